@@ -72,7 +72,7 @@ func main() {
 	flag.StringVar(&opts.ddConfigPath, "ddconfig", "/etc/dd-agent/datadog.conf", "Path to dd-agent config")
 	flag.StringVar(&opts.configPath, "config", "/etc/dd-agent/dd-process-agent.ini", "DEPRECATED: Path to legacy config file. Prefer -ddconfig to point to the dd-agent config")
 	flag.BoolVar(&opts.version, "version", false, "Print the version and exit")
-	flag.StringVar(&opts.check, "check", "", "Run a specific check and print the results. Choose from: process, connections, realtime")
+	flag.StringVar(&opts.check, "check", "", "Run a specific check and print the results. Choose from: process, connections, container, realtime")
 	flag.Parse()
 
 	// Set up a default config before parsing config so we log errors nicely.
@@ -145,17 +145,60 @@ func main() {
 		return
 	}
 	cl.run()
+	if c := docker.GetCollector(); c != nil {
+		c.Close()
+	}
 }
 
 func initMetadataProviders(cfg *config.AgentConfig) {
-	if err := docker.InitDockerUtil(&docker.Config{
-		CacheDuration:  cfg.ContainerCacheDuration,
-		CollectNetwork: cfg.CollectDockerNetwork,
-		Whitelist:      cfg.ContainerWhitelist,
-		Blacklist:      cfg.ContainerBlacklist,
-	}); err != nil && err != docker.ErrDockerNotAvailable {
+	dockerCfg := &docker.Config{
+		CacheDuration:              cfg.ContainerCacheDuration,
+		StatWorkers:                cfg.ContainerStatWorkers,
+		CollectionDeadline:         cfg.ContainerCollectionDeadline,
+		MaxInspectsPerCycle:        cfg.DockerMaxInspectsPerCycle,
+		MinUptime:                  cfg.ContainerMinUptime,
+		CreatedAfter:               cfg.ContainerCreatedAfter,
+		CreatedBefore:              cfg.ContainerCreatedBefore,
+		MaxContainers:              cfg.MaxContainers,
+		CollectNetwork:             cfg.CollectDockerNetwork,
+		Whitelist:                  cfg.ContainerWhitelist,
+		Blacklist:                  cfg.ContainerBlacklist,
+		WhitelistMode:              cfg.ContainerWhitelistMode,
+		NameNormalization:          cfg.ContainerNameNormalization,
+		NameReplacement:            cfg.ContainerNameReplacement,
+		LabelsAsTags:               cfg.ContainerLabelsAsTags,
+		AnnotationLabels:           cfg.ContainerAnnotationLabels,
+		CgroupRoot:                 cfg.CgroupRoot,
+		ExcludePauseContainer:      cfg.ExcludePauseContainer,
+		CollectHealthcheckConfig:   cfg.CollectDockerHealthcheck,
+		CollectDiskStats:           cfg.CollectDockerDiskStats,
+		CollectNetworkPerInterface: cfg.CollectDockerNetworkPerInterface,
+		CollectRestartCount:        cfg.CollectDockerRestartCount,
+		WatchEvents:                cfg.WatchDockerEvents,
+		BackgroundRefresh:          cfg.DockerBackgroundRefresh,
+		RefreshInterval:            cfg.DockerRefreshInterval,
+		IncludeStopped:             cfg.CollectStoppedContainers,
+		MetadataOnly:               cfg.ContainerMetadataOnly,
+		UseDockerStatsAPI:          cfg.UseDockerStatsAPI,
+		UseContainerdMetrics:       cfg.UseContainerdMetrics,
+		NoProxy:                    cfg.NoProxyDocker,
+		Statsd:                     statsd.Client,
+	}
+	if err := docker.InitDockerUtil(dockerCfg); err == docker.ErrDockerNotAvailable {
+		// Nodes without a Docker daemon may still run containerd directly.
+		if err := docker.InitContainerdUtil(dockerCfg); err != nil && err != docker.ErrContainerdNotAvailable {
+			log.Errorf("unable to initialize containerd collection: %s", err)
+		}
+	} else if err != nil {
 		log.Errorf("unable to initialize docker collection: %s", err)
 	}
+	if len(cfg.DockerEndpoints) > 0 {
+		// The containers of the endpoints are collected in place of the
+		// local ones.
+		if err := docker.InitEndpointsCollector(dockerCfg, cfg.DockerEndpoints); err != nil {
+			log.Errorf("unable to initialize docker endpoints collection: %s", err)
+		}
+	}
 
 	if err := kubernetes.InitKubeUtil(&kubernetes.Config{
 		KubeletHost:      cfg.KubernetesKubeletHost,
@@ -200,6 +243,9 @@ func debugCheckResults(cfg *config.AgentConfig, check string) error {
 	for _, ch := range checks.All {
 		if ch.Name() == check {
 			ch.Init(cfg, sysInfo)
+			if ch == checks.Container {
+				return printContainers(cfg)
+			}
 			return printResults(cfg, ch)
 		}
 		names = append(names, ch.Name())
@@ -233,3 +279,21 @@ func printResults(cfg *config.AgentConfig, ch checks.Check) error {
 	}
 	return nil
 }
+
+func printContainers(cfg *config.AgentConfig) error {
+	containers, err := checks.Container.RunOnce(cfg, 1*time.Second)
+	if err != nil {
+		return fmt.Errorf("collection error: %s", err)
+	}
+
+	fmt.Printf("-----------------------------\n\n")
+	fmt.Printf("\nResults for check %s\n", checks.Container.Name())
+	fmt.Printf("-----------------------------\n\n")
+
+	b, err := json.MarshalIndent(containers, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal error: %s", err)
+	}
+	fmt.Println(string(b))
+	return nil
+}
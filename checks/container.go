@@ -4,6 +4,7 @@ import (
 	"runtime"
 	"time"
 
+	agentpayload "github.com/DataDog/agent-payload/gogen"
 	"github.com/DataDog/gopsutil/cpu"
 	log "github.com/cihub/seelog"
 
@@ -18,12 +19,23 @@ import (
 // Container is a singleton ContainerCheck.
 var Container = &ContainerCheck{}
 
+// nowFunc is the clock used by the container checks and their rates, tests
+// override it to get deterministic intervals.
+var nowFunc = time.Now
+
+// collectorFunc returns the collector of the container checks. It is called
+// on every run since the global collector can be replaced after Init, e.g.
+// on reconnection. Tests override it with a fake collector.
+var collectorFunc = docker.GetCollector
+
 // ContainerCheck is a check that returns container metadata and stats.
 type ContainerCheck struct {
 	sysInfo        *model.SystemInfo
 	lastCPUTime    cpu.TimesStat
 	lastContainers []*docker.Container
 	lastRun        time.Time
+	lastHealthByID map[string]string
+	restartsByID   map[string][]time.Time
 }
 
 // Init initializes a ContainerCheck instance.
@@ -43,12 +55,12 @@ func (c *ContainerCheck) RealTime() bool { return false }
 // Run runs the ContainerCheck to collect a list of running containers and the
 // stats for each container.
 func (c *ContainerCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.MessageBody, error) {
-	start := time.Now()
+	start := nowFunc()
 	cpuTimes, err := cpu.Times(false)
 	if err != nil {
 		return nil, err
 	}
-	containers, err := docker.AllContainers()
+	containers, err := docker.CollectContainers(collectorFunc())
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +69,8 @@ func (c *ContainerCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.Me
 	if c.lastContainers == nil {
 		c.lastContainers = containers
 		c.lastCPUTime = cpuTimes[0]
-		c.lastRun = time.Now()
+		c.lastRun = nowFunc()
+		_, c.lastHealthByID = healthEvents(containers, nil, c.lastRun)
 		return nil, nil
 	}
 
@@ -65,15 +78,22 @@ func (c *ContainerCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.Me
 	ecsMeta := ecs.GetMetadata()
 	kubeMeta := kubernetes.GetMetadata()
 
-	groupSize := len(containers) / cfg.ProcLimit
-	if len(containers) != cfg.ProcLimit {
-		groupSize++
-	}
+	events, healthByID := healthEvents(containers, c.lastHealthByID, start)
+	crashLooping, restartsByID := crashLoops(containers, c.lastContainers, c.restartsByID, start, cfg.CrashLoopWindow, cfg.CrashLoopThreshold)
+
+	created, destroyed := containerChurn(containers, c.lastContainers)
+
+	groupSize := containerGroupSize(len(containers), cfg.ProcLimit)
 	chunked := fmtContainers(containers, c.lastContainers,
-		cpuTimes[0], c.lastCPUTime, c.lastRun, groupSize)
+		cpuTimes[0], c.lastCPUTime, c.lastRun, groupSize, totalMemory(c.sysInfo), numCPUs(c.sysInfo), kubeMeta, ecsMeta)
+	for _, chunk := range chunked {
+		for _, ctr := range chunk {
+			ctr.CrashLooping = crashLooping[ctr.Id]
+		}
+	}
 	messages := make([]model.MessageBody, 0, groupSize)
 	for i := 0; i < groupSize; i++ {
-		messages = append(messages, &model.CollectorContainer{
+		msg := &model.CollectorContainer{
 			HostName:   cfg.HostName,
 			Info:       c.sysInfo,
 			Containers: chunked[i],
@@ -81,25 +101,267 @@ func (c *ContainerCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.Me
 			GroupSize:  int32(groupSize),
 			Kubernetes: kubeMeta,
 			Ecs:        ecsMeta,
-		})
+		}
+		if i == 0 {
+			msg.HealthEvents = events
+		}
+		messages = append(messages, msg)
 	}
 
 	c.lastCPUTime = cpuTimes[0]
 	c.lastContainers = containers
-	c.lastRun = time.Now()
+	c.lastRun = nowFunc()
+	c.lastHealthByID = healthByID
+	c.restartsByID = restartsByID
 
 	statsd.Client.Gauge("datadog.process.containers.count", float64(len(containers)), []string{}, 1)
-	log.Infof("collected containers in %s", time.Now().Sub(start))
+	statsd.Client.Count("datadog.process.container.stat_errors", statErrors(containers), []string{}, 1)
+	statsd.Client.Count("datadog.process.containers.created", created, []string{}, 1)
+	statsd.Client.Count("datadog.process.containers.destroyed", destroyed, []string{}, 1)
+	for tag, count := range containersByAge(containers) {
+		statsd.Client.Gauge("datadog.process.containers.by_age", float64(count), []string{tag}, 1)
+	}
+	for tag, count := range containersByState(containers) {
+		statsd.Client.Gauge("datadog.process.containers.by_state", float64(count), []string{tag}, 1)
+	}
+	log.Infof("collected containers in %s", nowFunc().Sub(start))
 	return messages, nil
 }
 
+// RunOnce collects the containers twice, interval apart, and returns their
+// formatted stats so the rates are populated without going through the
+// skipped first run of Run. It leaves the state used by Run untouched and is
+// meant for debugging the configuration.
+func (c *ContainerCheck) RunOnce(cfg *config.AgentConfig, interval time.Duration) ([]*model.Container, error) {
+	lastCPUTimes, err := cpu.Times(false)
+	if err != nil {
+		return nil, err
+	}
+	lastContainers, err := docker.CollectContainers(collectorFunc())
+	if err != nil {
+		return nil, err
+	}
+	lastRun := nowFunc()
+
+	time.Sleep(interval)
+
+	cpuTimes, err := cpu.Times(false)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := docker.CollectContainers(collectorFunc())
+	if err != nil {
+		return nil, err
+	}
+
+	chunked := fmtContainers(containers, lastContainers,
+		cpuTimes[0], lastCPUTimes[0], lastRun, 1, totalMemory(c.sysInfo), numCPUs(c.sysInfo), kubernetes.GetMetadata(), ecs.GetMetadata())
+	return chunked[0], nil
+}
+
+// crashLoops tracks the restarts of the containers, from the increase of their
+// RestartCount since lastContainers, and returns the containers which
+// restarted more than threshold times within window. restartsByID holds the
+// times of the restarts within window by container ID, as returned by the
+// previous call. Restarts in a same collection are all counted at now. The
+// previous containers and restarts are matched by short ID too.
+func crashLoops(
+	containers, lastContainers []*docker.Container,
+	restartsByID map[string][]time.Time,
+	now time.Time,
+	window time.Duration,
+	threshold int,
+) (map[string]bool, map[string][]time.Time) {
+	ids := containerIDs(containers)
+	lastByID := make(map[string]*docker.Container, len(lastContainers))
+	for _, c := range lastContainers {
+		lastByID[resolveID(c.ID, ids)] = c
+	}
+	lastRestartsByID := make(map[string][]time.Time, len(restartsByID))
+	for id, restarts := range restartsByID {
+		id = resolveID(id, ids)
+		lastRestartsByID[id] = append(lastRestartsByID[id], restarts...)
+	}
+	crashLooping := make(map[string]bool)
+	newRestartsByID := make(map[string][]time.Time)
+	for _, ctr := range containers {
+		var restarts []time.Time
+		for _, t := range lastRestartsByID[ctr.ID] {
+			if now.Sub(t) < window {
+				restarts = append(restarts, t)
+			}
+		}
+		if last, ok := lastByID[ctr.ID]; ok {
+			for i := last.RestartCount; i < ctr.RestartCount; i++ {
+				restarts = append(restarts, now)
+			}
+		}
+		if len(restarts) > 0 {
+			newRestartsByID[ctr.ID] = restarts
+		}
+		if threshold > 0 && len(restarts) > threshold {
+			crashLooping[ctr.ID] = true
+		}
+	}
+	return crashLooping, newRestartsByID
+}
+
+// containerIDs returns the set of the IDs of the containers.
+func containerIDs(containers []*docker.Container) map[string]struct{} {
+	ids := make(map[string]struct{}, len(containers))
+	for _, c := range containers {
+		ids[c.ID] = struct{}{}
+	}
+	return ids
+}
+
+// resolveID returns the ID in ids of which id is the short ID, id itself if
+// it matches none or several of them.
+func resolveID(id string, ids map[string]struct{}) string {
+	if full, err := docker.ResolveContainerID(id, ids); err == nil {
+		return full
+	}
+	return id
+}
+
+// healthEvents returns the health transitions of the containers since the
+// previous collection, whose health by container ID is lastHealthByID, and
+// the health by ID to compare the next collection with. Containers which
+// are gone are dropped.
+func healthEvents(
+	containers []*docker.Container,
+	lastHealthByID map[string]string,
+	now time.Time,
+) ([]*model.ContainerHealthEvent, map[string]string) {
+	var events []*model.ContainerHealthEvent
+	healthByID := make(map[string]string, len(containers))
+	for _, ctr := range containers {
+		healthByID[ctr.ID] = ctr.Health
+		last, ok := lastHealthByID[ctr.ID]
+		if !ok || last == ctr.Health {
+			continue
+		}
+		events = append(events, &model.ContainerHealthEvent{
+			Id:        ctr.ID,
+			Name:      ctr.Name,
+			OldHealth: model.ContainerHealth(model.ContainerHealth_value[last]),
+			NewHealth: model.ContainerHealth(model.ContainerHealth_value[ctr.Health]),
+			Timestamp: now.Unix(),
+		})
+	}
+	return events, healthByID
+}
+
+// statErrors returns the number of cgroup stats that failed to be read across all containers.
+func statErrors(containers []*docker.Container) int64 {
+	var errs int64
+	for _, ctr := range containers {
+		errs += int64(ctr.StatErrors)
+	}
+	return errs
+}
+
+// containerChurn returns the number of containers created and destroyed
+// since the last collection, by container ID.
+func containerChurn(containers, lastContainers []*docker.Container) (created, destroyed int64) {
+	lastByID := make(map[string]struct{}, len(lastContainers))
+	for _, c := range lastContainers {
+		lastByID[c.ID] = struct{}{}
+	}
+	for _, c := range containers {
+		if _, ok := lastByID[c.ID]; ok {
+			delete(lastByID, c.ID)
+		} else {
+			created++
+		}
+	}
+	return created, int64(len(lastByID))
+}
+
+// The upper bounds of the container age buckets, the last bucket has none.
+const (
+	ageBucketMinute = time.Minute
+	ageBucketHour   = time.Hour
+	ageBucketDay    = 24 * time.Hour
+	ageBucketWeek   = 7 * 24 * time.Hour
+)
+
+// ageBuckets are the age buckets with their tag, by increasing bound.
+var ageBuckets = []struct {
+	max time.Duration
+	tag string
+}{
+	{ageBucketMinute, "age:lt_1m"},
+	{ageBucketHour, "age:lt_1h"},
+	{ageBucketDay, "age:lt_1d"},
+	{ageBucketWeek, "age:lt_1w"},
+}
+
+// containersByAge returns the number of running containers in each age
+// bucket, from their uptime, keyed by the tag of the bucket. All the buckets
+// are returned so empty ones are reported as 0.
+func containersByAge(containers []*docker.Container) map[string]int64 {
+	counts := map[string]int64{"age:gte_1w": 0}
+	for _, b := range ageBuckets {
+		counts[b.tag] = 0
+	}
+	for _, ctr := range containers {
+		if docker.IsStopped(ctr.State) {
+			continue
+		}
+		age := time.Duration(ctr.Uptime) * time.Second
+		tag := "age:gte_1w"
+		for _, b := range ageBuckets {
+			if age < b.max {
+				tag = b.tag
+				break
+			}
+		}
+		counts[tag]++
+	}
+	return counts
+}
+
+// containersByState returns the number of containers in each state of the
+// ContainerState enum, keyed by their state:<state> tag. The states unknown
+// to the enum are counted as unknown. All the states are returned so the
+// ones without containers anymore are reported as 0.
+func containersByState(containers []*docker.Container) map[string]int64 {
+	counts := make(map[string]int64, len(model.ContainerState_name))
+	for _, name := range model.ContainerState_name {
+		counts["state:"+name] = 0
+	}
+	for _, ctr := range containers {
+		state := model.ContainerState(model.ContainerState_value[ctr.State])
+		counts["state:"+state.String()]++
+	}
+	return counts
+}
+
+// containerGroupSize returns the number of messages needed to send
+// numContainers containers with at most limit containers per message.
+// We always send at least one message, even without containers.
+func containerGroupSize(numContainers, limit int) int {
+	groupSize := (numContainers + limit - 1) / limit
+	if groupSize < 1 {
+		groupSize = 1
+	}
+	return groupSize
+}
+
 // fmtContainers formats and chunks the containers into a slice of chunks using a specific
-// number of chunks. len(result) MUST EQUAL chunks.
+// number of chunks. len(result) MUST EQUAL chunks. The pod and ECS task of
+// each container are resolved with kubeMeta and ecsMeta which may be nil
+// outside of Kubernetes and ECS.
 func fmtContainers(
 	containers, lastContainers []*docker.Container,
 	syst2, syst1 cpu.TimesStat,
 	lastRun time.Time,
 	chunks int,
+	totalMemory uint64,
+	numCPUs int,
+	kubeMeta *agentpayload.KubeMetadataPayload,
+	ecsMeta *agentpayload.ECSMetadataPayload,
 ) [][]*model.Container {
 	lastByID := make(map[string]*docker.Container, len(containers))
 	for _, c := range lastContainers {
@@ -116,30 +378,80 @@ func fmtContainers(
 			// Set to an empty container so rate calculations work and use defaults.
 			lastCtr = docker.NullContainer
 		}
+		ctr, lastCtr = withNullStats(ctr), withNullStats(lastCtr)
+		if ok {
+			lastCtr = rateBase(ctr, lastCtr)
+		}
 
-		cpus := runtime.NumCPU()
+		cpus := containerCPUs(ctr, numCPUs)
+		podName, podNamespace, podUID := kubernetes.PodForContainer(kubeMeta, ctr)
+		taskArn, taskFamily := ecs.TaskForContainer(ecsMeta, ctr)
+		topDevice, topRbps, topWbps := topIODevice(ctr.IO, lastCtr.IO, lastRun)
+		mounts, binds, volumes := ctr.MountCounts()
 		chunk = append(chunk, &model.Container{
-			Type:        ctr.Type,
-			Name:        ctr.Name,
-			Id:          ctr.ID,
-			Image:       ctr.Image,
-			CpuLimit:    float32(ctr.CPULimit),
-			UserPct:     calculateCtrPct(ctr.CPU.User, lastCtr.CPU.User, cpus, lastRun),
-			SystemPct:   calculateCtrPct(ctr.CPU.System, lastCtr.CPU.System, cpus, lastRun),
-			TotalPct:    calculateCtrPct(ctr.CPU.User+ctr.CPU.System, lastCtr.CPU.User+lastCtr.CPU.System, cpus, lastRun),
-			MemoryLimit: ctr.MemLimit,
-			MemRss:      ctr.Memory.RSS,
-			MemCache:    ctr.Memory.Cache,
-			Created:     ctr.Created,
-			State:       model.ContainerState(model.ContainerState_value[ctr.State]),
-			Health:      model.ContainerHealth(model.ContainerHealth_value[ctr.Health]),
-			Rbps:        calculateRate(ctr.IO.ReadBytes, lastCtr.IO.ReadBytes, lastRun),
-			Wbps:        calculateRate(ctr.IO.WriteBytes, lastCtr.IO.WriteBytes, lastRun),
-			NetRcvdPs:   calculateRate(ctr.Network.PacketsRcvd, lastCtr.Network.PacketsRcvd, lastRun),
-			NetSentPs:   calculateRate(ctr.Network.PacketsSent, lastCtr.Network.PacketsSent, lastRun),
-			NetRcvdBps:  calculateRate(ctr.Network.BytesRcvd, lastCtr.Network.BytesRcvd, lastRun),
-			NetSentBps:  calculateRate(ctr.Network.BytesSent, lastCtr.Network.BytesSent, lastRun),
-			StartedAt:   ctr.StartedAt,
+			Type:                ctr.Type,
+			Name:                ctr.Name,
+			Id:                  ctr.ID,
+			Image:               ctr.Image,
+			CpuLimit:            float32(ctr.CPULimit),
+			UserPct:             calculateCtrPct(ctr.CPU.User, lastCtr.CPU.User, cpus, lastRun),
+			SystemPct:           calculateCtrPct(ctr.CPU.System, lastCtr.CPU.System, cpus, lastRun),
+			TotalPct:            calculateCtrPct(ctr.CPU.User+ctr.CPU.System, lastCtr.CPU.User+lastCtr.CPU.System, cpus, lastRun),
+			MemoryLimit:         memLimit(ctr, totalMemory),
+			MemRss:              ctr.Memory.RSS,
+			MemCache:            ctr.Memory.Cache,
+			MemSwap:             ctr.Memory.Swap,
+			MemWorkingSet:       ctr.Memory.WorkingSet,
+			Created:             ctr.Created,
+			State:               model.ContainerState(model.ContainerState_value[ctr.State]),
+			Health:              model.ContainerHealth(model.ContainerHealth_value[ctr.Health]),
+			Rbps:                calculateRate(ctr.IO.ReadBytes, lastCtr.IO.ReadBytes, lastRun),
+			Wbps:                calculateRate(ctr.IO.WriteBytes, lastCtr.IO.WriteBytes, lastRun),
+			NetRcvdPs:           calculateRate(ctr.Network.PacketsRcvd, lastCtr.Network.PacketsRcvd, lastRun),
+			NetSentPs:           calculateRate(ctr.Network.PacketsSent, lastCtr.Network.PacketsSent, lastRun),
+			NetRcvdBps:          calculateRate(ctr.Network.BytesRcvd, lastCtr.Network.BytesRcvd, lastRun),
+			NetSentBps:          calculateRate(ctr.Network.BytesSent, lastCtr.Network.BytesSent, lastRun),
+			StartedAt:           ctr.StartedAt,
+			SizeRw:              ctr.SizeRw,
+			SizeRootFs:          ctr.SizeRootFs,
+			RestartCount:        ctr.RestartCount,
+			PodName:             podName,
+			PodNamespace:        podNamespace,
+			PodUid:              podUID,
+			Uptime:              ctr.Uptime,
+			CpuNrThrottled:      ctr.CPU.NrThrottled,
+			CpuThrottledTime:    ctr.CPU.ThrottledTime,
+			TopIoDevice:         topDevice,
+			TopIoDeviceRbps:     topRbps,
+			TopIoDeviceWbps:     topWbps,
+			ComposeProject:      ctr.ComposeProject,
+			ComposeService:      ctr.ComposeService,
+			HealthFailingStreak: ctr.HealthFailingStreak,
+			MemOomKills:         ctr.Memory.OOMKills,
+			Command:             ctr.Command,
+			ImageTag:            ctr.ImageTag,
+			EcsTaskArn:          taskArn,
+			EcsTaskFamily:       taskFamily,
+			Tags:                ctr.Tags,
+			LogDriver:           ctr.LogDriver,
+			PidsCurrent:         ctr.PidsCurrent,
+			PidsLimit:           ctr.PidsLimit,
+			Privileged:          ctr.Privileged,
+			GpuCount:            ctr.GpuCount,
+			GpuVendor:           ctr.GpuVendor,
+			Endpoint:            ctr.Endpoint,
+			MemoryPressure:      float32(ctr.Memory.MemoryPressure),
+			CpuPressure:         float32(ctr.CPU.CPUPressure),
+			IoPressure:          float32(ctr.IO.IOPressure),
+			MountCount:          int32(mounts),
+			BindMountCount:      int32(binds),
+			VolumeMountCount:    int32(volumes),
+			IpAddress:           ctr.PrimaryIP(),
+			MemReservation:      ctr.MemReservation,
+			CpuShares:           ctr.CpuShares,
+			Annotations:         containerAnnotations(ctr.Annotations),
+			ExitCode:            ctr.ExitCode,
+			OomKilled:           ctr.OOMKilled,
 		})
 
 		if len(chunk) == perChunk {
@@ -154,14 +466,140 @@ func fmtContainers(
 	return chunked
 }
 
+// withNullStats returns the container with its missing stats replaced by
+// the ones of NullContainer so they can always be dereferenced. The
+// container is copied rather than modified if any stat is missing.
+func withNullStats(ctr *docker.Container) *docker.Container {
+	if ctr.CPU != nil && ctr.Memory != nil && ctr.IO != nil && ctr.Network != nil {
+		return ctr
+	}
+	c := *ctr
+	if c.CPU == nil {
+		c.CPU = docker.NullContainer.CPU
+	}
+	if c.Memory == nil {
+		c.Memory = docker.NullContainer.Memory
+	}
+	if c.IO == nil {
+		c.IO = docker.NullContainer.IO
+	}
+	if c.Network == nil {
+		c.Network = docker.NullContainer.Network
+	}
+	return &c
+}
+
+// rateBase returns the container to compute the rates of ctr against: lastCtr
+// with the stats that are the NullContainer placeholders of a failed read on
+// either side replaced by the ones of ctr, so their rates are 0 rather than a
+// spike computed against a zeroed counter.
+func rateBase(ctr, lastCtr *docker.Container) *docker.Container {
+	null := docker.NullContainer
+	nullCPU := ctr.CPU == null.CPU || lastCtr.CPU == null.CPU
+	nullIO := ctr.IO == null.IO || lastCtr.IO == null.IO
+	nullNetwork := ctr.Network == null.Network || lastCtr.Network == null.Network
+	if !nullCPU && !nullIO && !nullNetwork {
+		return lastCtr
+	}
+	c := *lastCtr
+	if nullCPU {
+		c.CPU = ctr.CPU
+	}
+	if nullIO {
+		c.IO = ctr.IO
+	}
+	if nullNetwork {
+		c.Network = ctr.Network
+	}
+	return &c
+}
+
+// topIODevice returns the block device with the highest combined read and
+// write throughput since the last run, along with its rates.
+func topIODevice(cur, prev *docker.CgroupIOStat, before time.Time) (string, float32, float32) {
+	var top string
+	var topRbps, topWbps float32
+	for name, dev := range cur.Devices {
+		last := prev.Devices[name]
+		// Counters are reset when the cgroup is recreated, skip the rates
+		// for this run rather than reporting an overflow.
+		if dev.ReadBytes < last.ReadBytes || dev.WriteBytes < last.WriteBytes {
+			last = dev
+		}
+		rbps := calculateRate(dev.ReadBytes, last.ReadBytes, before)
+		wbps := calculateRate(dev.WriteBytes, last.WriteBytes, before)
+		// Ties are broken by name so the result is stable.
+		if top == "" || rbps+wbps > topRbps+topWbps || (rbps+wbps == topRbps+topWbps && name < top) {
+			top, topRbps, topWbps = name, rbps, wbps
+		}
+	}
+	return top, topRbps, topWbps
+}
+
+// totalMemory returns the physical memory of the host, 0 if unknown.
+func totalMemory(info *model.SystemInfo) uint64 {
+	if info == nil || info.TotalMemory < 0 {
+		return 0
+	}
+	return uint64(info.TotalMemory)
+}
+
+// memLimit returns the effective memory limit of a container. Containers
+// without a limit, or with one above the memory of the host, are bound by
+// the host's memory.
+func memLimit(ctr *docker.Container, totalMemory uint64) uint64 {
+	if totalMemory > 0 && (ctr.MemLimit == 0 || ctr.MemLimit > totalMemory) {
+		return totalMemory
+	}
+	return ctr.MemLimit
+}
+
+// isLinux is whether the system info has an entry per logical CPU, overridden
+// in tests.
+var isLinux = runtime.GOOS == "linux"
+
+// numCPUs returns the number of logical CPUs of the host. The system info has
+// an entry per logical CPU on Linux only, elsewhere an entry per socket with
+// its physical cores, so the ones available to the Go runtime are used.
+func numCPUs(info *model.SystemInfo) int {
+	if isLinux && info != nil && len(info.Cpus) > 0 {
+		return len(info.Cpus)
+	}
+	return runtime.NumCPU()
+}
+
+// containerCPUs returns the number of CPUs the container may run on, the ones
+// of its cpuset if known, hostCPUs otherwise.
+func containerCPUs(ctr *docker.Container, hostCPUs int) int {
+	if ctr.CpusetCount > 0 {
+		return ctr.CpusetCount
+	}
+	return hostCPUs
+}
+
+// containerAnnotations converts the annotations of a container to the ones of
+// the payload, nil if it has none.
+func containerAnnotations(annotations map[string]int) map[string]int64 {
+	if len(annotations) == 0 {
+		return nil
+	}
+	ret := make(map[string]int64, len(annotations))
+	for k, v := range annotations {
+		ret[k] = int64(v)
+	}
+	return ret
+}
+
 func calculateCtrPct(cur, prev uint64, numCPU int, before time.Time) float32 {
-	now := time.Now()
-	diff := now.Unix() - before.Unix()
-	if before.IsZero() || diff <= 0 {
+	// Use the actual elapsed duration rather than a difference of Unix seconds
+	// so the delta isn't quantized to whole seconds.
+	diff := nowFunc().Sub(before).Seconds()
+	// The counters are reset when the cgroup is recreated.
+	if before.IsZero() || diff <= 0 || cur < prev {
 		return 0
 	}
 
-	overalPct := float32(cur-prev) / float32(diff)
+	overalPct := float32(float64(cur-prev) / diff)
 	// Sometimes we get values that don't make sense, so we clamp to 100%
 	if overalPct > 100 {
 		overalPct = 100
@@ -1,7 +1,6 @@
 package checks
 
 import (
-	"runtime"
 	"time"
 
 	"github.com/DataDog/gopsutil/cpu"
@@ -42,7 +41,7 @@ func (r *RTContainerCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.
 	if err != nil {
 		return nil, err
 	}
-	containers, err := docker.AllContainers()
+	containers, err := docker.CollectContainers(collectorFunc())
 	if err != nil {
 		return nil, err
 	}
@@ -51,22 +50,19 @@ func (r *RTContainerCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.
 	if r.lastContainers == nil {
 		r.lastContainers = containers
 		r.lastCPUTime = cpuTimes[0]
-		r.lastRun = time.Now()
+		r.lastRun = nowFunc()
 		return nil, nil
 	}
 
-	groupSize := len(containers) / cfg.ProcLimit
-	if len(containers) != cfg.ProcLimit {
-		groupSize++
-	}
+	groupSize := containerGroupSize(len(containers), cfg.ProcLimit)
 	chunked := fmtContainerStats(containers, r.lastContainers,
-		cpuTimes[0], r.lastCPUTime, r.lastRun, groupSize)
+		cpuTimes[0], r.lastCPUTime, r.lastRun, groupSize, totalMemory(r.sysInfo), numCPUs(r.sysInfo))
 	messages := make([]model.MessageBody, 0, groupSize)
 	for i := 0; i < groupSize; i++ {
 		messages = append(messages, &model.CollectorContainerRealTime{
 			HostName:    cfg.HostName,
 			Stats:       chunked[i],
-			NumCpus:     int32(runtime.NumCPU()),
+			NumCpus:     int32(numCPUs(r.sysInfo)),
 			TotalMemory: r.sysInfo.TotalMemory,
 			GroupId:     groupID,
 			GroupSize:   int32(groupSize),
@@ -75,7 +71,7 @@ func (r *RTContainerCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.
 
 	r.lastContainers = containers
 	r.lastCPUTime = cpuTimes[0]
-	r.lastRun = time.Now()
+	r.lastRun = nowFunc()
 
 	return messages, nil
 }
@@ -87,6 +83,8 @@ func fmtContainerStats(
 	syst2, syst1 cpu.TimesStat,
 	lastRun time.Time,
 	chunks int,
+	totalMemory uint64,
+	numCPUs int,
 ) [][]*model.ContainerStat {
 	lastByID := make(map[string]*docker.Container, len(containers))
 	for _, c := range lastContainers {
@@ -103,8 +101,12 @@ func fmtContainerStats(
 			// Set to an empty container so rate calculations work and use defaults.
 			lastCtr = docker.NullContainer
 		}
+		ctr, lastCtr = withNullStats(ctr), withNullStats(lastCtr)
+		if ok {
+			lastCtr = rateBase(ctr, lastCtr)
+		}
 
-		cpus := runtime.NumCPU()
+		cpus := containerCPUs(ctr, numCPUs)
 		chunk = append(chunk, &model.ContainerStat{
 			Id:         ctr.ID,
 			UserPct:    calculateCtrPct(ctr.CPU.User, lastCtr.CPU.User, cpus, lastRun),
@@ -113,7 +115,7 @@ func fmtContainerStats(
 			CpuLimit:   float32(ctr.CPULimit),
 			MemRss:     ctr.Memory.RSS,
 			MemCache:   ctr.Memory.Cache,
-			MemLimit:   ctr.MemLimit,
+			MemLimit:   memLimit(ctr, totalMemory),
 			Rbps:       calculateRate(ctr.IO.ReadBytes, lastCtr.IO.ReadBytes, lastRun),
 			Wbps:       calculateRate(ctr.IO.WriteBytes, lastCtr.IO.WriteBytes, lastRun),
 			NetRcvdPs:  calculateRate(ctr.Network.PacketsRcvd, lastCtr.Network.PacketsRcvd, lastRun),
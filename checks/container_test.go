@@ -1,9 +1,13 @@
 package checks
 
 import (
+	"runtime"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/DataDog/datadog-process-agent/config"
+	"github.com/DataDog/datadog-process-agent/model"
 	"github.com/DataDog/datadog-process-agent/util/docker"
 	"github.com/DataDog/gopsutil/cpu"
 	"github.com/stretchr/testify/assert"
@@ -52,7 +56,7 @@ func TestContainerChunking(t *testing.T) {
 			expected: 2,
 		},
 	} {
-		chunked := fmtContainers(tc.cur, tc.last, syst2, syst1, lastRun, tc.chunks)
+		chunked := fmtContainers(tc.cur, tc.last, syst2, syst1, lastRun, tc.chunks, 0, 4, nil, nil)
 		assert.Len(t, chunked, tc.chunks, "len test %d", i)
 		total := 0
 		for _, c := range chunked {
@@ -60,7 +64,7 @@ func TestContainerChunking(t *testing.T) {
 		}
 		assert.Equal(t, tc.expected, total, "total test %d", i)
 
-		chunkedStat := fmtContainerStats(tc.cur, tc.last, syst2, syst1, lastRun, tc.chunks)
+		chunkedStat := fmtContainerStats(tc.cur, tc.last, syst2, syst1, lastRun, tc.chunks, 0, 4)
 		assert.Len(t, chunkedStat, tc.chunks, "len stat test %d", i)
 		total = 0
 		for _, c := range chunked {
@@ -82,3 +86,432 @@ func BenchmarkAllContainers(b *testing.B) {
 		docker.AllContainers()
 	}
 }
+
+func TestCalculateCtrPctSubSecond(t *testing.T) {
+	assert := assert.New(t)
+
+	// 775 ticks over 15.5s is exactly 50% of a single CPU.
+	before := time.Now().Add(-15500 * time.Millisecond)
+	pct := calculateCtrPct(775, 0, 1, before)
+	assert.InDelta(50.0, pct, 0.1)
+
+	// Truncating the interval to whole seconds would have reported ~51.7%.
+	truncated := float32(775) / float32(15)
+	assert.True(truncated-pct > 1, "expected %f to be more precise than %f", pct, truncated)
+
+	// Runs less than a second apart used to divide by zero and report 0%.
+	before = time.Now().Add(-500 * time.Millisecond)
+	pct = calculateCtrPct(25, 0, 1, before)
+	assert.InDelta(50.0, pct, 1)
+
+	// Unset or future start times are still guarded.
+	assert.Equal(float32(0), calculateCtrPct(25, 0, 1, time.Time{}))
+	assert.Equal(float32(0), calculateCtrPct(25, 0, 1, time.Now().Add(time.Second)))
+}
+
+func TestContainerRates(t *testing.T) {
+	assert := assert.New(t)
+	defer func(f func() time.Time) { nowFunc = f }(nowFunc)
+
+	before := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := before.Add(10 * time.Second)
+	nowFunc = func() time.Time { return now }
+
+	// 250 ticks over 10s is 25% of a CPU, scaled by the number of CPUs.
+	assert.Equal(float32(25), calculateCtrPct(250, 0, 1, before))
+	assert.Equal(float32(100), calculateCtrPct(500, 250, 4, before))
+	// Nonsensical deltas are clamped to 100% before the CPU scaling.
+	assert.Equal(float32(100), calculateCtrPct(5000, 0, 1, before))
+	assert.Equal(float32(200), calculateCtrPct(5000, 0, 2, before))
+
+	assert.Equal(float32(1024), calculateRate(10240, 0, before))
+	assert.Equal(float32(0), calculateRate(10240, 0, now))
+
+	cur, last := makeContainer("1"), makeContainer("1")
+	cur.CPU.User, last.CPU.User = 150, 100
+	cur.CPU.System, last.CPU.System = 60, 10
+	cur.IO.ReadBytes, last.IO.ReadBytes = 4096, 1024
+	cur.Network.BytesSent, last.Network.BytesSent = 20000, 10000
+	cur.Network.PacketsRcvd = 50
+
+	chunked := fmtContainers([]*docker.Container{cur}, []*docker.Container{last}, cpu.TimesStat{}, cpu.TimesStat{}, before, 1, 0, 4, nil, nil)
+	if assert.Len(chunked[0], 1) {
+		c := chunked[0][0]
+		cpus := float32(4)
+		assert.Equal(5*cpus, c.UserPct)
+		assert.Equal(5*cpus, c.SystemPct)
+		assert.Equal(10*cpus, c.TotalPct)
+		assert.Equal(float32(307.2), c.Rbps)
+		assert.Equal(float32(1000), c.NetSentBps)
+		assert.Equal(float32(5), c.NetRcvdPs)
+	}
+}
+
+func TestContainerRatesFailedReads(t *testing.T) {
+	assert := assert.New(t)
+	defer func(f func() time.Time) { nowFunc = f }(nowFunc)
+
+	before := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := before.Add(10 * time.Second)
+	nowFunc = func() time.Time { return now }
+
+	// Counters going backwards are reset rather than wrapped around.
+	assert.Equal(float32(0), calculateRate(0, 10240, before))
+	assert.Equal(float32(0), calculateCtrPct(0, 250, 4, before))
+
+	healthy := func(ticks, bytes uint64) *docker.Container {
+		c := makeContainer("1")
+		c.CPU.User = ticks
+		c.IO.ReadBytes = bytes
+		c.Network.BytesRcvd = bytes
+		return c
+	}
+	// The stats of a failed read are the NullContainer placeholders.
+	failed := makeContainer("1")
+	failed.CPU = docker.NullContainer.CPU
+	failed.IO = docker.NullContainer.IO
+	failed.Network = docker.NullContainer.Network
+
+	for i, tc := range []struct {
+		cur, last *docker.Container
+	}{
+		// The reads fail...
+		{failed, healthy(1000, 1<<30)},
+		// ...then recover.
+		{healthy(1100, 1<<30+1024), failed},
+	} {
+		chunked := fmtContainers([]*docker.Container{tc.cur}, []*docker.Container{tc.last}, cpu.TimesStat{}, cpu.TimesStat{}, before, 1, 0, 4, nil, nil)
+		if assert.Len(chunked[0], 1) {
+			c := chunked[0][0]
+			assert.Equal(float32(0), c.UserPct, "case %d", i)
+			assert.Equal(float32(0), c.TotalPct, "case %d", i)
+			assert.Equal(float32(0), c.Rbps, "case %d", i)
+			assert.Equal(float32(0), c.NetRcvdBps, "case %d", i)
+		}
+		stats := fmtContainerStats([]*docker.Container{tc.cur}, []*docker.Container{tc.last}, cpu.TimesStat{}, cpu.TimesStat{}, before, 1, 0, 4)
+		if assert.Len(stats[0], 1) {
+			assert.Equal(float32(0), stats[0][0].TotalPct, "case %d", i)
+			assert.Equal(float32(0), stats[0][0].Rbps, "case %d", i)
+		}
+	}
+
+	// The rates are back once both collections read the stats.
+	chunked := fmtContainers([]*docker.Container{healthy(1200, 1<<30+11264)}, []*docker.Container{healthy(1100, 1<<30+1024)}, cpu.TimesStat{}, cpu.TimesStat{}, before, 1, 0, 4, nil, nil)
+	if assert.Len(chunked[0], 1) {
+		assert.Equal(float32(40), chunked[0][0].UserPct)
+		assert.Equal(float32(1024), chunked[0][0].Rbps)
+	}
+}
+
+func TestContainerCPUs(t *testing.T) {
+	ctr := makeContainer("1")
+	assert.Equal(t, 8, containerCPUs(ctr, 8))
+	ctr.CpusetCount = 2
+	assert.Equal(t, 2, containerCPUs(ctr, 8))
+
+	defer func(linux bool) { isLinux = linux }(isLinux)
+	isLinux = true
+	assert.Equal(t, runtime.NumCPU(), numCPUs(nil))
+	assert.Equal(t, 2, numCPUs(&model.SystemInfo{Cpus: []*model.CPUInfo{{Number: 0, Cores: 1}, {Number: 1, Cores: 1}}}))
+	// The entries of the other systems are sockets with their physical cores.
+	isLinux = false
+	assert.Equal(t, runtime.NumCPU(), numCPUs(&model.SystemInfo{Cpus: []*model.CPUInfo{{Number: 0, Cores: 8}}}))
+}
+
+func TestContainerAnnotations(t *testing.T) {
+	assert.Nil(t, containerAnnotations(nil))
+	assert.Equal(t, map[string]int64{"scan.critical": 3, "scan.high": 0}, containerAnnotations(map[string]int{"scan.critical": 3, "scan.high": 0}))
+}
+
+func TestCrashLoops(t *testing.T) {
+	assert := assert.New(t)
+
+	ctr := func(id string, restarts int32) *docker.Container {
+		return &docker.Container{ID: id, RestartCount: restarts}
+	}
+	now := time.Now()
+	window := 10 * time.Minute
+
+	crashLooping, restarts := crashLoops([]*docker.Container{ctr("1", 2), ctr("2", 0)}, nil, nil, now, window, 3)
+	assert.Empty(crashLooping)
+	assert.Empty(restarts)
+
+	// Restarts are counted from the previous collection.
+	now = now.Add(time.Minute)
+	crashLooping, restarts = crashLoops([]*docker.Container{ctr("1", 4), ctr("2", 1)}, []*docker.Container{ctr("1", 2), ctr("2", 0)}, restarts, now, window, 3)
+	assert.Empty(crashLooping)
+	assert.Len(restarts["1"], 2)
+
+	now = now.Add(time.Minute)
+	crashLooping, restarts = crashLoops([]*docker.Container{ctr("1", 6), ctr("2", 1)}, []*docker.Container{ctr("1", 4), ctr("2", 1)}, restarts, now, window, 3)
+	assert.Equal(map[string]bool{"1": true}, crashLooping)
+	assert.Len(restarts["1"], 4)
+	assert.Len(restarts["2"], 1)
+
+	// The restarts older than the window are forgotten.
+	now = now.Add(9*time.Minute + 30*time.Second)
+	crashLooping, restarts = crashLoops([]*docker.Container{ctr("1", 6), ctr("2", 1)}, []*docker.Container{ctr("1", 6), ctr("2", 1)}, restarts, now, window, 3)
+	assert.Empty(crashLooping)
+	assert.Len(restarts["1"], 2)
+	assert.NotContains(restarts, "2")
+
+	// The previous containers and restarts are matched by short ID.
+	id := "a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419"
+	crashLooping, restarts = crashLoops([]*docker.Container{ctr(id, 8)}, []*docker.Container{ctr(id[:12], 6)}, map[string][]time.Time{id[:12]: {now, now}}, now, window, 3)
+	assert.Equal(map[string]bool{id: true}, crashLooping)
+	assert.Len(restarts[id], 4)
+}
+
+func TestContainersByAge(t *testing.T) {
+	ctr := func(state string, uptime time.Duration) *docker.Container {
+		return &docker.Container{State: state, Uptime: int64(uptime / time.Second)}
+	}
+	containers := []*docker.Container{
+		ctr("running", 10*time.Second),
+		ctr("running", 59*time.Minute),
+		ctr("paused", 2*time.Hour),
+		ctr("running", 6*24*time.Hour),
+		ctr("running", 30*24*time.Hour),
+		ctr("running", 7*24*time.Hour),
+		ctr("exited", 0),
+	}
+	assert.Equal(t, map[string]int64{
+		"age:lt_1m":  1,
+		"age:lt_1h":  1,
+		"age:lt_1d":  1,
+		"age:lt_1w":  1,
+		"age:gte_1w": 2,
+	}, containersByAge(containers))
+}
+
+func TestContainersByState(t *testing.T) {
+	containers := []*docker.Container{
+		{State: "running"},
+		{State: "running"},
+		{State: "exited"},
+		{State: "paused"},
+		{State: "removing"},
+	}
+	assert.Equal(t, map[string]int64{
+		"state:unknown":    1,
+		"state:created":    0,
+		"state:restarting": 0,
+		"state:running":    2,
+		"state:paused":     1,
+		"state:exited":     1,
+		"state:dead":       0,
+	}, containersByState(containers))
+}
+
+func TestMemLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	ctr := makeContainer("1")
+	assert.Equal(uint64(0), memLimit(ctr, 0))
+	assert.Equal(uint64(8<<30), memLimit(ctr, 8<<30))
+
+	ctr.MemLimit = 1 << 30
+	assert.Equal(uint64(1<<30), memLimit(ctr, 8<<30))
+	assert.Equal(uint64(1<<30), memLimit(ctr, 0))
+	ctr.MemLimit = 16 << 30
+	assert.Equal(uint64(8<<30), memLimit(ctr, 8<<30))
+
+	assert.Equal(uint64(0), totalMemory(nil))
+	assert.Equal(uint64(8<<30), totalMemory(&model.SystemInfo{TotalMemory: 8 << 30}))
+}
+
+func TestTopIODevice(t *testing.T) {
+	assert := assert.New(t)
+
+	before := time.Now().Add(-10 * time.Second)
+	prev := &docker.CgroupIOStat{Devices: map[string]docker.IODeviceStat{
+		"sda":  {ReadBytes: 1000, WriteBytes: 1000},
+		"sdb":  {ReadBytes: 1000, WriteBytes: 1000},
+		"dm-0": {ReadBytes: 5000, WriteBytes: 5000},
+	}}
+	cur := &docker.CgroupIOStat{Devices: map[string]docker.IODeviceStat{
+		"sda":  {ReadBytes: 2000, WriteBytes: 1000},
+		"sdb":  {ReadBytes: 1000, WriteBytes: 11000},
+		"dm-0": {ReadBytes: 0, WriteBytes: 0},
+	}}
+	name, rbps, wbps := topIODevice(cur, prev, before)
+	assert.Equal("sdb", name)
+	assert.Equal(float32(0), rbps)
+	assert.Equal(float32(1000), wbps)
+
+	name, _, _ = topIODevice(docker.NullContainer.IO, docker.NullContainer.IO, before)
+	assert.Equal("", name)
+}
+
+func TestContainerChurn(t *testing.T) {
+	assert := assert.New(t)
+
+	last := []*docker.Container{makeContainer("1"), makeContainer("2"), makeContainer("3")}
+	cur := []*docker.Container{makeContainer("2"), makeContainer("4"), makeContainer("5"), makeContainer("3")}
+	created, destroyed := containerChurn(cur, last)
+	assert.Equal(int64(2), created)
+	assert.Equal(int64(1), destroyed)
+
+	created, destroyed = containerChurn(cur, cur)
+	assert.Equal(int64(0), created)
+	assert.Equal(int64(0), destroyed)
+
+	created, destroyed = containerChurn(nil, last)
+	assert.Equal(int64(0), created)
+	assert.Equal(int64(3), destroyed)
+}
+
+func TestContainerGroupSize(t *testing.T) {
+	limit := 100
+	lastRun := time.Now().Add(-5 * time.Second)
+	syst1, syst2 := cpu.TimesStat{}, cpu.TimesStat{}
+
+	for i, tc := range []struct {
+		containers int
+		expected   int
+	}{
+		{0, 1},
+		{1, 1},
+		{limit, 1},
+		{limit + 1, 2},
+		{2 * limit, 2},
+		{3 * limit, 3},
+		{3*limit + 1, 4},
+	} {
+		groupSize := containerGroupSize(tc.containers, limit)
+		assert.Equal(t, tc.expected, groupSize, "group size test %d", i)
+
+		ctrs := make([]*docker.Container, 0, tc.containers)
+		for j := 0; j < tc.containers; j++ {
+			ctrs = append(ctrs, makeContainer(strconv.Itoa(j)))
+		}
+		// Every container must fit in the chunks.
+		chunked := fmtContainers(ctrs, ctrs, syst2, syst1, lastRun, groupSize, 0, 4, nil, nil)
+		assert.Len(t, chunked, groupSize, "len test %d", i)
+		total := 0
+		for _, c := range chunked {
+			total += len(c)
+		}
+		assert.Equal(t, tc.containers, total, "total test %d", i)
+	}
+}
+
+func TestContainerRunOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	// Without a container runtime there is nothing to report but the check
+	// state must not be primed either.
+	c := &ContainerCheck{}
+	containers, err := c.RunOnce(&config.AgentConfig{}, time.Millisecond)
+	assert.NoError(err)
+	assert.Empty(containers)
+	assert.Nil(c.lastContainers)
+}
+
+// fakeCollector is a docker.Collector serving canned containers.
+type fakeCollector struct {
+	containers []*docker.Container
+	calls      int
+}
+
+func (f *fakeCollector) AllContainers() ([]*docker.Container, error) {
+	f.calls++
+	return f.containers, nil
+}
+
+func (f *fakeCollector) GetHostname() (string, error) { return "host", nil }
+
+func (f *fakeCollector) Close() {}
+
+func TestRTContainerCollector(t *testing.T) {
+	assert := assert.New(t)
+
+	collector := &fakeCollector{containers: []*docker.Container{makeContainer("1"), makeContainer("2")}}
+	defer func(f func() docker.Collector) { collectorFunc = f }(collectorFunc)
+	collectorFunc = func() docker.Collector { return collector }
+	r := &RTContainerCheck{sysInfo: &model.SystemInfo{}}
+	cfg := &config.AgentConfig{ProcLimit: 10}
+
+	// The first run only primes the check.
+	msgs, err := r.Run(cfg, 1)
+	assert.NoError(err)
+	assert.Empty(msgs)
+
+	msgs, err = r.Run(cfg, 1)
+	assert.NoError(err)
+	assert.Equal(2, collector.calls)
+	if assert.Len(msgs, 1) {
+		assert.Len(msgs[0].(*model.CollectorContainerRealTime).Stats, 2)
+	}
+
+	// The collector replaced after Init, e.g. on reconnection, is used.
+	replaced := &fakeCollector{containers: []*docker.Container{makeContainer("1")}}
+	collectorFunc = func() docker.Collector { return replaced }
+	msgs, err = r.Run(cfg, 1)
+	assert.NoError(err)
+	assert.Equal(2, collector.calls)
+	assert.Equal(1, replaced.calls)
+	if assert.Len(msgs, 1) {
+		assert.Len(msgs[0].(*model.CollectorContainerRealTime).Stats, 1)
+	}
+}
+
+func TestHealthEvents(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Unix(1500000000, 0)
+
+	ctr := func(id, health string) *docker.Container {
+		return &docker.Container{ID: id, Name: "ctr-" + id, Health: health}
+	}
+
+	// Nothing to compare with on the first run.
+	events, healthByID := healthEvents([]*docker.Container{ctr("1", "healthy"), ctr("2", "starting")}, nil, now)
+	assert.Empty(events)
+	assert.Equal(map[string]string{"1": "healthy", "2": "starting"}, healthByID)
+
+	// New containers don't transition, gone ones are dropped.
+	events, healthByID = healthEvents([]*docker.Container{ctr("1", "unhealthy"), ctr("3", "healthy")}, healthByID, now)
+	assert.Equal([]*model.ContainerHealthEvent{{
+		Id:        "1",
+		Name:      "ctr-1",
+		OldHealth: model.ContainerHealth_healthy,
+		NewHealth: model.ContainerHealth_unhealthy,
+		Timestamp: 1500000000,
+	}}, events)
+	assert.Equal(map[string]string{"1": "unhealthy", "3": "healthy"}, healthByID)
+
+	// A container coming back gets no event.
+	events, _ = healthEvents([]*docker.Container{ctr("1", "unhealthy"), ctr("2", "healthy")}, healthByID, now)
+	assert.Empty(events)
+}
+
+func TestFmtContainersNilStats(t *testing.T) {
+	assert := assert.New(t)
+	lastRun := time.Now().Add(-5 * time.Second)
+	syst1, syst2 := cpu.TimesStat{}, cpu.TimesStat{}
+
+	ctr := makeContainer("1")
+	ctr.Network = nil
+	ctr.IO.ReadBytes = 5000
+	last := makeContainer("1")
+	last.CPU = nil
+	last.IO = nil
+
+	chunked := fmtContainers([]*docker.Container{ctr}, []*docker.Container{last}, syst2, syst1, lastRun, 1, 0, 4, nil, nil)
+	if assert.Len(chunked[0], 1) {
+		c := chunked[0][0]
+		assert.Equal(float32(0), c.NetRcvdBps)
+		assert.Equal(float32(0), c.NetSentPs)
+		assert.Equal(float32(0), c.UserPct)
+		// No rate against a missing stat.
+		assert.Equal(float32(0), c.Rbps)
+	}
+	// The containers are left untouched.
+	assert.Nil(ctr.Network)
+	assert.Nil(last.CPU)
+
+	stats := fmtContainerStats([]*docker.Container{ctr}, []*docker.Container{last}, syst2, syst1, lastRun, 1, 0, 4)
+	if assert.Len(stats[0], 1) {
+		assert.Equal(float32(0), stats[0][0].NetRcvdBps)
+	}
+}
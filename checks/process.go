@@ -89,7 +89,7 @@ func (p *ProcessCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.Mess
 	}
 	groupSize := len(chunkedProcs)
 	chunkedContainers := fmtContainers(containers, p.lastContainers,
-		cpuTimes[0], p.lastCPUTime, p.lastRun, groupSize)
+		cpuTimes[0], p.lastCPUTime, p.lastRun, groupSize, totalMemory(p.sysInfo), numCPUs(p.sysInfo), kubeMeta, ecsMeta)
 	messages := make([]model.MessageBody, 0, groupSize)
 	for i := 0; i < groupSize; i++ {
 		messages = append(messages, &model.CollectorProc{
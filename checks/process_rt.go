@@ -71,7 +71,7 @@ func (r *RTProcessCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.Me
 		containers, cpuTimes[0], r.lastCPUTime, r.lastRun)
 	groupSize := len(chunkedStats)
 	chunkedCtrStats := fmtContainerStats(containers, r.lastContainers,
-		cpuTimes[0], r.lastCPUTime, r.lastRun, groupSize)
+		cpuTimes[0], r.lastCPUTime, r.lastRun, groupSize, totalMemory(r.sysInfo), numCPUs(r.sysInfo))
 	messages := make([]model.MessageBody, 0, groupSize)
 	for i := 0; i < groupSize; i++ {
 		messages = append(messages, &model.CollectorRealTime{
@@ -148,9 +148,10 @@ func fmtProcessStats(
 }
 
 func calculateRate(cur, prev uint64, before time.Time) float32 {
-	now := time.Now()
+	now := nowFunc()
 	diff := now.Unix() - before.Unix()
-	if before.IsZero() || diff <= 0 {
+	// A counter going backwards was reset, e.g. after a failed read.
+	if before.IsZero() || diff <= 0 || cur < prev {
 		return 0
 	}
 	return float32(cur-prev) / float32(diff)
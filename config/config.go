@@ -55,10 +55,39 @@ type AgentConfig struct {
 	CheckIntervals map[string]time.Duration
 
 	// Docker
-	ContainerBlacklist     []string
-	ContainerWhitelist     []string
-	CollectDockerNetwork   bool
-	ContainerCacheDuration time.Duration
+	ContainerBlacklist               []string
+	ContainerWhitelist               []string
+	ContainerWhitelistMode           string
+	ContainerNameNormalization       string
+	ContainerNameReplacement         string
+	ContainerLabelsAsTags            []string
+	ContainerAnnotationLabels        []string
+	CgroupRoot                       string
+	DockerEndpoints                  []string
+	NoProxyDocker                    bool
+	ExcludePauseContainer            bool
+	CollectDockerNetwork             bool
+	ContainerCacheDuration           time.Duration
+	ContainerMinUptime               time.Duration
+	ContainerCreatedAfter            time.Time
+	ContainerCreatedBefore           time.Time
+	MaxContainers                    int
+	ContainerStatWorkers             int
+	ContainerCollectionDeadline      time.Duration
+	DockerMaxInspectsPerCycle        int
+	CollectDockerHealthcheck         bool
+	CollectDockerDiskStats           bool
+	CollectDockerNetworkPerInterface bool
+	CollectDockerRestartCount        bool
+	CrashLoopWindow                  time.Duration
+	CrashLoopThreshold               int
+	WatchDockerEvents                bool
+	DockerBackgroundRefresh          bool
+	DockerRefreshInterval            time.Duration
+	CollectStoppedContainers         bool
+	ContainerMetadataOnly            bool
+	UseDockerStatsAPI                bool
+	UseContainerdMetrics             bool
 
 	// Kubernetes
 	CollectKubernetesMetadata  bool
@@ -96,7 +125,7 @@ func NewDefaultAgentConfig() *AgentConfig {
 	}
 	ac := &AgentConfig{
 		// We'll always run inside of a container.
-		Enabled:       docker.IsAvailable(),
+		Enabled:       docker.IsAvailable() || docker.IsContainerdAvailable(),
 		APIEndpoint:   u,
 		LogFile:       defaultLogFilePath,
 		LogLevel:      "info",
@@ -126,6 +155,7 @@ func NewDefaultAgentConfig() *AgentConfig {
 		// Docker
 		ContainerCacheDuration: 10 * time.Second,
 		CollectDockerNetwork:   true,
+		CrashLoopWindow:        10 * time.Minute,
 
 		// Kubernetes
 		CollectKubernetesMetadata:  true,
@@ -135,6 +165,7 @@ func NewDefaultAgentConfig() *AgentConfig {
 
 	// Set default values for proc/sys paths if unset.
 	if docker.IsContainerized() {
+		ac.ExcludePauseContainer = true
 		if v := os.Getenv("HOST_PROC"); v == "" {
 			os.Setenv("HOST_PROC", "/host/proc")
 		}
@@ -270,7 +301,36 @@ func NewAgentConfig(agentConf, legacyConf *File) (*AgentConfig, error) {
 		cfg.CollectDockerNetwork = file.GetBool(ns, "collect_docker_network", cfg.CollectDockerNetwork)
 		cfg.ContainerBlacklist = file.GetStrArrayDefault(ns, "container_blacklist", ",", cfg.ContainerBlacklist)
 		cfg.ContainerWhitelist = file.GetStrArrayDefault(ns, "container_whitelist", ",", cfg.ContainerWhitelist)
+		cfg.ContainerWhitelistMode = file.GetDefault(ns, "container_whitelist_mode", cfg.ContainerWhitelistMode)
+		cfg.ContainerNameNormalization = file.GetDefault(ns, "container_name_normalization", cfg.ContainerNameNormalization)
+		cfg.ContainerNameReplacement = file.GetDefault(ns, "container_name_replacement", cfg.ContainerNameReplacement)
+		cfg.ContainerLabelsAsTags = file.GetStrArrayDefault(ns, "container_labels_as_tags", ",", cfg.ContainerLabelsAsTags)
+		cfg.ContainerAnnotationLabels = file.GetStrArrayDefault(ns, "container_annotation_labels", ",", cfg.ContainerAnnotationLabels)
+		cfg.CgroupRoot = file.GetDefault(ns, "cgroup_root", cfg.CgroupRoot)
+		cfg.DockerEndpoints = file.GetStrArrayDefault(ns, "docker_endpoints", ",", cfg.DockerEndpoints)
+		cfg.NoProxyDocker = file.GetBool(ns, "no_proxy_docker", cfg.NoProxyDocker)
+		cfg.ExcludePauseContainer = file.GetBool(ns, "exclude_pause_container", cfg.ExcludePauseContainer)
 		cfg.ContainerCacheDuration = file.GetDurationDefault(ns, "container_cache_duration", time.Second, 30*time.Second)
+		cfg.ContainerMinUptime = file.GetDurationDefault(ns, "container_min_uptime", time.Second, cfg.ContainerMinUptime)
+		cfg.ContainerCreatedAfter = parseTimeDefault("container_created_after", file.GetDefault(ns, "container_created_after", ""), cfg.ContainerCreatedAfter)
+		cfg.ContainerCreatedBefore = parseTimeDefault("container_created_before", file.GetDefault(ns, "container_created_before", ""), cfg.ContainerCreatedBefore)
+		cfg.MaxContainers = file.GetIntDefault(ns, "max_containers", cfg.MaxContainers)
+		cfg.ContainerStatWorkers = file.GetIntDefault(ns, "container_stat_workers", cfg.ContainerStatWorkers)
+		cfg.ContainerCollectionDeadline = file.GetDurationDefault(ns, "container_collection_deadline", time.Second, cfg.ContainerCollectionDeadline)
+		cfg.DockerMaxInspectsPerCycle = file.GetIntDefault(ns, "docker_max_inspects_per_cycle", cfg.DockerMaxInspectsPerCycle)
+		cfg.CollectDockerHealthcheck = file.GetBool(ns, "collect_docker_healthcheck", cfg.CollectDockerHealthcheck)
+		cfg.CollectDockerDiskStats = file.GetBool(ns, "collect_docker_disk_stats", cfg.CollectDockerDiskStats)
+		cfg.CollectDockerNetworkPerInterface = file.GetBool(ns, "collect_docker_network_per_interface", cfg.CollectDockerNetworkPerInterface)
+		cfg.CollectDockerRestartCount = file.GetBool(ns, "collect_docker_restart_count", cfg.CollectDockerRestartCount)
+		cfg.CrashLoopWindow = file.GetDurationDefault(ns, "crash_loop_window", time.Second, cfg.CrashLoopWindow)
+		cfg.CrashLoopThreshold = file.GetIntDefault(ns, "crash_loop_threshold", cfg.CrashLoopThreshold)
+		cfg.WatchDockerEvents = file.GetBool(ns, "watch_docker_events", cfg.WatchDockerEvents)
+		cfg.DockerBackgroundRefresh = file.GetBool(ns, "docker_background_refresh", cfg.DockerBackgroundRefresh)
+		cfg.DockerRefreshInterval = file.GetDurationDefault(ns, "docker_refresh_interval", time.Second, cfg.DockerRefreshInterval)
+		cfg.CollectStoppedContainers = file.GetBool(ns, "collect_stopped_containers", cfg.CollectStoppedContainers)
+		cfg.ContainerMetadataOnly = file.GetBool(ns, "container_metadata_only", cfg.ContainerMetadataOnly)
+		cfg.UseDockerStatsAPI = file.GetBool(ns, "use_docker_stats_api", cfg.UseDockerStatsAPI)
+		cfg.UseContainerdMetrics = file.GetBool(ns, "use_containerd_metrics", cfg.UseContainerdMetrics)
 	}
 
 	cfg = mergeEnv(cfg)
@@ -280,6 +340,13 @@ func NewAgentConfig(agentConf, legacyConf *File) (*AgentConfig, error) {
 		cfg.LogLevel = "warn"
 	}
 
+	// The crash loops are detected from the restart counts, so setting a
+	// crash_loop_threshold enables them, with the inspect of new containers
+	// and of the ones changing state it takes.
+	if cfg.CrashLoopThreshold > 0 {
+		cfg.CollectDockerRestartCount = true
+	}
+
 	// (Re)configure the logging from our configuration
 	if err := NewLoggerLevel(cfg.LogLevel, cfg.LogFile); err != nil {
 		return nil, err
@@ -380,10 +447,107 @@ func mergeEnv(c *AgentConfig) *AgentConfig {
 	if v := os.Getenv("DD_CONTAINER_WHITELIST"); v != "" {
 		c.ContainerWhitelist = strings.Split(v, ",")
 	}
+	if v := os.Getenv("DD_CONTAINER_WHITELIST_MODE"); v != "" {
+		c.ContainerWhitelistMode = v
+	}
+	if v := os.Getenv("DD_CONTAINER_NAME_NORMALIZATION"); v != "" {
+		c.ContainerNameNormalization = v
+	}
+	if v := os.Getenv("DD_CONTAINER_NAME_REPLACEMENT"); v != "" {
+		c.ContainerNameReplacement = v
+	}
+	if v := os.Getenv("DD_CONTAINER_LABELS_AS_TAGS"); v != "" {
+		c.ContainerLabelsAsTags = strings.Split(v, ",")
+	}
+	if v := os.Getenv("DD_CONTAINER_ANNOTATION_LABELS"); v != "" {
+		c.ContainerAnnotationLabels = strings.Split(v, ",")
+	}
+	if v := os.Getenv("DD_CGROUP_ROOT"); v != "" {
+		c.CgroupRoot = v
+	}
+	if v := os.Getenv("DD_DOCKER_ENDPOINTS"); v != "" {
+		c.DockerEndpoints = strings.Split(v, ",")
+	}
+	if v := os.Getenv("DD_NO_PROXY_DOCKER"); v == "true" {
+		c.NoProxyDocker = true
+	}
+	if v := os.Getenv("DD_EXCLUDE_PAUSE_CONTAINER"); v == "false" {
+		c.ExcludePauseContainer = false
+	} else if v == "true" {
+		c.ExcludePauseContainer = true
+	}
 	if v := os.Getenv("DD_CONTAINER_CACHE_DURATION"); v != "" {
 		durationS, _ := strconv.Atoi(v)
 		c.ContainerCacheDuration = time.Duration(durationS) * time.Second
 	}
+	if v := os.Getenv("DD_CONTAINER_MIN_UPTIME"); v != "" {
+		uptimeS, _ := strconv.Atoi(v)
+		c.ContainerMinUptime = time.Duration(uptimeS) * time.Second
+	}
+	if v := os.Getenv("DD_CONTAINER_CREATED_AFTER"); v != "" {
+		c.ContainerCreatedAfter = parseTimeDefault("DD_CONTAINER_CREATED_AFTER", v, c.ContainerCreatedAfter)
+	}
+	if v := os.Getenv("DD_CONTAINER_CREATED_BEFORE"); v != "" {
+		c.ContainerCreatedBefore = parseTimeDefault("DD_CONTAINER_CREATED_BEFORE", v, c.ContainerCreatedBefore)
+	}
+	if v := os.Getenv("DD_MAX_CONTAINERS"); v != "" {
+		maxContainers, _ := strconv.Atoi(v)
+		c.MaxContainers = maxContainers
+	}
+	if v := os.Getenv("DD_CONTAINER_STAT_WORKERS"); v != "" {
+		workers, _ := strconv.Atoi(v)
+		c.ContainerStatWorkers = workers
+	}
+	if v := os.Getenv("DD_CONTAINER_COLLECTION_DEADLINE"); v != "" {
+		deadlineS, _ := strconv.Atoi(v)
+		c.ContainerCollectionDeadline = time.Duration(deadlineS) * time.Second
+	}
+	if v := os.Getenv("DD_DOCKER_MAX_INSPECTS_PER_CYCLE"); v != "" {
+		maxInspects, _ := strconv.Atoi(v)
+		c.DockerMaxInspectsPerCycle = maxInspects
+	}
+	if v := os.Getenv("DD_COLLECT_DOCKER_HEALTHCHECK"); v == "true" {
+		c.CollectDockerHealthcheck = true
+	}
+	if v := os.Getenv("DD_COLLECT_DOCKER_DISK_STATS"); v == "true" {
+		c.CollectDockerDiskStats = true
+	}
+	if v := os.Getenv("DD_COLLECT_DOCKER_NETWORK_PER_INTERFACE"); v == "true" {
+		c.CollectDockerNetworkPerInterface = true
+	}
+	if v := os.Getenv("DD_COLLECT_DOCKER_RESTART_COUNT"); v == "true" {
+		c.CollectDockerRestartCount = true
+	}
+	if v := os.Getenv("DD_CRASH_LOOP_WINDOW"); v != "" {
+		windowS, _ := strconv.Atoi(v)
+		c.CrashLoopWindow = time.Duration(windowS) * time.Second
+	}
+	if v := os.Getenv("DD_CRASH_LOOP_THRESHOLD"); v != "" {
+		threshold, _ := strconv.Atoi(v)
+		c.CrashLoopThreshold = threshold
+	}
+	if v := os.Getenv("DD_WATCH_DOCKER_EVENTS"); v == "true" {
+		c.WatchDockerEvents = true
+	}
+	if v := os.Getenv("DD_DOCKER_BACKGROUND_REFRESH"); v == "true" {
+		c.DockerBackgroundRefresh = true
+	}
+	if v := os.Getenv("DD_DOCKER_REFRESH_INTERVAL"); v != "" {
+		intervalS, _ := strconv.Atoi(v)
+		c.DockerRefreshInterval = time.Duration(intervalS) * time.Second
+	}
+	if v := os.Getenv("DD_COLLECT_STOPPED_CONTAINERS"); v == "true" {
+		c.CollectStoppedContainers = true
+	}
+	if v := os.Getenv("DD_CONTAINER_METADATA_ONLY"); v == "true" {
+		c.ContainerMetadataOnly = true
+	}
+	if v := os.Getenv("DD_USE_DOCKER_STATS_API"); v == "true" {
+		c.UseDockerStatsAPI = true
+	}
+	if v := os.Getenv("DD_USE_CONTAINERD_METRICS"); v == "true" {
+		c.UseContainerdMetrics = true
+	}
 
 	// Kubernetes config is set via environment only (for now).
 	if v := os.Getenv("DD_COLLECT_KUBERNETES_METADATA"); v == "false" {
@@ -402,6 +566,20 @@ func mergeEnv(c *AgentConfig) *AgentConfig {
 	return c
 }
 
+// parseTimeDefault parses the RFC 3339 timestamp of a setting, e.g.
+// 2018-03-01T00:00:00Z, returning def if it's empty or invalid.
+func parseTimeDefault(key, v string, def time.Time) time.Time {
+	if v == "" {
+		return def
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		log.Warnf("%s is not a valid RFC 3339 time: %s", key, err)
+		return def
+	}
+	return t
+}
+
 // IsBlacklisted returns a boolean indicating if the given command is blacklisted by our config.
 func IsBlacklisted(cmdline []string, blacklist []*regexp.Regexp) bool {
 	cmd := strings.Join(cmdline, " ")
@@ -178,3 +178,21 @@ func TestDDAgentConfigWithNewOpts(t *testing.T) {
 	assert.Equal(false, agentConfig.Enabled)
 	assert.Equal(containerChecks, agentConfig.EnabledChecks)
 }
+
+func TestCrashLoopRestartCount(t *testing.T) {
+	assert := assert.New(t)
+	load := func(lines ...string) *AgentConfig {
+		dd, _ := ini.Load([]byte(strings.Join(append([]string{"[Main]", "api_key = apikey_12", "[process.config]"}, lines...), "\n")))
+		agentConfig, err := NewAgentConfig(&File{instance: dd, Path: "whatever"}, nil)
+		assert.NoError(err)
+		return agentConfig
+	}
+
+	// The crash loops are only detected, from the restart counts, when a
+	// threshold is set.
+	assert.Equal(0, load().CrashLoopThreshold)
+	assert.False(load().CollectDockerRestartCount)
+	assert.True(load("collect_docker_restart_count = true").CollectDockerRestartCount)
+	assert.True(load("crash_loop_threshold = 3").CollectDockerRestartCount)
+	assert.False(load("crash_loop_threshold = 0").CollectDockerRestartCount)
+}
@@ -20,6 +20,7 @@
 		Process
 		Command
 		ProcessUser
+		ContainerHealthEvent
 		Container
 		ProcessStat
 		ContainerStat
@@ -326,6 +327,9 @@ type CollectorContainer struct {
 	Ecs        *datadog_agentpayload.ECSMetadataPayload  `protobuf:"bytes,7,opt,name=ecs" json:"ecs,omitempty"`
 	// Post-resolved fields
 	Host *Host `protobuf:"bytes,8,opt,name=host" json:"host,omitempty"`
+	// Health transitions since the previous collection, only set on the
+	// first message of a group.
+	HealthEvents []*ContainerHealthEvent `protobuf:"bytes,9,rep,name=healthEvents" json:"healthEvents,omitempty"`
 }
 
 func (m *CollectorContainer) Reset()                    { *m = CollectorContainer{} }
@@ -368,6 +372,13 @@ func (m *CollectorContainer) GetHost() *Host {
 	return nil
 }
 
+func (m *CollectorContainer) GetHealthEvents() []*ContainerHealthEvent {
+	if m != nil {
+		return m.HealthEvents
+	}
+	return nil
+}
+
 type CollectorContainerRealTime struct {
 	HostName string           `protobuf:"bytes,1,opt,name=hostName,proto3" json:"hostName,omitempty"`
 	Stats    []*ContainerStat `protobuf:"bytes,2,rep,name=stats" json:"stats,omitempty"`
@@ -517,6 +528,19 @@ func (m *ProcessUser) String() string            { return proto.CompactTextStrin
 func (*ProcessUser) ProtoMessage()               {}
 func (*ProcessUser) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{10} }
 
+type ContainerHealthEvent struct {
+	Id        string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	OldHealth ContainerHealth `protobuf:"varint,3,opt,name=oldHealth,proto3,enum=datadog.process_agent.ContainerHealth" json:"oldHealth,omitempty"`
+	NewHealth ContainerHealth `protobuf:"varint,4,opt,name=newHealth,proto3,enum=datadog.process_agent.ContainerHealth" json:"newHealth,omitempty"`
+	Timestamp int64           `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *ContainerHealthEvent) Reset()                    { *m = ContainerHealthEvent{} }
+func (m *ContainerHealthEvent) String() string            { return proto.CompactTextString(m) }
+func (*ContainerHealthEvent) ProtoMessage()               {}
+func (*ContainerHealthEvent) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{11} }
+
 type Container struct {
 	Type        string  `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
 	Id          string  `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
@@ -525,30 +549,75 @@ type Container struct {
 	CpuLimit    float32 `protobuf:"fixed32,5,opt,name=cpuLimit,proto3" json:"cpuLimit,omitempty"`
 	MemoryLimit uint64  `protobuf:"varint,6,opt,name=memoryLimit,proto3" json:"memoryLimit,omitempty"`
 	// 7 is removed, do not use.
-	State      ContainerState  `protobuf:"varint,8,opt,name=state,proto3,enum=datadog.process_agent.ContainerState" json:"state,omitempty"`
-	Health     ContainerHealth `protobuf:"varint,9,opt,name=health,proto3,enum=datadog.process_agent.ContainerHealth" json:"health,omitempty"`
-	Created    int64           `protobuf:"varint,10,opt,name=created,proto3" json:"created,omitempty"`
-	Rbps       float32         `protobuf:"fixed32,11,opt,name=rbps,proto3" json:"rbps,omitempty"`
-	Wbps       float32         `protobuf:"fixed32,12,opt,name=wbps,proto3" json:"wbps,omitempty"`
-	Key        uint32          `protobuf:"varint,13,opt,name=key,proto3" json:"key,omitempty"`
-	NetRcvdPs  float32         `protobuf:"fixed32,14,opt,name=netRcvdPs,proto3" json:"netRcvdPs,omitempty"`
-	NetSentPs  float32         `protobuf:"fixed32,15,opt,name=netSentPs,proto3" json:"netSentPs,omitempty"`
-	NetRcvdBps float32         `protobuf:"fixed32,16,opt,name=netRcvdBps,proto3" json:"netRcvdBps,omitempty"`
-	NetSentBps float32         `protobuf:"fixed32,17,opt,name=netSentBps,proto3" json:"netSentBps,omitempty"`
-	UserPct    float32         `protobuf:"fixed32,18,opt,name=userPct,proto3" json:"userPct,omitempty"`
-	SystemPct  float32         `protobuf:"fixed32,19,opt,name=systemPct,proto3" json:"systemPct,omitempty"`
-	TotalPct   float32         `protobuf:"fixed32,20,opt,name=totalPct,proto3" json:"totalPct,omitempty"`
-	MemRss     uint64          `protobuf:"varint,21,opt,name=memRss,proto3" json:"memRss,omitempty"`
-	MemCache   uint64          `protobuf:"varint,22,opt,name=memCache,proto3" json:"memCache,omitempty"`
-	Host       *Host           `protobuf:"bytes,23,opt,name=host" json:"host,omitempty"`
-	StartedAt  int64           `protobuf:"varint,24,opt,name=startedAt,proto3" json:"startedAt,omitempty"`
-	ByteKey    []byte          `protobuf:"bytes,25,opt,name=byteKey,proto3" json:"byteKey,omitempty"`
+	State            ContainerState  `protobuf:"varint,8,opt,name=state,proto3,enum=datadog.process_agent.ContainerState" json:"state,omitempty"`
+	Health           ContainerHealth `protobuf:"varint,9,opt,name=health,proto3,enum=datadog.process_agent.ContainerHealth" json:"health,omitempty"`
+	Created          int64           `protobuf:"varint,10,opt,name=created,proto3" json:"created,omitempty"`
+	Rbps             float32         `protobuf:"fixed32,11,opt,name=rbps,proto3" json:"rbps,omitempty"`
+	Wbps             float32         `protobuf:"fixed32,12,opt,name=wbps,proto3" json:"wbps,omitempty"`
+	Key              uint32          `protobuf:"varint,13,opt,name=key,proto3" json:"key,omitempty"`
+	NetRcvdPs        float32         `protobuf:"fixed32,14,opt,name=netRcvdPs,proto3" json:"netRcvdPs,omitempty"`
+	NetSentPs        float32         `protobuf:"fixed32,15,opt,name=netSentPs,proto3" json:"netSentPs,omitempty"`
+	NetRcvdBps       float32         `protobuf:"fixed32,16,opt,name=netRcvdBps,proto3" json:"netRcvdBps,omitempty"`
+	NetSentBps       float32         `protobuf:"fixed32,17,opt,name=netSentBps,proto3" json:"netSentBps,omitempty"`
+	UserPct          float32         `protobuf:"fixed32,18,opt,name=userPct,proto3" json:"userPct,omitempty"`
+	SystemPct        float32         `protobuf:"fixed32,19,opt,name=systemPct,proto3" json:"systemPct,omitempty"`
+	TotalPct         float32         `protobuf:"fixed32,20,opt,name=totalPct,proto3" json:"totalPct,omitempty"`
+	MemRss           uint64          `protobuf:"varint,21,opt,name=memRss,proto3" json:"memRss,omitempty"`
+	MemCache         uint64          `protobuf:"varint,22,opt,name=memCache,proto3" json:"memCache,omitempty"`
+	Host             *Host           `protobuf:"bytes,23,opt,name=host" json:"host,omitempty"`
+	StartedAt        int64           `protobuf:"varint,24,opt,name=startedAt,proto3" json:"startedAt,omitempty"`
+	ByteKey          []byte          `protobuf:"bytes,25,opt,name=byteKey,proto3" json:"byteKey,omitempty"`
+	SizeRw           int64           `protobuf:"varint,26,opt,name=sizeRw,proto3" json:"sizeRw,omitempty"`
+	SizeRootFs       int64           `protobuf:"varint,27,opt,name=sizeRootFs,proto3" json:"sizeRootFs,omitempty"`
+	RestartCount     int32           `protobuf:"varint,28,opt,name=restartCount,proto3" json:"restartCount,omitempty"`
+	PodName          string          `protobuf:"bytes,29,opt,name=podName,proto3" json:"podName,omitempty"`
+	PodNamespace     string          `protobuf:"bytes,30,opt,name=podNamespace,proto3" json:"podNamespace,omitempty"`
+	PodUid           string          `protobuf:"bytes,31,opt,name=podUid,proto3" json:"podUid,omitempty"`
+	MemSwap          uint64          `protobuf:"varint,32,opt,name=memSwap,proto3" json:"memSwap,omitempty"`
+	MemWorkingSet    uint64          `protobuf:"varint,33,opt,name=memWorkingSet,proto3" json:"memWorkingSet,omitempty"`
+	Uptime           int64           `protobuf:"varint,34,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	CpuNrThrottled   uint64          `protobuf:"varint,35,opt,name=cpuNrThrottled,proto3" json:"cpuNrThrottled,omitempty"`
+	CpuThrottledTime uint64          `protobuf:"varint,36,opt,name=cpuThrottledTime,proto3" json:"cpuThrottledTime,omitempty"`
+	// Block device with the highest read and write throughput.
+	TopIoDevice         string           `protobuf:"bytes,37,opt,name=topIoDevice,proto3" json:"topIoDevice,omitempty"`
+	TopIoDeviceRbps     float32          `protobuf:"fixed32,38,opt,name=topIoDeviceRbps,proto3" json:"topIoDeviceRbps,omitempty"`
+	TopIoDeviceWbps     float32          `protobuf:"fixed32,39,opt,name=topIoDeviceWbps,proto3" json:"topIoDeviceWbps,omitempty"`
+	ComposeProject      string           `protobuf:"bytes,40,opt,name=composeProject,proto3" json:"composeProject,omitempty"`
+	ComposeService      string           `protobuf:"bytes,41,opt,name=composeService,proto3" json:"composeService,omitempty"`
+	HealthFailingStreak int32            `protobuf:"varint,42,opt,name=healthFailingStreak,proto3" json:"healthFailingStreak,omitempty"`
+	MemOomKills         uint64           `protobuf:"varint,43,opt,name=memOomKills,proto3" json:"memOomKills,omitempty"`
+	Command             string           `protobuf:"bytes,44,opt,name=command,proto3" json:"command,omitempty"`
+	ImageTag            string           `protobuf:"bytes,45,opt,name=imageTag,proto3" json:"imageTag,omitempty"`
+	EcsTaskArn          string           `protobuf:"bytes,46,opt,name=ecsTaskArn,proto3" json:"ecsTaskArn,omitempty"`
+	EcsTaskFamily       string           `protobuf:"bytes,47,opt,name=ecsTaskFamily,proto3" json:"ecsTaskFamily,omitempty"`
+	Tags                []string         `protobuf:"bytes,48,rep,name=tags" json:"tags,omitempty"`
+	LogDriver           string           `protobuf:"bytes,49,opt,name=logDriver,proto3" json:"logDriver,omitempty"`
+	PidsCurrent         uint64           `protobuf:"varint,50,opt,name=pidsCurrent,proto3" json:"pidsCurrent,omitempty"`
+	PidsLimit           uint64           `protobuf:"varint,51,opt,name=pidsLimit,proto3" json:"pidsLimit,omitempty"`
+	Privileged          bool             `protobuf:"varint,52,opt,name=privileged,proto3" json:"privileged,omitempty"`
+	GpuCount            int32            `protobuf:"varint,53,opt,name=gpuCount,proto3" json:"gpuCount,omitempty"`
+	GpuVendor           string           `protobuf:"bytes,54,opt,name=gpuVendor,proto3" json:"gpuVendor,omitempty"`
+	CrashLooping        bool             `protobuf:"varint,55,opt,name=crashLooping,proto3" json:"crashLooping,omitempty"`
+	Endpoint            string           `protobuf:"bytes,56,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	MemoryPressure      float32          `protobuf:"fixed32,57,opt,name=memoryPressure,proto3" json:"memoryPressure,omitempty"`
+	CpuPressure         float32          `protobuf:"fixed32,58,opt,name=cpuPressure,proto3" json:"cpuPressure,omitempty"`
+	IoPressure          float32          `protobuf:"fixed32,59,opt,name=ioPressure,proto3" json:"ioPressure,omitempty"`
+	MountCount          int32            `protobuf:"varint,60,opt,name=mountCount,proto3" json:"mountCount,omitempty"`
+	BindMountCount      int32            `protobuf:"varint,61,opt,name=bindMountCount,proto3" json:"bindMountCount,omitempty"`
+	VolumeMountCount    int32            `protobuf:"varint,62,opt,name=volumeMountCount,proto3" json:"volumeMountCount,omitempty"`
+	IpAddress           string           `protobuf:"bytes,63,opt,name=ipAddress,proto3" json:"ipAddress,omitempty"`
+	MemReservation      uint64           `protobuf:"varint,64,opt,name=memReservation,proto3" json:"memReservation,omitempty"`
+	CpuShares           int64            `protobuf:"varint,65,opt,name=cpuShares,proto3" json:"cpuShares,omitempty"`
+	Annotations         map[string]int64 `protobuf:"bytes,66,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Exit code of a stopped container, -1 if running or unknown.
+	ExitCode  int32 `protobuf:"varint,67,opt,name=exitCode,proto3" json:"exitCode,omitempty"`
+	OomKilled bool  `protobuf:"varint,68,opt,name=oomKilled,proto3" json:"oomKilled,omitempty"`
 }
 
 func (m *Container) Reset()                    { *m = Container{} }
 func (m *Container) String() string            { return proto.CompactTextString(m) }
 func (*Container) ProtoMessage()               {}
-func (*Container) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{11} }
+func (*Container) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{12} }
 
 func (m *Container) GetHost() *Host {
 	if m != nil {
@@ -557,6 +626,13 @@ func (m *Container) GetHost() *Host {
 	return nil
 }
 
+func (m *Container) GetAnnotations() map[string]int64 {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
 // ProcessStat is used for real-time process messages. It should only contain
 // data that can change for a running process (and relevant information to
 // generate a key). We will send a lot of these in the real-time messages so
@@ -597,7 +673,7 @@ type ProcessStat struct {
 func (m *ProcessStat) Reset()                    { *m = ProcessStat{} }
 func (m *ProcessStat) String() string            { return proto.CompactTextString(m) }
 func (*ProcessStat) ProtoMessage()               {}
-func (*ProcessStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{12} }
+func (*ProcessStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{13} }
 
 func (m *ProcessStat) GetMemory() *MemoryStat {
 	if m != nil {
@@ -650,7 +726,7 @@ type ContainerStat struct {
 func (m *ContainerStat) Reset()                    { *m = ContainerStat{} }
 func (m *ContainerStat) String() string            { return proto.CompactTextString(m) }
 func (*ContainerStat) ProtoMessage()               {}
-func (*ContainerStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{13} }
+func (*ContainerStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{14} }
 
 type SystemInfo struct {
 	Uuid string     `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
@@ -663,7 +739,7 @@ type SystemInfo struct {
 func (m *SystemInfo) Reset()                    { *m = SystemInfo{} }
 func (m *SystemInfo) String() string            { return proto.CompactTextString(m) }
 func (*SystemInfo) ProtoMessage()               {}
-func (*SystemInfo) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{14} }
+func (*SystemInfo) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{15} }
 
 func (m *SystemInfo) GetOs() *OSInfo {
 	if m != nil {
@@ -690,7 +766,7 @@ type OSInfo struct {
 func (m *OSInfo) Reset()                    { *m = OSInfo{} }
 func (m *OSInfo) String() string            { return proto.CompactTextString(m) }
 func (*OSInfo) ProtoMessage()               {}
-func (*OSInfo) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{15} }
+func (*OSInfo) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{16} }
 
 type IOStat struct {
 	ReadRate       float32 `protobuf:"fixed32,1,opt,name=readRate,proto3" json:"readRate,omitempty"`
@@ -702,7 +778,7 @@ type IOStat struct {
 func (m *IOStat) Reset()                    { *m = IOStat{} }
 func (m *IOStat) String() string            { return proto.CompactTextString(m) }
 func (*IOStat) ProtoMessage()               {}
-func (*IOStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{16} }
+func (*IOStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{17} }
 
 type Connection struct {
 	Pid    int32  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
@@ -717,7 +793,7 @@ type Connection struct {
 func (m *Connection) Reset()                    { *m = Connection{} }
 func (m *Connection) String() string            { return proto.CompactTextString(m) }
 func (*Connection) ProtoMessage()               {}
-func (*Connection) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{17} }
+func (*Connection) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{18} }
 
 func (m *Connection) GetLaddr() *Addr {
 	if m != nil {
@@ -742,7 +818,7 @@ type Addr struct {
 func (m *Addr) Reset()                    { *m = Addr{} }
 func (m *Addr) String() string            { return proto.CompactTextString(m) }
 func (*Addr) ProtoMessage()               {}
-func (*Addr) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{18} }
+func (*Addr) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{19} }
 
 func (m *Addr) GetHost() *Host {
 	if m != nil {
@@ -765,7 +841,7 @@ type MemoryStat struct {
 func (m *MemoryStat) Reset()                    { *m = MemoryStat{} }
 func (m *MemoryStat) String() string            { return proto.CompactTextString(m) }
 func (*MemoryStat) ProtoMessage()               {}
-func (*MemoryStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{19} }
+func (*MemoryStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{20} }
 
 type CPUStat struct {
 	LastCpu    string           `protobuf:"bytes,1,opt,name=lastCpu,proto3" json:"lastCpu,omitempty"`
@@ -782,7 +858,7 @@ type CPUStat struct {
 func (m *CPUStat) Reset()                    { *m = CPUStat{} }
 func (m *CPUStat) String() string            { return proto.CompactTextString(m) }
 func (*CPUStat) ProtoMessage()               {}
-func (*CPUStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{20} }
+func (*CPUStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{21} }
 
 func (m *CPUStat) GetCpus() []*SingleCPUStat {
 	if m != nil {
@@ -799,7 +875,7 @@ type SingleCPUStat struct {
 func (m *SingleCPUStat) Reset()                    { *m = SingleCPUStat{} }
 func (m *SingleCPUStat) String() string            { return proto.CompactTextString(m) }
 func (*SingleCPUStat) ProtoMessage()               {}
-func (*SingleCPUStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{21} }
+func (*SingleCPUStat) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{22} }
 
 type CPUInfo struct {
 	Number     int32  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
@@ -816,7 +892,7 @@ type CPUInfo struct {
 func (m *CPUInfo) Reset()                    { *m = CPUInfo{} }
 func (m *CPUInfo) String() string            { return proto.CompactTextString(m) }
 func (*CPUInfo) ProtoMessage()               {}
-func (*CPUInfo) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{22} }
+func (*CPUInfo) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{23} }
 
 // Host and HostTags are used in backend post-resolution
 type Host struct {
@@ -832,7 +908,7 @@ type Host struct {
 func (m *Host) Reset()                    { *m = Host{} }
 func (m *Host) String() string            { return proto.CompactTextString(m) }
 func (*Host) ProtoMessage()               {}
-func (*Host) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{23} }
+func (*Host) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{24} }
 
 func (m *Host) GetTags() []*HostTags {
 	if m != nil {
@@ -849,7 +925,7 @@ type HostTags struct {
 func (m *HostTags) Reset()                    { *m = HostTags{} }
 func (m *HostTags) String() string            { return proto.CompactTextString(m) }
 func (*HostTags) ProtoMessage()               {}
-func (*HostTags) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{24} }
+func (*HostTags) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{25} }
 
 func init() {
 	proto.RegisterType((*ResCollector)(nil), "datadog.process_agent.ResCollector")
@@ -864,6 +940,7 @@ func init() {
 	proto.RegisterType((*Process)(nil), "datadog.process_agent.Process")
 	proto.RegisterType((*Command)(nil), "datadog.process_agent.Command")
 	proto.RegisterType((*ProcessUser)(nil), "datadog.process_agent.ProcessUser")
+	proto.RegisterType((*ContainerHealthEvent)(nil), "datadog.process_agent.ContainerHealthEvent")
 	proto.RegisterType((*Container)(nil), "datadog.process_agent.Container")
 	proto.RegisterType((*ProcessStat)(nil), "datadog.process_agent.ProcessStat")
 	proto.RegisterType((*ContainerStat)(nil), "datadog.process_agent.ContainerStat")
@@ -1254,6 +1331,18 @@ func (m *CollectorContainer) MarshalTo(data []byte) (int, error) {
 		}
 		i += n11
 	}
+	if len(m.HealthEvents) > 0 {
+		for _, msg := range m.HealthEvents {
+			data[i] = 0x4a
+			i++
+			i = encodeVarintAgent(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -1649,6 +1738,51 @@ func (m *ProcessUser) MarshalTo(data []byte) (int, error) {
 	return i, nil
 }
 
+func (m *ContainerHealthEvent) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *ContainerHealthEvent) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		data[i] = 0xa
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.Id)))
+		i += copy(data[i:], m.Id)
+	}
+	if len(m.Name) > 0 {
+		data[i] = 0x12
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.Name)))
+		i += copy(data[i:], m.Name)
+	}
+	if m.OldHealth != 0 {
+		data[i] = 0x18
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.OldHealth))
+	}
+	if m.NewHealth != 0 {
+		data[i] = 0x20
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.NewHealth))
+	}
+	if m.Timestamp != 0 {
+		data[i] = 0x28
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.Timestamp))
+	}
+	return i, nil
+}
+
 func (m *Container) Marshal() (data []byte, err error) {
 	size := m.Size()
 	data = make([]byte, size)
@@ -1814,6 +1948,357 @@ func (m *Container) MarshalTo(data []byte) (int, error) {
 		i = encodeVarintAgent(data, i, uint64(len(m.ByteKey)))
 		i += copy(data[i:], m.ByteKey)
 	}
+	if m.SizeRw != 0 {
+		data[i] = 0xd0
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.SizeRw))
+	}
+	if m.SizeRootFs != 0 {
+		data[i] = 0xd8
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.SizeRootFs))
+	}
+	if m.RestartCount != 0 {
+		data[i] = 0xe0
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.RestartCount))
+	}
+	if len(m.PodName) > 0 {
+		data[i] = 0xea
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.PodName)))
+		i += copy(data[i:], m.PodName)
+	}
+	if len(m.PodNamespace) > 0 {
+		data[i] = 0xf2
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.PodNamespace)))
+		i += copy(data[i:], m.PodNamespace)
+	}
+	if len(m.PodUid) > 0 {
+		data[i] = 0xfa
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.PodUid)))
+		i += copy(data[i:], m.PodUid)
+	}
+	if m.MemSwap != 0 {
+		data[i] = 0x80
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.MemSwap))
+	}
+	if m.MemWorkingSet != 0 {
+		data[i] = 0x88
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.MemWorkingSet))
+	}
+	if m.Uptime != 0 {
+		data[i] = 0x90
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.Uptime))
+	}
+	if m.CpuNrThrottled != 0 {
+		data[i] = 0x98
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.CpuNrThrottled))
+	}
+	if m.CpuThrottledTime != 0 {
+		data[i] = 0xa0
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.CpuThrottledTime))
+	}
+	if len(m.TopIoDevice) > 0 {
+		data[i] = 0xaa
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.TopIoDevice)))
+		i += copy(data[i:], m.TopIoDevice)
+	}
+	if m.TopIoDeviceRbps != 0 {
+		data[i] = 0xb5
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeFixed32Agent(data, i, uint32(math.Float32bits(float32(m.TopIoDeviceRbps))))
+	}
+	if m.TopIoDeviceWbps != 0 {
+		data[i] = 0xbd
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeFixed32Agent(data, i, uint32(math.Float32bits(float32(m.TopIoDeviceWbps))))
+	}
+	if len(m.ComposeProject) > 0 {
+		data[i] = 0xc2
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.ComposeProject)))
+		i += copy(data[i:], m.ComposeProject)
+	}
+	if len(m.ComposeService) > 0 {
+		data[i] = 0xca
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.ComposeService)))
+		i += copy(data[i:], m.ComposeService)
+	}
+	if m.HealthFailingStreak != 0 {
+		data[i] = 0xd0
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.HealthFailingStreak))
+	}
+	if m.MemOomKills != 0 {
+		data[i] = 0xd8
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.MemOomKills))
+	}
+	if len(m.Command) > 0 {
+		data[i] = 0xe2
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.Command)))
+		i += copy(data[i:], m.Command)
+	}
+	if len(m.ImageTag) > 0 {
+		data[i] = 0xea
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.ImageTag)))
+		i += copy(data[i:], m.ImageTag)
+	}
+	if len(m.EcsTaskArn) > 0 {
+		data[i] = 0xf2
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.EcsTaskArn)))
+		i += copy(data[i:], m.EcsTaskArn)
+	}
+	if len(m.EcsTaskFamily) > 0 {
+		data[i] = 0xfa
+		i++
+		data[i] = 0x2
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.EcsTaskFamily)))
+		i += copy(data[i:], m.EcsTaskFamily)
+	}
+	if len(m.Tags) > 0 {
+		for _, s := range m.Tags {
+			data[i] = 0x82
+			i++
+			data[i] = 0x3
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				data[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			data[i] = uint8(l)
+			i++
+			i += copy(data[i:], s)
+		}
+	}
+	if len(m.LogDriver) > 0 {
+		data[i] = 0x8a
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.LogDriver)))
+		i += copy(data[i:], m.LogDriver)
+	}
+	if m.PidsCurrent != 0 {
+		data[i] = 0x90
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.PidsCurrent))
+	}
+	if m.PidsLimit != 0 {
+		data[i] = 0x98
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.PidsLimit))
+	}
+	if m.Privileged {
+		data[i] = 0xa0
+		i++
+		data[i] = 0x3
+		i++
+		if m.Privileged {
+			data[i] = 1
+		} else {
+			data[i] = 0
+		}
+		i++
+	}
+	if m.GpuCount != 0 {
+		data[i] = 0xa8
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.GpuCount))
+	}
+	if len(m.GpuVendor) > 0 {
+		data[i] = 0xb2
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.GpuVendor)))
+		i += copy(data[i:], m.GpuVendor)
+	}
+	if m.CrashLooping {
+		data[i] = 0xb8
+		i++
+		data[i] = 0x3
+		i++
+		if m.CrashLooping {
+			data[i] = 1
+		} else {
+			data[i] = 0
+		}
+		i++
+	}
+	if len(m.Endpoint) > 0 {
+		data[i] = 0xc2
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.Endpoint)))
+		i += copy(data[i:], m.Endpoint)
+	}
+	if m.MemoryPressure != 0 {
+		data[i] = 0xcd
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeFixed32Agent(data, i, uint32(math.Float32bits(float32(m.MemoryPressure))))
+	}
+	if m.CpuPressure != 0 {
+		data[i] = 0xd5
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeFixed32Agent(data, i, uint32(math.Float32bits(float32(m.CpuPressure))))
+	}
+	if m.IoPressure != 0 {
+		data[i] = 0xdd
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeFixed32Agent(data, i, uint32(math.Float32bits(float32(m.IoPressure))))
+	}
+	if m.MountCount != 0 {
+		data[i] = 0xe0
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.MountCount))
+	}
+	if m.BindMountCount != 0 {
+		data[i] = 0xe8
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.BindMountCount))
+	}
+	if m.VolumeMountCount != 0 {
+		data[i] = 0xf0
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.VolumeMountCount))
+	}
+	if len(m.IpAddress) > 0 {
+		data[i] = 0xfa
+		i++
+		data[i] = 0x3
+		i++
+		i = encodeVarintAgent(data, i, uint64(len(m.IpAddress)))
+		i += copy(data[i:], m.IpAddress)
+	}
+	if m.MemReservation != 0 {
+		data[i] = 0x80
+		i++
+		data[i] = 0x4
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.MemReservation))
+	}
+	if m.CpuShares != 0 {
+		data[i] = 0x88
+		i++
+		data[i] = 0x4
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.CpuShares))
+	}
+	if len(m.Annotations) > 0 {
+		for k, _ := range m.Annotations {
+			data[i] = 0x92
+			i++
+			data[i] = 0x4
+			i++
+			v := m.Annotations[k]
+			mapSize := 1 + len(k) + sovAgent(uint64(len(k))) + 1 + sovAgent(uint64(v))
+			i = encodeVarintAgent(data, i, uint64(mapSize))
+			data[i] = 0xa
+			i++
+			i = encodeVarintAgent(data, i, uint64(len(k)))
+			i += copy(data[i:], k)
+			data[i] = 0x10
+			i++
+			i = encodeVarintAgent(data, i, uint64(v))
+		}
+	}
+	if m.ExitCode != 0 {
+		data[i] = 0x98
+		i++
+		data[i] = 0x4
+		i++
+		i = encodeVarintAgent(data, i, uint64(m.ExitCode))
+	}
+	if m.OomKilled {
+		data[i] = 0xa0
+		i++
+		data[i] = 0x4
+		i++
+		if m.OomKilled {
+			data[i] = 1
+		} else {
+			data[i] = 0
+		}
+		i++
+	}
 	return i, nil
 }
 
@@ -2888,6 +3373,12 @@ func (m *CollectorContainer) Size() (n int) {
 		l = m.Host.Size()
 		n += 1 + l + sovAgent(uint64(l))
 	}
+	if len(m.HealthEvents) > 0 {
+		for _, e := range m.HealthEvents {
+			l = e.Size()
+			n += 1 + l + sovAgent(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -3075,6 +3566,29 @@ func (m *ProcessUser) Size() (n int) {
 	return n
 }
 
+func (m *ContainerHealthEvent) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	if m.OldHealth != 0 {
+		n += 1 + sovAgent(uint64(m.OldHealth))
+	}
+	if m.NewHealth != 0 {
+		n += 1 + sovAgent(uint64(m.NewHealth))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovAgent(uint64(m.Timestamp))
+	}
+	return n
+}
+
 func (m *Container) Size() (n int) {
 	var l int
 	_ = l
@@ -3156,7 +3670,158 @@ func (m *Container) Size() (n int) {
 	if l > 0 {
 		n += 2 + l + sovAgent(uint64(l))
 	}
-	return n
+	if m.SizeRw != 0 {
+		n += 2 + sovAgent(uint64(m.SizeRw))
+	}
+	if m.SizeRootFs != 0 {
+		n += 2 + sovAgent(uint64(m.SizeRootFs))
+	}
+	if m.RestartCount != 0 {
+		n += 2 + sovAgent(uint64(m.RestartCount))
+	}
+	l = len(m.PodName)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	l = len(m.PodNamespace)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	l = len(m.PodUid)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	if m.MemSwap != 0 {
+		n += 2 + sovAgent(uint64(m.MemSwap))
+	}
+	if m.MemWorkingSet != 0 {
+		n += 2 + sovAgent(uint64(m.MemWorkingSet))
+	}
+	if m.Uptime != 0 {
+		n += 2 + sovAgent(uint64(m.Uptime))
+	}
+	if m.CpuNrThrottled != 0 {
+		n += 2 + sovAgent(uint64(m.CpuNrThrottled))
+	}
+	if m.CpuThrottledTime != 0 {
+		n += 2 + sovAgent(uint64(m.CpuThrottledTime))
+	}
+	l = len(m.TopIoDevice)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	if m.TopIoDeviceRbps != 0 {
+		n += 6
+	}
+	if m.TopIoDeviceWbps != 0 {
+		n += 6
+	}
+	l = len(m.ComposeProject)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	l = len(m.ComposeService)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	if m.HealthFailingStreak != 0 {
+		n += 2 + sovAgent(uint64(m.HealthFailingStreak))
+	}
+	if m.MemOomKills != 0 {
+		n += 2 + sovAgent(uint64(m.MemOomKills))
+	}
+	l = len(m.Command)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	l = len(m.ImageTag)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	l = len(m.EcsTaskArn)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	l = len(m.EcsTaskFamily)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	if len(m.Tags) > 0 {
+		for _, s := range m.Tags {
+			l = len(s)
+			n += 2 + l + sovAgent(uint64(l))
+		}
+	}
+	l = len(m.LogDriver)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	if m.PidsCurrent != 0 {
+		n += 2 + sovAgent(uint64(m.PidsCurrent))
+	}
+	if m.PidsLimit != 0 {
+		n += 2 + sovAgent(uint64(m.PidsLimit))
+	}
+	if m.Privileged {
+		n += 3
+	}
+	if m.GpuCount != 0 {
+		n += 2 + sovAgent(uint64(m.GpuCount))
+	}
+	l = len(m.GpuVendor)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	if m.CrashLooping {
+		n += 3
+	}
+	l = len(m.Endpoint)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	if m.MemoryPressure != 0 {
+		n += 6
+	}
+	if m.CpuPressure != 0 {
+		n += 6
+	}
+	if m.IoPressure != 0 {
+		n += 6
+	}
+	if m.MountCount != 0 {
+		n += 2 + sovAgent(uint64(m.MountCount))
+	}
+	if m.BindMountCount != 0 {
+		n += 2 + sovAgent(uint64(m.BindMountCount))
+	}
+	if m.VolumeMountCount != 0 {
+		n += 2 + sovAgent(uint64(m.VolumeMountCount))
+	}
+	l = len(m.IpAddress)
+	if l > 0 {
+		n += 2 + l + sovAgent(uint64(l))
+	}
+	if m.MemReservation != 0 {
+		n += 2 + sovAgent(uint64(m.MemReservation))
+	}
+	if m.CpuShares != 0 {
+		n += 2 + sovAgent(uint64(m.CpuShares))
+	}
+	if len(m.Annotations) > 0 {
+		for k, v := range m.Annotations {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovAgent(uint64(len(k))) + 1 + sovAgent(uint64(v))
+			n += mapEntrySize + 2 + sovAgent(uint64(mapEntrySize))
+		}
+	}
+	if m.ExitCode != 0 {
+		n += 2 + sovAgent(uint64(m.ExitCode))
+	}
+	if m.OomKilled {
+		n += 3
+	}
+	return n
 }
 
 func (m *ProcessStat) Size() (n int) {
@@ -4784,6 +5449,37 @@ func (m *CollectorContainer) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HealthEvents", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HealthEvents = append(m.HealthEvents, &ContainerHealthEvent{})
+			if err := m.HealthEvents[len(m.HealthEvents)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAgent(data[iNdEx:])
@@ -6118,7 +6814,7 @@ func (m *ProcessUser) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *Container) Unmarshal(data []byte) error {
+func (m *ContainerHealthEvent) Unmarshal(data []byte) error {
 	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6141,15 +6837,15 @@ func (m *Container) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Container: wiretype end group for non-group")
+			return fmt.Errorf("proto: ContainerHealthEvent: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Container: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ContainerHealthEvent: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6174,11 +6870,11 @@ func (m *Container) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Type = string(data[iNdEx:postIndex])
+			m.Id = string(data[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6203,13 +6899,13 @@ func (m *Container) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Id = string(data[iNdEx:postIndex])
+			m.Name = string(data[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OldHealth", wireType)
 			}
-			var stringLen uint64
+			m.OldHealth = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowAgent
@@ -6219,26 +6915,16 @@ func (m *Container) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.OldHealth |= (ContainerHealth(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthAgent
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Name = string(data[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Image", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewHealth", wireType)
 			}
-			var stringLen uint64
+			m.NewHealth = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowAgent
@@ -6248,40 +6934,16 @@ func (m *Container) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.NewHealth |= (ContainerHealth(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthAgent
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Image = string(data[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 5:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CpuLimit", wireType)
-			}
-			var v uint32
-			if (iNdEx + 4) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += 4
-			v = uint32(data[iNdEx-4])
-			v |= uint32(data[iNdEx-3]) << 8
-			v |= uint32(data[iNdEx-2]) << 16
-			v |= uint32(data[iNdEx-1]) << 24
-			m.CpuLimit = float32(math.Float32frombits(v))
-		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MemoryLimit", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
 			}
-			m.MemoryLimit = 0
+			m.Timestamp = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowAgent
@@ -6291,16 +6953,66 @@ func (m *Container) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.MemoryLimit |= (uint64(b) & 0x7F) << shift
+				m.Timestamp |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAgent(data[iNdEx:])
+			if err != nil {
+				return err
 			}
-			m.State = 0
+			if skippy < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Container) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAgent
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Container: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Container: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowAgent
@@ -6310,16 +7022,26 @@ func (m *Container) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.State |= (ContainerState(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 9:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Health", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
 			}
-			m.Health = 0
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowAgent
@@ -6329,17 +7051,156 @@ func (m *Container) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.Health |= (ContainerHealth(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 10:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Created", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
 			}
-			m.Created = 0
-			for shift := uint(0); ; shift += 7 {
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Image", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Image = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CpuLimit", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 4
+			v = uint32(data[iNdEx-4])
+			v |= uint32(data[iNdEx-3]) << 8
+			v |= uint32(data[iNdEx-2]) << 16
+			v |= uint32(data[iNdEx-1]) << 24
+			m.CpuLimit = float32(math.Float32frombits(v))
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryLimit", wireType)
+			}
+			m.MemoryLimit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.MemoryLimit |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			m.State = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.State |= (ContainerState(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Health", wireType)
+			}
+			m.Health = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Health |= (ContainerHealth(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Created", wireType)
+			}
+			m.Created = 0
+			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowAgent
 				}
@@ -6619,6 +7480,1038 @@ func (m *Container) Unmarshal(data []byte) error {
 				m.ByteKey = []byte{}
 			}
 			iNdEx = postIndex
+		case 26:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeRw", wireType)
+			}
+			m.SizeRw = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.SizeRw |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 27:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeRootFs", wireType)
+			}
+			m.SizeRootFs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.SizeRootFs |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 28:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RestartCount", wireType)
+			}
+			m.RestartCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.RestartCount |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 29:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PodName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PodName = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 30:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PodNamespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PodNamespace = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 31:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PodUid", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PodUid = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 32:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemSwap", wireType)
+			}
+			m.MemSwap = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.MemSwap |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 33:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemWorkingSet", wireType)
+			}
+			m.MemWorkingSet = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.MemWorkingSet |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 34:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Uptime", wireType)
+			}
+			m.Uptime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Uptime |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 35:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CpuNrThrottled", wireType)
+			}
+			m.CpuNrThrottled = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.CpuNrThrottled |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 36:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CpuThrottledTime", wireType)
+			}
+			m.CpuThrottledTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.CpuThrottledTime |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 37:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TopIoDevice", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TopIoDevice = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 38:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TopIoDeviceRbps", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 4
+			v = uint32(data[iNdEx-4])
+			v |= uint32(data[iNdEx-3]) << 8
+			v |= uint32(data[iNdEx-2]) << 16
+			v |= uint32(data[iNdEx-1]) << 24
+			m.TopIoDeviceRbps = float32(math.Float32frombits(v))
+		case 39:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TopIoDeviceWbps", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 4
+			v = uint32(data[iNdEx-4])
+			v |= uint32(data[iNdEx-3]) << 8
+			v |= uint32(data[iNdEx-2]) << 16
+			v |= uint32(data[iNdEx-1]) << 24
+			m.TopIoDeviceWbps = float32(math.Float32frombits(v))
+		case 40:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ComposeProject", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ComposeProject = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 41:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ComposeService", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ComposeService = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 42:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HealthFailingStreak", wireType)
+			}
+			m.HealthFailingStreak = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.HealthFailingStreak |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 43:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemOomKills", wireType)
+			}
+			m.MemOomKills = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.MemOomKills |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 44:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Command", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Command = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 45:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ImageTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ImageTag = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 46:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EcsTaskArn", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EcsTaskArn = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 47:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EcsTaskFamily", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EcsTaskFamily = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 48:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tags", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tags = append(m.Tags, string(data[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 49:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogDriver", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LogDriver = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 50:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PidsCurrent", wireType)
+			}
+			m.PidsCurrent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.PidsCurrent |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 51:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PidsLimit", wireType)
+			}
+			m.PidsLimit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.PidsLimit |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 52:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Privileged", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Privileged = bool(v != 0)
+		case 53:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GpuCount", wireType)
+			}
+			m.GpuCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.GpuCount |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 54:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GpuVendor", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GpuVendor = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 55:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CrashLooping", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.CrashLooping = bool(v != 0)
+		case 56:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Endpoint", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Endpoint = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 57:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryPressure", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 4
+			v = uint32(data[iNdEx-4])
+			v |= uint32(data[iNdEx-3]) << 8
+			v |= uint32(data[iNdEx-2]) << 16
+			v |= uint32(data[iNdEx-1]) << 24
+			m.MemoryPressure = float32(math.Float32frombits(v))
+		case 58:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CpuPressure", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 4
+			v = uint32(data[iNdEx-4])
+			v |= uint32(data[iNdEx-3]) << 8
+			v |= uint32(data[iNdEx-2]) << 16
+			v |= uint32(data[iNdEx-1]) << 24
+			m.CpuPressure = float32(math.Float32frombits(v))
+		case 59:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IoPressure", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 4
+			v = uint32(data[iNdEx-4])
+			v |= uint32(data[iNdEx-3]) << 8
+			v |= uint32(data[iNdEx-2]) << 16
+			v |= uint32(data[iNdEx-1]) << 24
+			m.IoPressure = float32(math.Float32frombits(v))
+		case 60:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MountCount", wireType)
+			}
+			m.MountCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.MountCount |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 61:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BindMountCount", wireType)
+			}
+			m.BindMountCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.BindMountCount |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 62:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VolumeMountCount", wireType)
+			}
+			m.VolumeMountCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.VolumeMountCount |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 63:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IpAddress", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IpAddress = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 64:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemReservation", wireType)
+			}
+			m.MemReservation = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.MemReservation |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 65:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CpuShares", wireType)
+			}
+			m.CpuShares = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.CpuShares |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 66:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Annotations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var keykey uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				keykey |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			var stringLenmapkey uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLenmapkey |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLenmapkey := int(stringLenmapkey)
+			if intStringLenmapkey < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postStringIndexmapkey := iNdEx + intStringLenmapkey
+			if postStringIndexmapkey > l {
+				return io.ErrUnexpectedEOF
+			}
+			mapkey := string(data[iNdEx:postStringIndexmapkey])
+			iNdEx = postStringIndexmapkey
+			if m.Annotations == nil {
+				m.Annotations = make(map[string]int64)
+			}
+			if iNdEx < postIndex {
+				var valuekey uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAgent
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := data[iNdEx]
+					iNdEx++
+					valuekey |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				var mapvalue int64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAgent
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := data[iNdEx]
+					iNdEx++
+					mapvalue |= (int64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Annotations[mapkey] = mapvalue
+			} else {
+				var mapvalue int64
+				m.Annotations[mapkey] = mapvalue
+			}
+			iNdEx = postIndex
+		case 67:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExitCode", wireType)
+			}
+			m.ExitCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.ExitCode |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 68:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OomKilled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.OomKilled = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAgent(data[iNdEx:])
@@ -9605,157 +11498,200 @@ var (
 func init() { proto.RegisterFile("agent.proto", fileDescriptorAgent) }
 
 var fileDescriptorAgent = []byte{
-	// 2429 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0xcd, 0x8f, 0x1c, 0x47,
-	0x15, 0x77, 0xf7, 0x74, 0xcf, 0xc7, 0xdb, 0x9d, 0xf5, 0xb8, 0xbc, 0x76, 0x3a, 0x1b, 0xb3, 0x6c,
-	0x9a, 0x10, 0x2d, 0x96, 0xbc, 0x36, 0x0e, 0x44, 0x4e, 0x40, 0x26, 0xf1, 0x9a, 0xe0, 0x55, 0x62,
-	0x67, 0x55, 0x63, 0x13, 0x14, 0x0e, 0x51, 0x6f, 0x77, 0xed, 0x6c, 0x6b, 0xa7, 0xbb, 0x87, 0xee,
-	0xea, 0x5d, 0x4f, 0x4e, 0xfc, 0x09, 0xb9, 0x70, 0xc8, 0x91, 0x03, 0x12, 0x48, 0xdc, 0xf9, 0x17,
-	0x50, 0xb8, 0x20, 0x24, 0x24, 0xb8, 0x21, 0x23, 0xfe, 0x0f, 0xf4, 0x5e, 0x55, 0x7f, 0xcc, 0xe7,
-	0x7e, 0xc0, 0x69, 0xea, 0xbd, 0x7a, 0xaf, 0xaa, 0xba, 0xea, 0xfd, 0xde, 0xef, 0x55, 0x0d, 0xac,
-	0x78, 0x03, 0x11, 0xcb, 0x9d, 0x51, 0x9a, 0xc8, 0x84, 0xdd, 0x08, 0x3c, 0xe9, 0x05, 0xc9, 0x00,
-	0x45, 0x5f, 0x64, 0xd9, 0x17, 0xd4, 0xb9, 0xf1, 0x83, 0x41, 0x28, 0x8f, 0xf2, 0x83, 0x1d, 0x3f,
-	0x89, 0xee, 0x3e, 0xf6, 0xa4, 0xf7, 0x38, 0x19, 0xdc, 0xa5, 0x9e, 0x3b, 0x23, 0x6f, 0x3c, 0x4c,
-	0xbc, 0x40, 0x49, 0x5f, 0x68, 0x49, 0x0d, 0xe6, 0x7e, 0x63, 0xc0, 0x2a, 0x17, 0xd9, 0x6e, 0x32,
-	0x1c, 0x0a, 0x5f, 0x26, 0x29, 0x7b, 0x04, 0xcd, 0x23, 0xe1, 0x05, 0x22, 0x75, 0x8c, 0x2d, 0x63,
-	0x7b, 0xe5, 0xfe, 0xed, 0x9d, 0xb9, 0xd3, 0xed, 0xd4, 0x9d, 0x76, 0x9e, 0x90, 0x07, 0xd7, 0x9e,
-	0xcc, 0x81, 0x56, 0x24, 0xb2, 0xcc, 0x1b, 0x08, 0xc7, 0xdc, 0x32, 0xb6, 0x3b, 0xbc, 0x10, 0xd9,
-	0x43, 0x68, 0x66, 0xd2, 0x93, 0x79, 0xe6, 0x34, 0x68, 0xf4, 0xb7, 0x17, 0x8c, 0x5e, 0x0e, 0xdd,
-	0x27, 0x6b, 0xae, 0xbd, 0x36, 0x6e, 0x41, 0x53, 0xcd, 0xc5, 0x18, 0x58, 0x72, 0x3c, 0x12, 0x8e,
-	0xb5, 0x65, 0x6c, 0xdb, 0x9c, 0xda, 0xee, 0xdf, 0x1a, 0xd0, 0x2d, 0x3d, 0xf7, 0xd3, 0xc4, 0x67,
-	0x1b, 0xd0, 0x3e, 0x4a, 0x32, 0xf9, 0xcc, 0x8b, 0x8a, 0xa5, 0x94, 0x32, 0xfb, 0x31, 0x74, 0xf4,
-	0xa4, 0x02, 0x97, 0xd3, 0xd8, 0x5e, 0xb9, 0xbf, 0xb9, 0x60, 0x39, 0xfb, 0x4a, 0xe2, 0x95, 0x03,
-	0xbb, 0x0b, 0x16, 0x8e, 0x44, 0xf3, 0xaf, 0xdc, 0x7f, 0x63, 0x81, 0xe3, 0x93, 0x24, 0x93, 0x9c,
-	0x0c, 0xd9, 0x0f, 0xc1, 0x0a, 0xe3, 0xc3, 0xc4, 0xb1, 0xc9, 0xe1, 0xcd, 0x05, 0x0e, 0xfd, 0x71,
-	0x26, 0x45, 0xb4, 0x17, 0x1f, 0x26, 0x9c, 0xcc, 0x71, 0x2f, 0x07, 0x69, 0x92, 0x8f, 0xf6, 0x02,
-	0xa7, 0x49, 0x9f, 0x5a, 0x88, 0xec, 0x16, 0x74, 0xa8, 0xd9, 0x0f, 0xbf, 0x14, 0x4e, 0x8b, 0xfa,
-	0x2a, 0x05, 0xdb, 0x03, 0x38, 0xce, 0x0f, 0x44, 0x1a, 0x0b, 0x29, 0x32, 0xa7, 0x4d, 0x93, 0x7e,
-	0xaf, 0x9c, 0x94, 0x26, 0x2b, 0x22, 0xe1, 0xe3, 0xfc, 0x40, 0x3c, 0x15, 0xd2, 0xc3, 0xce, 0x7d,
-	0xa5, 0xe3, 0x35, 0x67, 0xf6, 0x3e, 0x34, 0x84, 0x9f, 0x39, 0x1d, 0x1a, 0x63, 0x7b, 0xfe, 0x18,
-	0x3f, 0xdd, 0xed, 0x4f, 0x0f, 0x81, 0x4e, 0xec, 0x03, 0x00, 0x3f, 0x89, 0xa5, 0x17, 0xc6, 0x22,
-	0xcd, 0x1c, 0xa0, 0x5d, 0xde, 0x5a, 0x78, 0xe8, 0xda, 0x90, 0xd7, 0x7c, 0xdc, 0xdf, 0x1b, 0xb0,
-	0x5e, 0x1e, 0xea, 0x6e, 0x12, 0xc7, 0xc2, 0x97, 0x61, 0x12, 0x67, 0x4b, 0xcf, 0x76, 0x17, 0x56,
-	0xfc, 0xca, 0x54, 0x9f, 0xee, 0x9b, 0x8b, 0xe7, 0xd5, 0x96, 0xbc, 0xee, 0x75, 0xe1, 0x23, 0x76,
-	0xff, 0x69, 0xc2, 0xb5, 0x72, 0xa9, 0x5c, 0x78, 0xc3, 0xe7, 0x61, 0x24, 0x96, 0xae, 0xf3, 0x01,
-	0xd8, 0x18, 0xd9, 0xc5, 0x0a, 0xdd, 0xe5, 0xf1, 0x87, 0x60, 0xe0, 0xca, 0x81, 0xdd, 0x84, 0x26,
-	0x8e, 0xb2, 0x17, 0x68, 0x04, 0x68, 0x89, 0xad, 0x83, 0x9d, 0xa4, 0x83, 0xbd, 0x80, 0xe2, 0xcc,
-	0xe6, 0x4a, 0xb8, 0x74, 0x14, 0x39, 0xd0, 0x8a, 0xf3, 0x68, 0x77, 0x94, 0xab, 0x10, 0xb2, 0x79,
-	0x21, 0xb2, 0x2d, 0x58, 0x91, 0x89, 0xf4, 0x86, 0x4f, 0x45, 0x94, 0xa4, 0x63, 0x0a, 0x8e, 0x06,
-	0xaf, 0xab, 0xd8, 0x27, 0xb0, 0x56, 0x1e, 0x63, 0x9f, 0x3e, 0x52, 0x1d, 0xff, 0x5b, 0x67, 0x1d,
-	0x3f, 0x7d, 0xe6, 0x94, 0xaf, 0xfb, 0x75, 0x03, 0x58, 0x3d, 0x0c, 0x54, 0xdf, 0xc4, 0xe6, 0x1a,
-	0x53, 0x9b, 0x5b, 0x20, 0xce, 0xbc, 0x18, 0xe2, 0x26, 0x43, 0xb6, 0x71, 0xf1, 0x90, 0xad, 0xef,
-	0xb6, 0xb5, 0x64, 0xb7, 0xed, 0xe5, 0x98, 0x6d, 0xfe, 0x1f, 0x30, 0xdb, 0xba, 0x0c, 0x66, 0x8b,
-	0xb8, 0x6f, 0x9f, 0x37, 0xee, 0x7f, 0x6d, 0xc2, 0xc6, 0xec, 0xd9, 0xcc, 0x05, 0xc0, 0xf4, 0x19,
-	0xbd, 0x5f, 0x00, 0xc0, 0xbc, 0x40, 0x6c, 0x68, 0x08, 0xd4, 0x82, 0xb3, 0xb1, 0x34, 0x38, 0xad,
-	0xd9, 0xe0, 0xac, 0xe0, 0x63, 0x4f, 0xc0, 0xe7, 0x92, 0x40, 0x71, 0xef, 0xd5, 0xa2, 0x93, 0x8b,
-	0x5f, 0x29, 0xda, 0x5a, 0x06, 0x7d, 0xb7, 0x0f, 0x57, 0xa7, 0x58, 0x8e, 0xbd, 0x05, 0x5d, 0xcf,
-	0x97, 0xe1, 0x89, 0xd8, 0x1d, 0x86, 0x22, 0x96, 0x19, 0xed, 0x96, 0xcd, 0x27, 0x95, 0x38, 0x68,
-	0x18, 0x4b, 0x91, 0x9e, 0x78, 0x43, 0x1a, 0xd4, 0xe6, 0xa5, 0xec, 0xfe, 0xa1, 0x09, 0x2d, 0x9d,
-	0x2c, 0x58, 0x0f, 0x1a, 0xc7, 0x62, 0x4c, 0x63, 0x74, 0x39, 0x36, 0x51, 0x33, 0x0a, 0x03, 0xed,
-	0x84, 0xcd, 0xf2, 0xa8, 0x1b, 0xe7, 0x65, 0xb1, 0x07, 0xd0, 0xf2, 0x93, 0x28, 0xf2, 0xe2, 0x40,
-	0xa7, 0xc5, 0xcd, 0x85, 0x27, 0x46, 0x56, 0xbc, 0x30, 0x67, 0xef, 0x82, 0x95, 0x67, 0x22, 0xd5,
-	0xfc, 0x77, 0x46, 0xa6, 0x7b, 0x91, 0x89, 0x94, 0x93, 0x3d, 0x7b, 0x0f, 0x9a, 0x91, 0x3a, 0xc6,
-	0xd6, 0x52, 0x1c, 0xab, 0x83, 0xa5, 0xf8, 0xd0, 0x0e, 0xec, 0x1e, 0x34, 0xfc, 0x51, 0xae, 0xe3,
-	0x78, 0xe1, 0x42, 0xf7, 0x5f, 0x90, 0x13, 0x9a, 0xb2, 0x4d, 0x00, 0x3f, 0x15, 0x9e, 0x14, 0x18,
-	0xb8, 0x3a, 0xa9, 0xd5, 0x34, 0xec, 0x21, 0x74, 0x4a, 0x9c, 0x3b, 0x40, 0xe3, 0x9e, 0x9d, 0x1a,
-	0x2a, 0x17, 0x0c, 0xcc, 0x64, 0x24, 0xe2, 0x8f, 0x82, 0xdd, 0x24, 0x8f, 0xa5, 0xb3, 0x42, 0x27,
-	0x51, 0x57, 0xb1, 0xf7, 0x14, 0x20, 0x84, 0xb3, 0xba, 0x65, 0x6c, 0xaf, 0xdd, 0xff, 0xce, 0xd9,
-	0x8c, 0x20, 0x14, 0x1e, 0x30, 0xdf, 0x35, 0xc3, 0x04, 0x35, 0x4e, 0x97, 0x56, 0xf6, 0xad, 0x05,
-	0xbe, 0x7b, 0x9f, 0xaa, 0x5d, 0x52, 0xc6, 0xb8, 0xa6, 0x72, 0x81, 0x7b, 0x81, 0xb3, 0x46, 0x71,
-	0x5a, 0x57, 0x31, 0x17, 0x56, 0x4b, 0xf1, 0x63, 0x31, 0x76, 0xae, 0x52, 0x48, 0x4d, 0xe8, 0xd8,
-	0x7d, 0x58, 0x3f, 0x49, 0x86, 0x79, 0x2c, 0xbd, 0x74, 0xbc, 0x2b, 0x5f, 0xf6, 0x4f, 0x43, 0xe9,
-	0x1f, 0x89, 0xcc, 0xe9, 0x6d, 0x19, 0xdb, 0x16, 0x9f, 0xdb, 0xc7, 0xde, 0x85, 0x9b, 0x61, 0x3c,
-	0xd7, 0xeb, 0x1a, 0x79, 0x2d, 0xe8, 0x45, 0x90, 0x1e, 0x8c, 0xa5, 0xc0, 0xa5, 0xb0, 0x2d, 0x63,
-	0x7b, 0x95, 0x17, 0x22, 0xbb, 0x0d, 0xbd, 0x72, 0x55, 0x8f, 0xb4, 0xc9, 0x75, 0x32, 0x99, 0xd1,
-	0xbb, 0x5f, 0x1b, 0xd0, 0xd2, 0x51, 0x8a, 0xd5, 0xa4, 0x97, 0x0e, 0x10, 0x70, 0x8d, 0xed, 0x0e,
-	0xa7, 0x36, 0xa2, 0xc5, 0x3f, 0x0d, 0x08, 0x1a, 0x1d, 0x8e, 0x4d, 0xb4, 0x4a, 0x93, 0x44, 0x15,
-	0x04, 0x1d, 0x4e, 0x6d, 0x4c, 0x24, 0x49, 0xfc, 0x38, 0xcc, 0x8e, 0x29, 0xb0, 0xdb, 0x5c, 0x4b,
-	0x68, 0x3b, 0x42, 0xb0, 0xa9, 0x2c, 0x42, 0x6d, 0xb4, 0x1d, 0x51, 0xca, 0xd0, 0xf9, 0x43, 0x4b,
-	0x38, 0x93, 0x78, 0x29, 0x28, 0x4e, 0x3b, 0x1c, 0x9b, 0xee, 0x6f, 0x0c, 0x58, 0xa9, 0x41, 0x01,
-	0x47, 0x8b, 0xab, 0xf4, 0x49, 0x6d, 0xf4, 0xca, 0x2b, 0x34, 0xe7, 0x61, 0x80, 0x9a, 0x41, 0x18,
-	0xe8, 0x64, 0x88, 0x4d, 0xf4, 0x13, 0x68, 0xa4, 0xab, 0x64, 0x6c, 0x93, 0x0e, 0xcd, 0x6c, 0xad,
-	0xd3, 0x76, 0x59, 0x5e, 0xad, 0x36, 0xd3, 0x76, 0x19, 0xda, 0xb5, 0xb4, 0x6e, 0x10, 0x06, 0xee,
-	0xdf, 0x6d, 0xe8, 0x54, 0xe4, 0x5b, 0xd4, 0xe0, 0x7a, 0x55, 0xd8, 0x66, 0x6b, 0x60, 0xea, 0x45,
-	0x75, 0xb8, 0xa9, 0x46, 0xa1, 0x95, 0x37, 0x6a, 0x2b, 0x5f, 0x07, 0x3b, 0x8c, 0xf0, 0x76, 0xa0,
-	0x36, 0x52, 0x09, 0x98, 0xd7, 0xfc, 0x51, 0xfe, 0x49, 0x18, 0x85, 0x92, 0xd6, 0x66, 0xf2, 0x52,
-	0xc6, 0x18, 0x55, 0x98, 0x56, 0xdd, 0x4d, 0x0a, 0x8f, 0xba, 0x8a, 0xfd, 0xa8, 0xc0, 0x4d, 0x9b,
-	0x70, 0xf3, 0xdd, 0xf3, 0x10, 0x49, 0x89, 0x9c, 0x87, 0x74, 0xe9, 0x19, 0xca, 0x23, 0x82, 0xfc,
-	0xda, 0x92, 0x6b, 0x89, 0xf6, 0x7e, 0x42, 0xd6, 0x5c, 0x7b, 0x61, 0x40, 0xaa, 0x24, 0x11, 0x50,
-	0x52, 0x68, 0xf0, 0x42, 0xa4, 0x90, 0x39, 0x18, 0x65, 0x84, 0x74, 0x93, 0x53, 0x1b, 0x75, 0xa7,
-	0xa8, 0x5b, 0x55, 0x3a, 0x6c, 0x17, 0xc9, 0xba, 0x5b, 0x25, 0xeb, 0x5b, 0xd0, 0x89, 0x85, 0xe4,
-	0xfe, 0x49, 0xb0, 0x9f, 0x11, 0x28, 0x4d, 0x5e, 0x29, 0x74, 0x6f, 0x5f, 0xc4, 0x72, 0x3f, 0x23,
-	0x3c, 0xaa, 0x5e, 0xa5, 0xc0, 0x34, 0xa6, 0x4d, 0x1f, 0x8d, 0x14, 0x04, 0x4d, 0x5e, 0xd3, 0xe8,
-	0x7e, 0x34, 0xc6, 0xfe, 0x6b, 0x65, 0xbf, 0xd6, 0xe0, 0xf7, 0x60, 0xee, 0xdd, 0xf7, 0x25, 0x01,
-	0xcc, 0xe4, 0x85, 0x88, 0xf3, 0x66, 0x54, 0x30, 0x61, 0xdf, 0x75, 0x35, 0x6f, 0xa9, 0xc0, 0x23,
-	0x24, 0x92, 0xc5, 0xce, 0x75, 0x75, 0x84, 0x85, 0x8c, 0xc1, 0x1f, 0x89, 0x88, 0x67, 0x99, 0x73,
-	0x83, 0x4e, 0x4f, 0x4b, 0xe8, 0x13, 0x89, 0x68, 0xd7, 0xf3, 0x8f, 0x84, 0x73, 0x93, 0x7a, 0x4a,
-	0xb9, 0xa4, 0xa7, 0xd7, 0xce, 0x4b, 0x4f, 0xb8, 0x3c, 0xe9, 0xa5, 0x52, 0x04, 0x1f, 0x4a, 0xc7,
-	0xa1, 0xa3, 0xa8, 0x14, 0xf5, 0xbc, 0xf1, 0xfa, 0x44, 0xde, 0x70, 0xff, 0xd4, 0x2e, 0xf1, 0x46,
-	0x39, 0x51, 0x33, 0xa5, 0x51, 0x31, 0xe5, 0x24, 0x33, 0x98, 0x33, 0xcc, 0x50, 0xd1, 0x54, 0xe3,
-	0x92, 0x34, 0x65, 0x9d, 0x9f, 0xa6, 0x10, 0x54, 0xa1, 0x5f, 0x54, 0x90, 0xd4, 0xc6, 0x8f, 0x93,
-	0x47, 0xa9, 0xf0, 0x82, 0x4c, 0x23, 0xb6, 0x10, 0xa7, 0x49, 0xa7, 0x3d, 0x4b, 0x3a, 0x3a, 0xfa,
-	0x3a, 0x55, 0xf4, 0x4d, 0x91, 0x02, 0xcc, 0x92, 0xc2, 0xd3, 0xa9, 0xf2, 0x5e, 0x50, 0x8c, 0x9f,
-	0x1b, 0x79, 0x53, 0xce, 0xec, 0x67, 0xb0, 0x3a, 0xaa, 0x71, 0xda, 0x45, 0xe8, 0x6f, 0xc2, 0x91,
-	0xed, 0xc3, 0x55, 0x7f, 0x12, 0xa6, 0x84, 0x8f, 0xf3, 0x83, 0x7a, 0xda, 0x1d, 0xcb, 0xb2, 0x52,
-	0xc5, 0x0f, 0x4a, 0x40, 0x4d, 0x2a, 0x27, 0xac, 0x3e, 0x3b, 0x28, 0x61, 0x35, 0xa9, 0x9c, 0xa1,
-	0x52, 0x36, 0x87, 0x4a, 0x2b, 0x1e, 0xbf, 0x7e, 0x11, 0x1e, 0xdf, 0x01, 0x56, 0x0e, 0xf3, 0xac,
-	0xcc, 0x1c, 0x0a, 0x86, 0x73, 0x7a, 0xa6, 0xed, 0x75, 0x2e, 0xb9, 0x31, 0x6b, 0xaf, 0x93, 0xca,
-	0x3d, 0xb8, 0x3e, 0x3d, 0x0a, 0x66, 0x8f, 0x9b, 0xe4, 0x30, 0xaf, 0x6b, 0xda, 0xa3, 0xc8, 0x37,
-	0xaf, 0xcd, 0x7a, 0x14, 0x89, 0x67, 0x51, 0x15, 0xe1, 0x5c, 0xaa, 0x8a, 0x78, 0xfd, 0xbc, 0x55,
-	0xc4, 0xc6, 0xd9, 0x55, 0xc4, 0x1b, 0x0b, 0xaa, 0x88, 0x6f, 0x2c, 0xe8, 0x4e, 0x84, 0xb6, 0x66,
-	0x40, 0xa3, 0x64, 0xc0, 0x5a, 0x32, 0x35, 0x97, 0x24, 0xd3, 0xc6, 0xb2, 0x64, 0x6a, 0x4d, 0x25,
-	0xd3, 0x65, 0x5c, 0x59, 0x25, 0xda, 0xe6, 0xc2, 0x44, 0xdb, 0x9a, 0x4a, 0xb4, 0xaa, 0x4f, 0x8d,
-	0xd7, 0x2e, 0xfb, 0xd4, 0x78, 0x05, 0x85, 0x75, 0xe6, 0x50, 0x18, 0xd4, 0x28, 0x6c, 0x82, 0xb0,
-	0x56, 0x96, 0x12, 0xd6, 0xea, 0x72, 0xc2, 0xea, 0x9e, 0x41, 0x58, 0x6b, 0x33, 0x84, 0x55, 0xb2,
-	0xff, 0xd5, 0xff, 0x89, 0xfd, 0x7b, 0x97, 0x62, 0x7f, 0x9d, 0x3d, 0xaf, 0x4d, 0x70, 0x77, 0x45,
-	0x43, 0x6c, 0x09, 0x0d, 0x5d, 0x9f, 0xa4, 0xa1, 0xdf, 0x19, 0x00, 0xd5, 0x7b, 0x04, 0xee, 0x72,
-	0x9e, 0x97, 0xb1, 0x44, 0x6d, 0x76, 0x07, 0xcc, 0x24, 0xd3, 0x4f, 0x1a, 0x8b, 0x12, 0xc3, 0xa7,
-	0x7d, 0x7a, 0xce, 0x30, 0x13, 0x04, 0x94, 0xe5, 0xab, 0x0b, 0x72, 0x63, 0x39, 0xb9, 0xa8, 0x07,
-	0x10, 0x7f, 0xce, 0xed, 0xd9, 0x9e, 0xb9, 0x3d, 0xbb, 0x5f, 0x19, 0xd0, 0x54, 0x93, 0xcc, 0xad,
-	0x4c, 0x37, 0xa0, 0x3d, 0x1a, 0x7a, 0xf2, 0x30, 0x49, 0xa3, 0xe2, 0xda, 0x5b, 0xc8, 0x18, 0x9d,
-	0x87, 0x5e, 0x14, 0x0e, 0xc7, 0xba, 0x22, 0xd4, 0x12, 0x6e, 0xca, 0x89, 0x48, 0xb3, 0x30, 0x89,
-	0x75, 0x55, 0x58, 0x88, 0x98, 0x58, 0x8f, 0x45, 0x1a, 0x8b, 0xe1, 0xcf, 0x75, 0xbf, 0x4d, 0xfd,
-	0x93, 0x4a, 0x5a, 0x92, 0x4a, 0x88, 0x38, 0x3d, 0x12, 0x1f, 0xc7, 0x78, 0x30, 0x14, 0x38, 0x0a,
-	0x19, 0x4f, 0xe6, 0x34, 0x0d, 0xa5, 0xa0, 0x4e, 0x05, 0xc7, 0x4a, 0x81, 0x53, 0xa1, 0x25, 0x62,
-	0x3b, 0x23, 0x0b, 0x05, 0xca, 0x49, 0x25, 0x7b, 0x1b, 0xd6, 0xc8, 0xa5, 0x32, 0x53, 0xf0, 0x9c,
-	0xd2, 0xba, 0xff, 0x30, 0x00, 0xaa, 0xb7, 0xc5, 0x39, 0x35, 0xc5, 0x1a, 0x98, 0x87, 0x45, 0x01,
-	0x6f, 0x1e, 0x06, 0x53, 0x7b, 0x63, 0x97, 0x7b, 0x33, 0xe7, 0xad, 0x9b, 0x7d, 0x1f, 0xec, 0xa1,
-	0x17, 0x04, 0xc5, 0x7d, 0x7a, 0x51, 0x6d, 0xf4, 0x61, 0x10, 0xa4, 0x5c, 0x59, 0xa2, 0x4b, 0x4a,
-	0x2e, 0xcd, 0x73, 0xb8, 0x90, 0x25, 0xae, 0x48, 0xbf, 0xd7, 0xb7, 0xd4, 0x69, 0x29, 0xc9, 0xfd,
-	0x25, 0x58, 0x68, 0x56, 0x16, 0x68, 0xc6, 0x79, 0x0b, 0x34, 0x4c, 0x8e, 0xa3, 0xf2, 0x7a, 0x30,
-	0xa2, 0x6b, 0x52, 0x92, 0x4a, 0xfd, 0xc1, 0xd4, 0x76, 0xff, 0x68, 0x00, 0x54, 0x65, 0x12, 0xee,
-	0x5b, 0x9a, 0xa9, 0xb7, 0x10, 0x8b, 0x63, 0x13, 0x35, 0x27, 0x91, 0x02, 0x81, 0xc5, 0xb1, 0x49,
-	0x77, 0x95, 0x53, 0x6f, 0x44, 0xc3, 0x58, 0x9c, 0xda, 0xb4, 0xf6, 0x23, 0x2f, 0x15, 0xea, 0xf6,
-	0x63, 0x71, 0x2d, 0xd1, 0x6e, 0x8a, 0x97, 0x2a, 0x6f, 0x5a, 0x9c, 0xda, 0x38, 0xe2, 0x30, 0x3c,
-	0xd0, 0x09, 0x13, 0x9b, 0x68, 0x85, 0x1f, 0xa3, 0x33, 0x25, 0xb5, 0xf1, 0xde, 0x12, 0x84, 0xa9,
-	0x1c, 0xeb, 0x14, 0xa9, 0x04, 0xf7, 0xb7, 0x26, 0xb4, 0x74, 0x75, 0x86, 0x51, 0x3c, 0xf4, 0x32,
-	0xb9, 0x3b, 0xca, 0x35, 0x20, 0x0a, 0x71, 0x22, 0x9b, 0x9b, 0x53, 0xd9, 0xbc, 0xc6, 0x10, 0x8d,
-	0x25, 0x0c, 0x61, 0x4d, 0x33, 0x04, 0x66, 0xc5, 0x3c, 0x7a, 0xae, 0xab, 0x3e, 0x55, 0x0c, 0xd6,
-	0x34, 0xec, 0x81, 0x06, 0x7f, 0x73, 0xe9, 0xdb, 0x5a, 0x3f, 0x8c, 0x07, 0x43, 0x51, 0xd4, 0x97,
-	0x2a, 0x05, 0x14, 0x05, 0x66, 0xab, 0x56, 0x60, 0x6e, 0x40, 0x1b, 0x97, 0x45, 0xf5, 0x6f, 0x9b,
-	0x72, 0x42, 0x29, 0xe3, 0x4a, 0xd4, 0xb2, 0xea, 0xef, 0x26, 0x95, 0xc6, 0xfd, 0x09, 0x74, 0x27,
-	0xa6, 0x59, 0x94, 0x36, 0x16, 0x6d, 0x91, 0xfb, 0x1f, 0x83, 0x36, 0x99, 0x52, 0xce, 0x4d, 0x68,
-	0xc6, 0x79, 0x74, 0xa0, 0xff, 0xa2, 0xb2, 0xb9, 0x96, 0x50, 0x7f, 0x22, 0xe2, 0x20, 0x49, 0x75,
-	0x7c, 0x69, 0x69, 0x61, 0xca, 0x59, 0x07, 0x3b, 0x4a, 0x02, 0x31, 0x2c, 0xae, 0xa1, 0x24, 0xe0,
-	0xa7, 0x8c, 0x8e, 0xc6, 0x59, 0xe8, 0x7b, 0x43, 0xfd, 0x3a, 0xd8, 0xe1, 0x35, 0x0d, 0x8e, 0xe6,
-	0x27, 0xa9, 0xd0, 0x0f, 0x84, 0x1d, 0xae, 0x25, 0x1c, 0x0d, 0x5b, 0x45, 0xf5, 0xad, 0x04, 0x0c,
-	0xac, 0xe8, 0xe8, 0x4b, 0xbd, 0x5f, 0xd8, 0xc4, 0x23, 0xf5, 0x91, 0x73, 0xe9, 0x1d, 0xb1, 0xa3,
-	0xde, 0x11, 0x4b, 0x85, 0xfb, 0x17, 0x03, 0xac, 0x27, 0x05, 0x50, 0x8a, 0x64, 0x81, 0x55, 0x44,
-	0xf9, 0xae, 0x6f, 0xd6, 0xdf, 0xf5, 0xe7, 0xdd, 0xae, 0xdf, 0x01, 0x4b, 0x7a, 0x83, 0xcc, 0xb1,
-	0xe8, 0xd4, 0xbf, 0xbd, 0x04, 0x93, 0xcf, 0xbd, 0x41, 0xc6, 0xc9, 0x18, 0x43, 0xd0, 0x1b, 0x0e,
-	0x51, 0x41, 0xd1, 0xd2, 0xe1, 0x85, 0x58, 0x7f, 0x65, 0x6d, 0x2d, 0x7d, 0x65, 0x6d, 0xcf, 0xf2,
-	0xc4, 0x43, 0x68, 0x17, 0xf3, 0x50, 0x88, 0x24, 0x79, 0xea, 0x8b, 0xe7, 0xc5, 0x93, 0x41, 0x97,
-	0xd7, 0x34, 0x04, 0x4b, 0x9c, 0xde, 0x54, 0x4f, 0x30, 0xd8, 0xbe, 0x1d, 0xc2, 0xda, 0x24, 0x65,
-	0xb3, 0x15, 0x68, 0xe5, 0xf1, 0x71, 0x9c, 0x9c, 0xc6, 0xbd, 0x2b, 0x28, 0xe8, 0x7b, 0x76, 0xcf,
-	0x60, 0x6b, 0x00, 0xa9, 0x20, 0x92, 0x0d, 0xe3, 0x41, 0xcf, 0xc4, 0xce, 0x34, 0x8f, 0x63, 0x14,
-	0x1a, 0x0c, 0xa0, 0x39, 0xf2, 0xf2, 0x4c, 0x04, 0x3d, 0x0b, 0xdb, 0xe2, 0x65, 0x88, 0x4e, 0x36,
-	0x6b, 0x83, 0x15, 0x08, 0x2f, 0xe8, 0x35, 0x6f, 0x3f, 0x83, 0xab, 0x53, 0xfc, 0xce, 0xae, 0x41,
-	0x57, 0xcf, 0xa5, 0x14, 0xbd, 0x2b, 0x6c, 0x15, 0xda, 0xe5, 0x14, 0x06, 0x4e, 0xa1, 0x4a, 0x80,
-	0x71, 0xcf, 0x64, 0x5d, 0xe8, 0xe4, 0x71, 0x21, 0x36, 0x6e, 0x7f, 0x04, 0xab, 0xf5, 0x4b, 0x0a,
-	0xb3, 0xc1, 0x78, 0xd1, 0xbb, 0x82, 0x3f, 0x8f, 0x7b, 0x06, 0xfe, 0xf0, 0x9e, 0x89, 0x3f, 0xfd,
-	0x5e, 0x03, 0x7f, 0x9e, 0xf7, 0x2c, 0xfc, 0xf9, 0xac, 0x67, 0xe3, 0xcf, 0x2f, 0x7a, 0x4d, 0xfc,
-	0xf9, 0xbc, 0xd7, 0x7a, 0xf4, 0xc1, 0x9f, 0x5f, 0x6d, 0x1a, 0x7f, 0x7d, 0xb5, 0x69, 0xfc, 0xeb,
-	0xd5, 0xa6, 0xf1, 0xd5, 0xbf, 0x37, 0xaf, 0x7c, 0xbe, 0x33, 0xe7, 0x8f, 0x5e, 0x7d, 0xc6, 0x77,
-	0xf4, 0x19, 0xdf, 0xa1, 0x33, 0xbe, 0x4b, 0x01, 0x7d, 0xd0, 0xa4, 0x7f, 0x7a, 0xdf, 0xf9, 0x6f,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xd5, 0xdf, 0xff, 0xb4, 0x45, 0x1e, 0x00, 0x00,
+	// 3108 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x5a, 0x49, 0x73, 0xdd, 0xc6,
+	0xf1, 0x17, 0xf0, 0xf6, 0xe1, 0xf6, 0x04, 0xd1, 0xf2, 0x98, 0x96, 0x69, 0xfa, 0x79, 0xf9, 0xd3,
+	0xf2, 0x5f, 0x94, 0x2c, 0x2f, 0x91, 0x65, 0x47, 0xb6, 0x44, 0x5a, 0x11, 0xcb, 0x96, 0xc4, 0x9a,
+	0x47, 0x59, 0x29, 0xe7, 0xe0, 0x02, 0x81, 0xd1, 0x23, 0x42, 0x00, 0x83, 0x00, 0x03, 0x52, 0xf4,
+	0x29, 0x1f, 0xc1, 0x97, 0x1c, 0x72, 0xcc, 0x21, 0x55, 0x49, 0x55, 0xee, 0xf9, 0x0a, 0x29, 0xe7,
+	0x92, 0xca, 0x29, 0xb9, 0xa5, 0x94, 0xe5, 0x90, 0x4f, 0x91, 0xea, 0x9e, 0xc1, 0xfa, 0x16, 0x92,
+	0x4a, 0x4e, 0x6f, 0xfa, 0xd7, 0xdd, 0xb3, 0x76, 0xf7, 0x74, 0x0f, 0x1e, 0x99, 0xb3, 0x47, 0x3c,
+	0x94, 0x1b, 0x51, 0x2c, 0xa4, 0xb0, 0x5e, 0x70, 0x6d, 0x69, 0xbb, 0x62, 0x04, 0xa4, 0xc3, 0x93,
+	0xe4, 0x1b, 0x64, 0xae, 0xbc, 0x3f, 0xf2, 0xe4, 0x7e, 0xba, 0xb7, 0xe1, 0x88, 0xe0, 0xea, 0x96,
+	0x2d, 0xed, 0x2d, 0x31, 0xba, 0x8a, 0x9c, 0x2b, 0x91, 0x7d, 0xec, 0x0b, 0xdb, 0x55, 0xd4, 0x37,
+	0x9a, 0x52, 0x9d, 0x0d, 0xbe, 0x37, 0xc8, 0x3c, 0xe3, 0xc9, 0xa6, 0xf0, 0x7d, 0xee, 0x48, 0x11,
+	0x5b, 0x77, 0x48, 0x7b, 0x9f, 0xdb, 0x2e, 0x8f, 0xa9, 0xb1, 0x66, 0xac, 0xcf, 0x5d, 0xbf, 0xbc,
+	0x31, 0x71, 0xb8, 0x8d, 0xb2, 0xd2, 0xc6, 0x3d, 0xd4, 0x60, 0x5a, 0xd3, 0xa2, 0xa4, 0x13, 0xf0,
+	0x24, 0xb1, 0x47, 0x9c, 0x9a, 0x6b, 0xc6, 0x7a, 0x8f, 0x65, 0xa4, 0x75, 0x8b, 0xb4, 0x13, 0x69,
+	0xcb, 0x34, 0xa1, 0x0d, 0xec, 0xfd, 0xad, 0x29, 0xbd, 0xe7, 0x5d, 0x0f, 0x51, 0x9a, 0x69, 0xad,
+	0x95, 0x4b, 0xa4, 0xad, 0xc6, 0xb2, 0x2c, 0xd2, 0x94, 0xc7, 0x11, 0xa7, 0xcd, 0x35, 0x63, 0xbd,
+	0xc5, 0xb0, 0x3d, 0xf8, 0x73, 0x83, 0x2c, 0xe4, 0x9a, 0x3b, 0xb1, 0x70, 0xac, 0x15, 0xd2, 0xdd,
+	0x17, 0x89, 0x7c, 0x60, 0x07, 0xd9, 0x54, 0x72, 0xda, 0xfa, 0x84, 0xf4, 0xf4, 0xa0, 0x1c, 0xa6,
+	0xd3, 0x58, 0x9f, 0xbb, 0xbe, 0x3a, 0x65, 0x3a, 0x3b, 0x8a, 0x62, 0x85, 0x82, 0x75, 0x95, 0x34,
+	0xa1, 0x27, 0x1c, 0x7f, 0xee, 0xfa, 0xcb, 0x53, 0x14, 0xef, 0x89, 0x44, 0x32, 0x14, 0xb4, 0x3e,
+	0x20, 0x4d, 0x2f, 0x7c, 0x22, 0x68, 0x0b, 0x15, 0x5e, 0x9b, 0xa2, 0x30, 0x3c, 0x4e, 0x24, 0x0f,
+	0xb6, 0xc3, 0x27, 0x82, 0xa1, 0x38, 0xec, 0xe5, 0x28, 0x16, 0x69, 0xb4, 0xed, 0xd2, 0x36, 0x2e,
+	0x35, 0x23, 0xad, 0x4b, 0xa4, 0x87, 0xcd, 0xa1, 0xf7, 0x2d, 0xa7, 0x1d, 0xe4, 0x15, 0x80, 0xb5,
+	0x4d, 0xc8, 0x41, 0xba, 0xc7, 0xe3, 0x90, 0x4b, 0x9e, 0xd0, 0x2e, 0x0e, 0xfa, 0x76, 0x3e, 0x28,
+	0x0e, 0x96, 0x59, 0xc2, 0x17, 0xe9, 0x1e, 0xbf, 0xcf, 0xa5, 0x0d, 0xcc, 0x1d, 0x85, 0xb1, 0x92,
+	0xb2, 0x75, 0x93, 0x34, 0xb8, 0x93, 0xd0, 0x1e, 0xf6, 0xb1, 0x3e, 0xb9, 0x8f, 0xcf, 0x37, 0x87,
+	0xf5, 0x2e, 0x40, 0xc9, 0xfa, 0x8c, 0x10, 0x47, 0x84, 0xd2, 0xf6, 0x42, 0x1e, 0x27, 0x94, 0xe0,
+	0x2e, 0xaf, 0x4d, 0x3d, 0x74, 0x2d, 0xc8, 0x4a, 0x3a, 0x83, 0xdf, 0x18, 0x64, 0x39, 0x3f, 0xd4,
+	0x4d, 0x11, 0x86, 0xdc, 0x91, 0x9e, 0x08, 0x93, 0x99, 0x67, 0xbb, 0x49, 0xe6, 0x9c, 0x42, 0x54,
+	0x9f, 0xee, 0x6b, 0xd3, 0xc7, 0xd5, 0x92, 0xac, 0xac, 0x75, 0xe6, 0x23, 0x1e, 0xfc, 0xd5, 0x24,
+	0xe7, 0xf3, 0xa9, 0x32, 0x6e, 0xfb, 0xbb, 0x5e, 0xc0, 0x67, 0xce, 0xf3, 0x06, 0x69, 0x81, 0x65,
+	0x67, 0x33, 0x1c, 0xcc, 0xb6, 0x3f, 0x70, 0x06, 0xa6, 0x14, 0xac, 0x8b, 0xa4, 0x0d, 0xbd, 0x6c,
+	0xbb, 0xda, 0x03, 0x34, 0x65, 0x2d, 0x93, 0x96, 0x88, 0x47, 0xdb, 0x2e, 0xda, 0x59, 0x8b, 0x29,
+	0xe2, 0xb9, 0xad, 0x88, 0x92, 0x4e, 0x98, 0x06, 0x9b, 0x51, 0xaa, 0x4c, 0xa8, 0xc5, 0x32, 0xd2,
+	0x5a, 0x23, 0x73, 0x52, 0x48, 0xdb, 0xbf, 0xcf, 0x03, 0x11, 0x1f, 0xa3, 0x71, 0x34, 0x58, 0x19,
+	0xb2, 0xbe, 0x24, 0x8b, 0xf9, 0x31, 0x0e, 0x71, 0x91, 0xea, 0xf8, 0xdf, 0x38, 0xe9, 0xf8, 0x71,
+	0x99, 0x35, 0xdd, 0xc1, 0xbf, 0x1b, 0xc4, 0x2a, 0x9b, 0x81, 0xe2, 0x55, 0x36, 0xd7, 0xa8, 0x6d,
+	0x6e, 0xe6, 0x71, 0xe6, 0xd9, 0x3c, 0xae, 0x6a, 0xb2, 0x8d, 0xb3, 0x9b, 0x6c, 0x79, 0xb7, 0x9b,
+	0x33, 0x76, 0xbb, 0x35, 0xdb, 0x67, 0xdb, 0xff, 0x03, 0x9f, 0xed, 0x3c, 0x8f, 0xcf, 0x66, 0x76,
+	0xdf, 0x3d, 0x6d, 0x68, 0x7b, 0x48, 0xe6, 0xf7, 0xb9, 0xed, 0xcb, 0xfd, 0xcf, 0x0f, 0x79, 0x28,
+	0x21, 0x52, 0xc0, 0x9e, 0xbd, 0x73, 0xd2, 0x9e, 0xdd, 0x2b, 0x74, 0x58, 0xa5, 0x83, 0xc1, 0xcf,
+	0x4d, 0xb2, 0x32, 0x7e, 0xd8, 0x13, 0x3d, 0xaa, 0x7e, 0xe8, 0x37, 0x33, 0x8f, 0x32, 0xcf, 0x60,
+	0x6c, 0xda, 0xa7, 0x4a, 0xd6, 0xde, 0x98, 0x69, 0xed, 0xcd, 0x71, 0x6b, 0x2f, 0xfc, 0xb1, 0x55,
+	0xf1, 0xc7, 0xe7, 0xf4, 0xbc, 0xc1, 0xb5, 0x92, 0xb9, 0x33, 0xfe, 0x33, 0x75, 0x0f, 0xce, 0x8a,
+	0x25, 0x83, 0x21, 0x59, 0xaa, 0x5d, 0x9b, 0xd6, 0x1b, 0x64, 0xc1, 0x76, 0xa4, 0x77, 0xc8, 0x37,
+	0x7d, 0x0f, 0x4f, 0xc6, 0xc0, 0x61, 0xaa, 0x20, 0x74, 0xea, 0x85, 0x92, 0xc7, 0x87, 0xb6, 0x8f,
+	0x9d, 0xb6, 0x58, 0x4e, 0x0f, 0x7e, 0xdb, 0x26, 0x1d, 0x1d, 0x7d, 0xac, 0x3e, 0x69, 0x1c, 0xf0,
+	0x63, 0xec, 0x63, 0x81, 0x41, 0x13, 0x90, 0xc8, 0x73, 0xb5, 0x12, 0x34, 0x73, 0xdb, 0x69, 0x9c,
+	0xd6, 0x76, 0x6e, 0x90, 0x8e, 0x23, 0x82, 0xc0, 0x0e, 0x5d, 0x1d, 0x67, 0x57, 0xa7, 0x9e, 0x18,
+	0x4a, 0xb1, 0x4c, 0xdc, 0xfa, 0x90, 0x34, 0xd3, 0x84, 0xc7, 0xfa, 0x42, 0x3d, 0x21, 0x74, 0x3e,
+	0x4a, 0x78, 0xcc, 0x50, 0xde, 0xfa, 0x88, 0xb4, 0x03, 0x75, 0x8c, 0x9d, 0x99, 0x81, 0x41, 0x1d,
+	0x2c, 0xda, 0x87, 0x56, 0xb0, 0xae, 0x91, 0x86, 0x13, 0xa5, 0xb4, 0x3b, 0x7b, 0xa2, 0x3b, 0x8f,
+	0x50, 0x09, 0x44, 0xad, 0x55, 0x42, 0x9c, 0x98, 0xdb, 0x92, 0x83, 0xe1, 0xea, 0x28, 0x59, 0x42,
+	0xac, 0x5b, 0xa4, 0x97, 0x07, 0x0e, 0x4a, 0xd6, 0x8c, 0x53, 0xc5, 0x9a, 0x42, 0x05, 0x0c, 0x53,
+	0x44, 0x3c, 0xbc, 0xeb, 0x6e, 0x8a, 0x34, 0x94, 0x74, 0x0e, 0x4f, 0xa2, 0x0c, 0x59, 0x1f, 0x29,
+	0x87, 0xe0, 0x74, 0x7e, 0xcd, 0x58, 0x5f, 0xbc, 0xfe, 0xfa, 0xc9, 0x57, 0x0c, 0x57, 0xfe, 0x00,
+	0x01, 0xb4, 0xed, 0x09, 0x40, 0xe8, 0x02, 0xce, 0xec, 0x95, 0x29, 0xba, 0xdb, 0x0f, 0xd5, 0x2e,
+	0x29, 0x61, 0x98, 0x53, 0x3e, 0xc1, 0x6d, 0x97, 0x2e, 0xa2, 0x9d, 0x96, 0x21, 0x6b, 0x40, 0xe6,
+	0x73, 0xf2, 0x0b, 0x7e, 0x4c, 0x97, 0xd0, 0xa4, 0x2a, 0x98, 0x75, 0x9d, 0x2c, 0x1f, 0x0a, 0x3f,
+	0x0d, 0xa5, 0x1d, 0x1f, 0x6f, 0xca, 0xa7, 0xc3, 0x23, 0x4f, 0x3a, 0xfb, 0x3c, 0xa1, 0xfd, 0x35,
+	0x63, 0xbd, 0xc9, 0x26, 0xf2, 0xac, 0x0f, 0xc9, 0x45, 0x2f, 0x9c, 0xa8, 0x75, 0x1e, 0xb5, 0xa6,
+	0x70, 0xc1, 0x49, 0xf7, 0x8e, 0x25, 0x87, 0xa9, 0x58, 0x6b, 0xc6, 0xfa, 0x3c, 0xcb, 0x48, 0xeb,
+	0x32, 0xe9, 0xe7, 0xb3, 0xba, 0xa3, 0x45, 0x2e, 0xa0, 0xc8, 0x18, 0x3e, 0xf8, 0xa5, 0x41, 0x3a,
+	0xda, 0x4a, 0x21, 0x3d, 0xb5, 0xe3, 0x11, 0x38, 0x5c, 0x63, 0xbd, 0xc7, 0xb0, 0x0d, 0xde, 0xe2,
+	0x1c, 0xb9, 0xe8, 0x1a, 0x3d, 0x06, 0x4d, 0x90, 0x8a, 0x85, 0x50, 0x19, 0x46, 0x8f, 0x61, 0x1b,
+	0x02, 0x89, 0x08, 0xb7, 0xbc, 0xe4, 0x00, 0x0d, 0xbb, 0xcb, 0x34, 0x05, 0xb2, 0x51, 0xe4, 0x65,
+	0x51, 0x04, 0xdb, 0x20, 0x1b, 0x61, 0xc8, 0xd0, 0xf1, 0x43, 0x53, 0x30, 0x12, 0x7f, 0xca, 0xd1,
+	0x4e, 0x7b, 0x0c, 0x9a, 0x83, 0x5f, 0x18, 0x64, 0xae, 0xe4, 0x0a, 0xd0, 0x5b, 0x58, 0x84, 0x4f,
+	0x6c, 0x83, 0x56, 0x5a, 0x78, 0x73, 0xea, 0xb9, 0x80, 0x8c, 0x3c, 0x57, 0x07, 0x43, 0x68, 0x82,
+	0x1e, 0x07, 0x21, 0x9d, 0x76, 0xf3, 0x54, 0x63, 0x20, 0xd6, 0xd2, 0x98, 0x96, 0x4b, 0xd2, 0x62,
+	0xb6, 0x89, 0x96, 0x4b, 0x40, 0xae, 0xa3, 0xb1, 0x91, 0xe7, 0x0e, 0xfe, 0x81, 0xd9, 0xdd, 0xf8,
+	0x85, 0x60, 0x2d, 0x12, 0xd3, 0x73, 0xf5, 0xf4, 0x4c, 0xa5, 0x1c, 0x16, 0x51, 0x4f, 0x4d, 0x78,
+	0x8b, 0xf4, 0x84, 0xef, 0x2a, 0x2d, 0x9c, 0xe4, 0xe2, 0x8c, 0x82, 0xa2, 0x32, 0x06, 0x2b, 0x14,
+	0xa1, 0x97, 0x90, 0x1f, 0xe9, 0x5e, 0x9a, 0x67, 0xeb, 0x25, 0x57, 0x84, 0x68, 0x2e, 0xbd, 0x80,
+	0x27, 0xd2, 0x0e, 0x22, 0xdc, 0x89, 0x06, 0x2b, 0x80, 0xc1, 0xbf, 0xce, 0x93, 0x5e, 0xae, 0x9c,
+	0xd7, 0x2e, 0x7a, 0xf3, 0xa1, 0xad, 0xd7, 0x6b, 0x8e, 0xad, 0xb7, 0x51, 0x5a, 0xef, 0x32, 0x69,
+	0x79, 0x01, 0x54, 0x55, 0xca, 0x5e, 0x14, 0x01, 0xe1, 0xdb, 0x89, 0xd2, 0x2f, 0xbd, 0xc0, 0x93,
+	0x38, 0xb0, 0xc9, 0x72, 0x1a, 0x5c, 0x51, 0x85, 0x2e, 0xc5, 0x6e, 0xa3, 0x17, 0x94, 0x21, 0xeb,
+	0xe3, 0x2c, 0x3c, 0x74, 0x71, 0xe5, 0x6f, 0x9e, 0xe6, 0xbe, 0xcc, 0x03, 0xc4, 0x2d, 0x2c, 0x16,
+	0x61, 0xdf, 0x7a, 0x67, 0xda, 0x37, 0xad, 0x05, 0x7e, 0xa7, 0x62, 0xa1, 0x8b, 0xb1, 0xaf, 0xc1,
+	0x32, 0x12, 0x3d, 0x63, 0x2f, 0x4a, 0x30, 0xa0, 0x99, 0x0c, 0xdb, 0x80, 0x1d, 0x01, 0x36, 0xaf,
+	0x30, 0x68, 0x67, 0x77, 0xd2, 0x42, 0x71, 0x27, 0x5d, 0x82, 0xe3, 0x94, 0xcc, 0x39, 0x74, 0x77,
+	0x12, 0x8c, 0x3d, 0x26, 0x2b, 0x00, 0xcd, 0x1d, 0xf2, 0x50, 0xee, 0x24, 0x74, 0x29, 0xe7, 0x2a,
+	0x00, 0xa2, 0xb5, 0x16, 0xbd, 0x13, 0xa9, 0x48, 0x63, 0xb2, 0x12, 0xa2, 0xf9, 0x20, 0x7c, 0x27,
+	0x52, 0x31, 0xc5, 0x64, 0x25, 0x04, 0xd6, 0x03, 0x57, 0xcc, 0x8e, 0x23, 0x31, 0x8e, 0x98, 0x2c,
+	0x23, 0x61, 0xdc, 0x04, 0x13, 0x4d, 0xe0, 0x5d, 0x50, 0xe3, 0xe6, 0x00, 0x1c, 0x21, 0xe6, 0x12,
+	0xc0, 0x5c, 0x56, 0x47, 0x98, 0xd1, 0xe0, 0xe3, 0x01, 0x0f, 0x58, 0x92, 0xd0, 0x17, 0xf0, 0xf4,
+	0x34, 0x05, 0x3a, 0x01, 0x0f, 0x36, 0x6d, 0x67, 0x9f, 0xd3, 0x8b, 0xc8, 0xc9, 0xe9, 0xfc, 0x16,
+	0x7e, 0xf1, 0xb4, 0xb7, 0x30, 0x4c, 0x4f, 0xda, 0xb1, 0xe4, 0xee, 0x6d, 0x49, 0xa9, 0xb2, 0xde,
+	0x1c, 0x28, 0x87, 0xc7, 0x97, 0xaa, 0xe1, 0xf1, 0x22, 0x69, 0x27, 0xde, 0xb7, 0x9c, 0x1d, 0xd1,
+	0x15, 0x54, 0xd2, 0x14, 0x6c, 0x14, 0xb6, 0x84, 0x90, 0x77, 0x13, 0xfa, 0x32, 0xf2, 0x4a, 0x08,
+	0x5c, 0x00, 0x31, 0xc7, 0x01, 0xd4, 0xbd, 0x75, 0x09, 0x43, 0x42, 0x05, 0x83, 0x51, 0x23, 0xe1,
+	0x62, 0xaa, 0xf3, 0x8a, 0x7a, 0x45, 0xd0, 0x24, 0x68, 0xeb, 0x66, 0x12, 0xd9, 0x0e, 0xa7, 0xab,
+	0xc8, 0xae, 0x60, 0x18, 0x1a, 0x85, 0xfb, 0xc8, 0x73, 0xe9, 0xab, 0xc8, 0xd5, 0x94, 0x7a, 0x9b,
+	0x08, 0x86, 0x47, 0x76, 0x44, 0xd7, 0x70, 0xd7, 0x32, 0x12, 0x92, 0xa5, 0x80, 0x07, 0x8f, 0x45,
+	0x7c, 0xe0, 0x85, 0xa3, 0x21, 0x97, 0xf4, 0x35, 0xe4, 0x57, 0x41, 0xe8, 0x37, 0x8d, 0xc0, 0xb1,
+	0xe9, 0x40, 0xad, 0x58, 0x51, 0xd6, 0x5b, 0x64, 0xd1, 0x89, 0xd2, 0x07, 0xf1, 0xee, 0x7e, 0x2c,
+	0xa4, 0xf4, 0xb9, 0x4b, 0x5f, 0x47, 0xf5, 0x1a, 0x8a, 0x17, 0x4a, 0x94, 0xe6, 0x34, 0xa6, 0x05,
+	0x6f, 0xa0, 0xe4, 0x18, 0xae, 0xb2, 0xce, 0x68, 0x5b, 0x6c, 0xf1, 0x43, 0xcf, 0xe1, 0xf4, 0x4d,
+	0x75, 0x91, 0x96, 0x20, 0x6b, 0x9d, 0x2c, 0x95, 0x48, 0x06, 0xde, 0xf1, 0x16, 0xda, 0x4f, 0x1d,
+	0xae, 0x49, 0x3e, 0x06, 0xc9, 0xff, 0x1b, 0x93, 0x04, 0x18, 0x57, 0x22, 0x82, 0x48, 0x24, 0x7c,
+	0x27, 0x16, 0x3f, 0xe5, 0x8e, 0xa4, 0xeb, 0x38, 0x70, 0x0d, 0x2d, 0xc9, 0x0d, 0x79, 0x8c, 0x13,
+	0x7c, 0xbb, 0x22, 0xa7, 0x51, 0xeb, 0x1a, 0xb9, 0xa0, 0xdc, 0xfd, 0xae, 0xed, 0xf9, 0xb0, 0x8b,
+	0x32, 0xe6, 0xf6, 0x01, 0xbd, 0x8c, 0x47, 0x3e, 0x89, 0xa5, 0xa3, 0xd6, 0x43, 0x11, 0x7c, 0xe1,
+	0xf9, 0x7e, 0x42, 0xdf, 0xc9, 0xa3, 0x56, 0x06, 0x61, 0xe0, 0xd0, 0x59, 0xe3, 0xff, 0x2b, 0xdb,
+	0xd0, 0x24, 0x26, 0xb3, 0x10, 0x16, 0x77, 0xed, 0x11, 0xbd, 0x82, 0xac, 0x9c, 0x06, 0xab, 0xe4,
+	0x4e, 0xb2, 0x6b, 0x27, 0x07, 0xb7, 0xe3, 0x90, 0x6e, 0x20, 0xb7, 0x84, 0x80, 0x05, 0x68, 0xea,
+	0xae, 0x1d, 0x78, 0xfe, 0x31, 0xbd, 0x8a, 0x22, 0x55, 0x10, 0xa3, 0xb7, 0x3d, 0x4a, 0xe8, 0x35,
+	0x75, 0xb5, 0x43, 0x1b, 0xfc, 0xc7, 0x17, 0xa3, 0xad, 0xd8, 0x3b, 0xe4, 0x31, 0x7d, 0x17, 0xb5,
+	0x0a, 0x00, 0xd6, 0x13, 0x79, 0x6e, 0xb2, 0x99, 0xc6, 0x31, 0x0f, 0x25, 0xbd, 0xae, 0xd6, 0x53,
+	0x82, 0x40, 0x1f, 0x48, 0x15, 0xa5, 0xdf, 0x43, 0x7e, 0x01, 0xc0, 0xbc, 0xa3, 0xd8, 0x3b, 0xf4,
+	0x7c, 0x3e, 0xe2, 0x2e, 0x7d, 0x1f, 0xd3, 0x82, 0x12, 0x02, 0x6b, 0x1e, 0x45, 0xa9, 0xf2, 0xa4,
+	0x0f, 0x54, 0x02, 0x9f, 0xd1, 0x58, 0x65, 0x44, 0xe9, 0x57, 0x3c, 0x74, 0x45, 0x4c, 0x3f, 0x54,
+	0x33, 0xcb, 0x01, 0x4c, 0xc4, 0x62, 0x3b, 0xd9, 0xff, 0x52, 0x88, 0xc8, 0x0b, 0x47, 0xf4, 0x07,
+	0xd8, 0x77, 0x05, 0x83, 0xde, 0x79, 0xe8, 0x46, 0xc2, 0x0b, 0x25, 0xbd, 0xa1, 0x76, 0x34, 0xa3,
+	0xc1, 0x06, 0xd4, 0x65, 0xb2, 0x13, 0xf3, 0x24, 0x49, 0x63, 0x4e, 0x3f, 0x42, 0xa3, 0xaa, 0xa1,
+	0x98, 0x12, 0x46, 0x69, 0x2e, 0x74, 0x13, 0x85, 0xca, 0x10, 0xac, 0xd1, 0x13, 0xb9, 0xc0, 0xc7,
+	0x28, 0x50, 0x42, 0x80, 0x1f, 0xc0, 0x82, 0xd4, 0x2a, 0x3f, 0xc1, 0x55, 0x96, 0x10, 0x98, 0xc9,
+	0x9e, 0x17, 0xba, 0xf7, 0x0b, 0x99, 0x1f, 0xa2, 0x4c, 0x0d, 0x05, 0xff, 0x83, 0x14, 0x30, 0xe0,
+	0x25, 0xc9, 0x5b, 0x28, 0x39, 0x86, 0xc3, 0xde, 0x79, 0xd1, 0x6d, 0xd7, 0x85, 0x39, 0xd0, 0x4f,
+	0xd5, 0xde, 0xe5, 0x80, 0x5e, 0x3b, 0xe3, 0x09, 0x54, 0x4a, 0xd2, 0x13, 0x21, 0xfd, 0x4c, 0x79,
+	0x7c, 0x15, 0x85, 0x5e, 0x9c, 0x28, 0x1d, 0xee, 0xdb, 0x31, 0x4f, 0xe8, 0x6d, 0x15, 0x5b, 0x73,
+	0xc0, 0x1a, 0x92, 0x39, 0x3b, 0x0c, 0x85, 0xb4, 0xd5, 0x4b, 0xd5, 0x1d, 0xac, 0x5a, 0xdf, 0x3d,
+	0xe9, 0x1e, 0xdd, 0xb8, 0x5d, 0xe8, 0x7c, 0x1e, 0xca, 0xf8, 0x98, 0x95, 0x7b, 0xc1, 0x23, 0x7b,
+	0xea, 0xc9, 0x4d, 0xe1, 0x72, 0xba, 0xa9, 0x0c, 0x22, 0xa3, 0x61, 0x3a, 0x42, 0xb9, 0x11, 0x77,
+	0xe9, 0x16, 0x9e, 0x77, 0x01, 0xac, 0xdc, 0x22, 0xfd, 0x7a, 0xd7, 0xe5, 0xba, 0xaf, 0xa7, 0xee,
+	0xd8, 0x65, 0xd2, 0x3a, 0xb4, 0xfd, 0x54, 0x65, 0x63, 0x0d, 0xa6, 0x88, 0x9b, 0xe6, 0x0d, 0x63,
+	0xf0, 0xfb, 0x6e, 0x9e, 0x67, 0x62, 0x2d, 0xa0, 0x2b, 0x44, 0xa3, 0xa8, 0x10, 0xab, 0x15, 0x91,
+	0x39, 0x56, 0x11, 0x15, 0xe5, 0x59, 0xe3, 0x39, 0xcb, 0xb3, 0xe6, 0xe9, 0xcb, 0x33, 0xc8, 0xb2,
+	0x20, 0x72, 0xe9, 0xd4, 0x15, 0xda, 0x10, 0x5b, 0xe4, 0x7e, 0xcc, 0x6d, 0x37, 0xd1, 0x99, 0x6a,
+	0x46, 0xd6, 0x8b, 0xad, 0xee, 0x78, 0xb1, 0xa5, 0xb7, 0xaa, 0x57, 0xa4, 0x23, 0xb5, 0x62, 0x88,
+	0x8c, 0x17, 0x43, 0xf7, 0x6b, 0xef, 0x64, 0x9c, 0xce, 0x9d, 0x25, 0x15, 0xab, 0x29, 0x5b, 0x3f,
+	0x22, 0xf3, 0x51, 0x71, 0x00, 0x67, 0x2a, 0xfb, 0x2a, 0x8a, 0xd6, 0x0e, 0x59, 0x72, 0xaa, 0x79,
+	0x1b, 0x5d, 0x3a, 0x53, 0x96, 0x57, 0x57, 0x87, 0xf8, 0x9a, 0x43, 0x6c, 0x2f, 0xcf, 0xb0, 0xaa,
+	0x60, 0x45, 0xea, 0xf1, 0x5e, 0x9e, 0x67, 0x55, 0xc1, 0xb1, 0x12, 0xd2, 0x9a, 0x50, 0x42, 0x16,
+	0xf5, 0xeb, 0x85, 0xb3, 0xd4, 0xaf, 0x1b, 0xc4, 0xca, 0xbb, 0x79, 0x90, 0xa7, 0x92, 0x2a, 0x2f,
+	0x9b, 0xc0, 0xa9, 0xcb, 0xeb, 0xe4, 0xf2, 0x85, 0x71, 0x79, 0xc5, 0x81, 0x0b, 0xb1, 0xde, 0x0b,
+	0xa4, 0x93, 0x17, 0x51, 0x61, 0x12, 0xab, 0xae, 0x91, 0x25, 0xa0, 0x2f, 0x8e, 0x6b, 0x68, 0xd6,
+	0xd4, 0xea, 0x99, 0x3e, 0x57, 0xf5, 0xfc, 0xd2, 0x69, 0xab, 0xe7, 0x95, 0x93, 0xab, 0xe7, 0x97,
+	0xa7, 0x54, 0xcf, 0xdf, 0x37, 0xe1, 0xe3, 0x4d, 0xc9, 0x94, 0xc7, 0x4a, 0xc0, 0x52, 0x76, 0x6d,
+	0xce, 0xc8, 0xae, 0x1b, 0xb3, 0xb2, 0xeb, 0x66, 0x2d, 0xbb, 0x9e, 0x55, 0x3c, 0x15, 0x99, 0x77,
+	0x7b, 0x6a, 0xe6, 0xdd, 0xa9, 0x65, 0xde, 0x8a, 0xa7, 0xfa, 0xeb, 0xe6, 0x3c, 0xd5, 0x5f, 0x56,
+	0xd3, 0xf4, 0x26, 0xd4, 0x34, 0xa4, 0x54, 0xd3, 0x54, 0x2a, 0x98, 0xb9, 0x99, 0x15, 0xcc, 0xfc,
+	0xec, 0x0a, 0x66, 0xe1, 0x84, 0x0a, 0x66, 0x71, 0xac, 0x82, 0xc9, 0xcb, 0xc1, 0xa5, 0xff, 0xaa,
+	0x1c, 0xec, 0x3f, 0x57, 0x39, 0xa8, 0xa3, 0xe7, 0xf9, 0x4a, 0x31, 0x57, 0xd4, 0x25, 0xd6, 0x8c,
+	0xba, 0xe4, 0x42, 0xc5, 0xf0, 0x06, 0xbf, 0x36, 0x08, 0x29, 0x1e, 0xf6, 0x61, 0x97, 0xd3, 0x34,
+	0xb7, 0x25, 0x6c, 0x5b, 0x57, 0x88, 0x29, 0x12, 0x6a, 0xce, 0x0c, 0x0c, 0x0f, 0x87, 0xa0, 0xce,
+	0x4c, 0x01, 0x0e, 0xd5, 0x74, 0xd4, 0xc3, 0x70, 0x63, 0xf6, 0xe5, 0x82, 0x1a, 0x28, 0x5b, 0x7f,
+	0x35, 0x6e, 0x8d, 0xbd, 0x1a, 0x0f, 0xbe, 0x33, 0x48, 0xfb, 0xe1, 0x30, 0x9b, 0xe3, 0xd8, 0x8b,
+	0xcc, 0x0a, 0xe9, 0x46, 0xbe, 0x2d, 0x9f, 0x88, 0x38, 0xc8, 0x9e, 0x7b, 0x33, 0x1a, 0xac, 0xf3,
+	0x89, 0xca, 0x52, 0xd5, 0x13, 0x81, 0xa6, 0x60, 0x53, 0x0e, 0x79, 0x9c, 0x40, 0x3e, 0xa2, 0x9e,
+	0x09, 0x32, 0x12, 0x02, 0xeb, 0x01, 0x8f, 0x43, 0xee, 0x7f, 0xa5, 0xf9, 0x2d, 0x95, 0xde, 0x56,
+	0x40, 0x9c, 0x92, 0x0a, 0x88, 0x30, 0x3c, 0x5c, 0x7c, 0xcc, 0x96, 0x6a, 0x5a, 0x26, 0xcb, 0x69,
+	0x38, 0x99, 0xa3, 0xd8, 0x93, 0x1c, 0x99, 0xca, 0x1d, 0x0b, 0x00, 0x86, 0x02, 0x49, 0xf0, 0xed,
+	0x04, 0x25, 0x94, 0x53, 0x56, 0x41, 0xc8, 0xa0, 0x50, 0xa5, 0x10, 0x53, 0xee, 0x59, 0x43, 0x07,
+	0x7f, 0x31, 0x08, 0x29, 0x3e, 0xd2, 0x4d, 0xc8, 0x29, 0x16, 0x89, 0xf9, 0x24, 0x7b, 0xb8, 0x32,
+	0x9f, 0xb8, 0xb5, 0xbd, 0x69, 0xe5, 0x7b, 0x33, 0xe1, 0xa3, 0xb1, 0xf5, 0x2e, 0x69, 0xf9, 0xb6,
+	0xeb, 0x66, 0xef, 0xc8, 0xd3, 0x8a, 0x65, 0xc8, 0xfa, 0x98, 0x92, 0x04, 0x95, 0x18, 0x55, 0xda,
+	0xa7, 0x50, 0x41, 0x49, 0x2c, 0x94, 0xd5, 0x87, 0xef, 0x8e, 0x3a, 0x2d, 0x45, 0x0d, 0x7e, 0x42,
+	0x9a, 0x20, 0x96, 0x57, 0xec, 0xc6, 0x69, 0x2b, 0x76, 0x08, 0x8e, 0x51, 0xfe, 0x5e, 0x14, 0xe1,
+	0xf3, 0xa0, 0x88, 0xa5, 0x5e, 0x30, 0xb6, 0x07, 0xbf, 0x33, 0x08, 0x29, 0xd2, 0x24, 0xd8, 0xb7,
+	0x38, 0x51, 0xdf, 0x00, 0x9a, 0x0c, 0x9a, 0x80, 0x1c, 0x06, 0xca, 0x09, 0x9a, 0x0c, 0x9a, 0xd0,
+	0x4d, 0x02, 0xb5, 0x71, 0x03, 0x21, 0x6c, 0xe3, 0xdc, 0x21, 0x59, 0x55, 0xaf, 0x7e, 0x4d, 0xa6,
+	0x29, 0xdc, 0x4d, 0xfe, 0x54, 0xc5, 0xcd, 0x26, 0xc3, 0x36, 0xf4, 0xe8, 0x7b, 0x7b, 0x3a, 0x60,
+	0x42, 0x13, 0xa4, 0x60, 0x31, 0x3a, 0x52, 0x62, 0x1b, 0xf2, 0x47, 0xd7, 0x8b, 0xe5, 0xb1, 0x0e,
+	0x91, 0x8a, 0x18, 0xfc, 0xca, 0x24, 0x1d, 0x9d, 0x9d, 0x81, 0x15, 0xfb, 0x76, 0x22, 0x37, 0xa3,
+	0x54, 0x3b, 0x44, 0x46, 0x56, 0xa2, 0xb9, 0x59, 0x8b, 0xe6, 0xa5, 0x1b, 0xa2, 0x31, 0xe3, 0x86,
+	0x68, 0xd6, 0x6f, 0x08, 0x88, 0x8a, 0x69, 0xb0, 0xab, 0xb3, 0x3e, 0x95, 0x0c, 0x96, 0x10, 0xeb,
+	0x86, 0x76, 0xfe, 0xf6, 0xcc, 0x6f, 0x4a, 0x43, 0x2f, 0x1c, 0xf9, 0x3c, 0xcb, 0x2f, 0x51, 0x23,
+	0x4f, 0x30, 0x3b, 0xa5, 0x04, 0x73, 0x85, 0x74, 0x61, 0x5a, 0x98, 0xff, 0x76, 0x31, 0x26, 0xe4,
+	0x34, 0xcc, 0x44, 0x4d, 0xab, 0xfc, 0xbd, 0xa0, 0x40, 0x06, 0x9f, 0x92, 0x85, 0xca, 0x30, 0xd3,
+	0xc2, 0xc6, 0xb4, 0x2d, 0x1a, 0xfc, 0xd3, 0xc0, 0x4d, 0xc6, 0x90, 0x73, 0x91, 0xb4, 0xc3, 0x34,
+	0xd8, 0xd3, 0xff, 0xf5, 0x68, 0x31, 0x4d, 0x01, 0x7e, 0xa8, 0x0a, 0x46, 0x65, 0x5f, 0x9a, 0x9a,
+	0x1a, 0x72, 0x96, 0x49, 0x2b, 0x10, 0x2e, 0xf7, 0xb3, 0x77, 0x49, 0x24, 0xb0, 0x6a, 0xdd, 0x3f,
+	0x4e, 0x3c, 0xc7, 0xf6, 0xf5, 0x57, 0xb1, 0x1e, 0x2b, 0x21, 0xd0, 0x9b, 0x23, 0x62, 0xae, 0x3f,
+	0x8c, 0xf5, 0x98, 0xa6, 0xa0, 0x37, 0x68, 0x65, 0xd9, 0xb7, 0x22, 0xc0, 0xb0, 0x82, 0xfd, 0x6f,
+	0xf5, 0x7e, 0x41, 0x13, 0xeb, 0x2a, 0xb8, 0x73, 0xf1, 0xfb, 0x59, 0x0f, 0x65, 0x0b, 0x60, 0xf0,
+	0x47, 0x83, 0x34, 0xef, 0x65, 0x8e, 0x92, 0x05, 0x0b, 0xd3, 0x2b, 0x7d, 0x20, 0x37, 0xcb, 0x1f,
+	0xc8, 0x27, 0x3d, 0xb7, 0xbe, 0xa7, 0x0b, 0xfd, 0x26, 0x9e, 0xfa, 0xab, 0x33, 0x7c, 0x72, 0xd7,
+	0x1e, 0x25, 0xfa, 0x25, 0x80, 0x92, 0x8e, 0xed, 0xfb, 0x00, 0xa0, 0xb5, 0xf4, 0x58, 0x46, 0x96,
+	0xbf, 0x2e, 0x76, 0x66, 0x7e, 0x5d, 0xec, 0x8e, 0xdf, 0x13, 0xb7, 0x48, 0x37, 0x1b, 0x07, 0x4d,
+	0x44, 0xa4, 0xb1, 0xc3, 0x77, 0xb3, 0x37, 0xe4, 0x05, 0x56, 0x42, 0xf2, 0xf7, 0x09, 0xb3, 0x78,
+	0x9f, 0xb8, 0xec, 0x91, 0xc5, 0xea, 0x95, 0x6d, 0xcd, 0x91, 0x4e, 0x1a, 0x1e, 0x84, 0xe2, 0x28,
+	0xec, 0x9f, 0x03, 0x42, 0x3f, 0xbc, 0xf6, 0x0d, 0x6b, 0x91, 0x10, 0xfd, 0x0e, 0xe7, 0x85, 0xa3,
+	0xbe, 0x09, 0xcc, 0x38, 0x0d, 0x43, 0x20, 0x1a, 0x16, 0x21, 0xed, 0xc8, 0x4e, 0x13, 0xee, 0xf6,
+	0x9b, 0xd0, 0x86, 0xaa, 0x92, 0xbb, 0xfd, 0x96, 0xd5, 0x25, 0x4d, 0x97, 0xdb, 0x6e, 0xbf, 0x7d,
+	0xf9, 0x01, 0x59, 0xca, 0x87, 0xd2, 0x79, 0xff, 0x79, 0xb2, 0xa0, 0xc7, 0x52, 0x40, 0xff, 0x9c,
+	0x35, 0x4f, 0xba, 0xf9, 0x10, 0x06, 0x0c, 0xa1, 0x52, 0x80, 0xe3, 0xbe, 0x69, 0x2d, 0x90, 0x5e,
+	0x1a, 0x66, 0x64, 0xe3, 0xf2, 0x5d, 0x32, 0x5f, 0x2e, 0x52, 0xac, 0x16, 0x31, 0x1e, 0xf5, 0xcf,
+	0xc1, 0xcf, 0x56, 0xdf, 0x80, 0x1f, 0xd6, 0x37, 0xe1, 0x67, 0xd8, 0x6f, 0xc0, 0xcf, 0x6e, 0xbf,
+	0x09, 0x3f, 0x8f, 0xfb, 0x2d, 0xf8, 0xf9, 0x71, 0xbf, 0x0d, 0x3f, 0x5f, 0xf7, 0x3b, 0x77, 0x3e,
+	0xfb, 0xc3, 0xb3, 0x55, 0xe3, 0x4f, 0xcf, 0x56, 0x8d, 0xbf, 0x3d, 0x5b, 0x35, 0xbe, 0xfb, 0xfb,
+	0xea, 0xb9, 0xaf, 0x37, 0x26, 0xfc, 0x63, 0x4a, 0x9f, 0xf1, 0x15, 0x7d, 0xc6, 0x57, 0xf0, 0x8c,
+	0xaf, 0xa2, 0x41, 0xef, 0xb5, 0xf1, 0x2f, 0x53, 0xef, 0xfd, 0x67, 0x00, 0x95, 0x53, 0x6b, 0x75,
+	0x8e, 0x25, 0x00, 0x00,
 }
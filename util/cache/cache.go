@@ -27,6 +27,13 @@ func Get(key string) (interface{}, bool) {
 	return globalCache.get(key)
 }
 
+// Delete removes a value from the global memory cache, e.g. to reset it
+// between tests.
+func Delete(key string) {
+	ensureGlobalCache()
+	globalCache.delete(key)
+}
+
 // Memory cache is a simple thread-safe in-memory cache.
 type memoryCache struct {
 	cache  map[string]interface{}
@@ -66,6 +73,13 @@ func (c *memoryCache) get(key string) (interface{}, bool) {
 	return v, ok
 }
 
+func (c *memoryCache) delete(key string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.cache, key)
+	delete(c.expiry, key)
+}
+
 func ensureGlobalCache() {
 	if globalCache == nil {
 		globalCache = newMemoryCache()
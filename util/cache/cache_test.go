@@ -27,3 +27,14 @@ func TestMemoryCache(t *testing.T) {
 	assert.False(t, ok)
 	assert.Nil(t, v)
 }
+
+func TestMemoryCacheDelete(t *testing.T) {
+	SetWithTTL("to-delete", "foo", 5*time.Minute)
+	Delete("to-delete")
+	v, ok := Get("to-delete")
+	assert.False(t, ok)
+	assert.Nil(t, v)
+
+	// Deleting a missing key is a no-op.
+	Delete("to-delete")
+}
@@ -2,8 +2,10 @@ package docker
 
 import (
 	"bufio"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"math"
 	"os"
@@ -12,8 +14,10 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/DataDog/datadog-process-agent/util"
+	"github.com/DataDog/datadog-process-agent/util/cache"
 	log "github.com/cihub/seelog"
 )
 
@@ -21,6 +25,8 @@ var (
 	containerRe = regexp.MustCompile("[0-9a-f]{64}")
 	// ErrMissingTarget is an error set when a cgroup target is missing.
 	ErrMissingTarget = errors.New("Missing cgroup target")
+
+	errNoCgroupMounts = errors.New("mounts file does not exist")
 )
 
 // CgroupMemStat stores memory statistics about a cgroup.
@@ -55,6 +61,17 @@ type CgroupMemStat struct {
 	TotalUnevictable        uint64
 	MemUsageInBytes         uint64
 	MemFailCnt              uint64
+	// Swap is 0 when swap accounting is disabled.
+	Swap uint64
+	// WorkingSet is the usage minus the inactive file cache.
+	WorkingSet uint64
+	// OOMKills is the number of processes killed by the OOM killer in the
+	// cgroup since it was created. It is 0 on kernels not reporting it.
+	OOMKills uint64
+	// MemoryPressure is the percentage of time some tasks of the cgroup were
+	// stalled on memory over the last 10 seconds. It is 0 without PSI, e.g.
+	// on cgroup v1.
+	MemoryPressure float64
 }
 
 // CgroupTimesStat stores CPU times for a cgroup.
@@ -62,6 +79,13 @@ type CgroupTimesStat struct {
 	ContainerID string
 	System      uint64
 	User        uint64
+	// NrThrottled is the number of CFS periods the cgroup was throttled in
+	// and ThrottledTime the total time it was throttled for in nanoseconds.
+	NrThrottled   uint64
+	ThrottledTime uint64
+	// CPUPressure is the percentage of time some tasks of the cgroup were
+	// stalled on CPU over the last 10 seconds, 0 without PSI.
+	CPUPressure float64
 }
 
 // CgroupIOStat store I/O statistics about a cgroup.
@@ -69,6 +93,18 @@ type CgroupIOStat struct {
 	ContainerID string
 	ReadBytes   uint64
 	WriteBytes  uint64
+	// Devices holds the stats of each block device by name, or by
+	// major:minor if the name couldn't be resolved.
+	Devices map[string]IODeviceStat
+	// IOPressure is the percentage of time some tasks of the cgroup were
+	// stalled on I/O over the last 10 seconds, 0 without PSI.
+	IOPressure float64
+}
+
+// IODeviceStat stores the I/O statistics of a cgroup on a block device.
+type IODeviceStat struct {
+	ReadBytes  uint64
+	WriteBytes uint64
 }
 
 // ContainerCgroup is a structure that stores paths and mounts for a cgroup.
@@ -79,11 +115,17 @@ type ContainerCgroup struct {
 	Pids        []int32
 	Paths       map[string]string
 	Mounts      map[string]string
+
+	// v2 is true when the host uses the cgroup v2 unified hierarchy.
+	v2 bool
 }
 
 // Mem returns the memory statistics for a Cgroup. If the cgroup file is not
 // availble then we return an empty stats file.
 func (c ContainerCgroup) Mem() (*CgroupMemStat, error) {
+	if c.v2 {
+		return c.memV2()
+	}
 	ret := &CgroupMemStat{ContainerID: c.ContainerID}
 	statfile := c.cgroupFilePath("memory", "memory.stat")
 
@@ -97,78 +139,143 @@ func (c ContainerCgroup) Mem() (*CgroupMemStat, error) {
 	defer f.Close()
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
-		fields := strings.Split(scanner.Text(), " ")
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
 		v, err := strconv.ParseUint(fields[1], 10, 64)
 		if err != nil {
 			continue
 		}
-		switch fields[0] {
-		case "cache":
-			ret.Cache = v
-		case "rss":
-			ret.RSS = v
-		case "rssHuge":
-			ret.RSSHuge = v
-		case "mappedFile":
-			ret.MappedFile = v
-		case "pgpgin":
-			ret.Pgpgin = v
-		case "pgpgout":
-			ret.Pgpgout = v
-		case "pgfault":
-			ret.Pgfault = v
-		case "pgmajfault":
-			ret.Pgmajfault = v
-		case "inactiveAnon":
-			ret.InactiveAnon = v
-		case "activeAnon":
-			ret.ActiveAnon = v
-		case "inactiveFile":
-			ret.InactiveFile = v
-		case "activeFile":
-			ret.ActiveFile = v
-		case "unevictable":
-			ret.Unevictable = v
-		case "hierarchicalMemoryLimit":
-			ret.HierarchicalMemoryLimit = v
-		case "totalCache":
-			ret.TotalCache = v
-		case "totalRss":
-			ret.TotalRSS = v
-		case "totalRssHuge":
-			ret.TotalRSSHuge = v
-		case "totalMappedFile":
-			ret.TotalMappedFile = v
-		case "totalPgpgin":
-			ret.TotalPgpgIn = v
-		case "totalPgpgout":
-			ret.TotalPgpgOut = v
-		case "totalPgfault":
-			ret.TotalPgFault = v
-		case "totalPgmajfault":
-			ret.TotalPgMajFault = v
-		case "totalInactiveAnon":
-			ret.TotalInactiveAnon = v
-		case "totalActiveAnon":
-			ret.TotalActiveAnon = v
-		case "totalInactiveFile":
-			ret.TotalInactiveFile = v
-		case "totalActiveFile":
-			ret.TotalActiveFile = v
-		case "totalUnevictable":
-			ret.TotalUnevictable = v
-		}
+		ret.setV1(fields[0], v)
 	}
 	if err := scanner.Err(); err != nil {
 		return ret, fmt.Errorf("error reading %s: %s", statfile, err)
 	}
+
+	ret.MemUsageInBytes, err = c.memValue("memory.usage_in_bytes")
+	if err != nil {
+		return ret, err
+	}
+	// The usage includes the children cgroups so we use the hierarchical
+	// total_inactive_file.
+	ret.WorkingSet = workingSet(ret.MemUsageInBytes, ret.TotalInactiveFile)
+	// oom_kill was added to memory.oom_control in Linux 4.13.
+	ret.OOMKills, err = c.memKeyedValue("memory.oom_control", "oom_kill")
+	if err != nil {
+		return ret, err
+	}
 	return ret, nil
 }
 
+// setV1 sets the memory stat from a memory.stat key of cgroup v1, unknown
+// keys are ignored. The keys are the snake_case names of the kernel, e.g.
+// total_inactive_file, not the camelCase names of the docker stats API.
+func (m *CgroupMemStat) setV1(key string, v uint64) {
+	switch key {
+	case "cache":
+		m.Cache = v
+	case "rss":
+		m.RSS = v
+	case "rss_huge":
+		m.RSSHuge = v
+	case "mapped_file":
+		m.MappedFile = v
+	case "pgpgin":
+		m.Pgpgin = v
+	case "pgpgout":
+		m.Pgpgout = v
+	case "pgfault":
+		m.Pgfault = v
+	case "pgmajfault":
+		m.Pgmajfault = v
+	case "inactive_anon":
+		m.InactiveAnon = v
+	case "active_anon":
+		m.ActiveAnon = v
+	case "inactive_file":
+		m.InactiveFile = v
+	case "active_file":
+		m.ActiveFile = v
+	case "unevictable":
+		m.Unevictable = v
+	case "hierarchical_memory_limit":
+		m.HierarchicalMemoryLimit = v
+	case "total_cache":
+		m.TotalCache = v
+	case "total_rss":
+		m.TotalRSS = v
+	case "total_rss_huge":
+		m.TotalRSSHuge = v
+	case "total_mapped_file":
+		m.TotalMappedFile = v
+	case "total_pgpgin":
+		m.TotalPgpgIn = v
+	case "total_pgpgout":
+		m.TotalPgpgOut = v
+	case "total_pgfault":
+		m.TotalPgFault = v
+	case "total_pgmajfault":
+		m.TotalPgMajFault = v
+	case "total_inactive_anon":
+		m.TotalInactiveAnon = v
+	case "total_active_anon":
+		m.TotalActiveAnon = v
+	case "total_inactive_file":
+		m.TotalInactiveFile = v
+	case "total_active_file":
+		m.TotalActiveFile = v
+	case "total_unevictable":
+		m.TotalUnevictable = v
+	case "total_swap":
+		// Only reported when swap accounting is enabled.
+		m.Swap = v
+	}
+}
+
+// workingSet returns the memory usage minus the inactive file cache which
+// the kernel reclaims first under pressure, clamped at 0.
+func workingSet(usage, inactiveFile uint64) uint64 {
+	if inactiveFile > usage {
+		return 0
+	}
+	return usage - inactiveFile
+}
+
 // MemLimit returns the memory limit of the cgroup, if it exists. If the file does not
 // exist or there is no limit then this will default to 0.
 func (c ContainerCgroup) MemLimit() (uint64, error) {
-	statfile := c.cgroupFilePath("memory", "memory.limit_in_bytes")
+	if c.v2 {
+		return c.memValue("memory.max")
+	}
+	return c.memValue("memory.limit_in_bytes")
+}
+
+// MemSoftLimit returns the memory soft limit (cgroup v1 memory.soft_limit_in_bytes)
+// of the cgroup. If the file does not exist or there is no limit this will default to 0.
+func (c ContainerCgroup) MemSoftLimit() (uint64, error) {
+	if c.v2 {
+		return 0, nil
+	}
+	return c.memValue("memory.soft_limit_in_bytes")
+}
+
+// MemLow returns the best-effort memory protection (cgroup v2 memory.low) of
+// the cgroup. If the file does not exist or it is unset this will default to 0.
+func (c ContainerCgroup) MemLow() (uint64, error) {
+	return c.memValue("memory.low")
+}
+
+// MemMin returns the hard memory protection (cgroup v2 memory.min) of the
+// cgroup. If the file does not exist or it is unset this will default to 0.
+func (c ContainerCgroup) MemMin() (uint64, error) {
+	return c.memValue("memory.min")
+}
+
+// memValue reads a file from the memory cgroup containing a single value in bytes.
+// Missing files and values meaning "no limit" will return 0.
+func (c ContainerCgroup) memValue(file string) (uint64, error) {
+	statfile := c.cgroupFilePath("memory", file)
 	lines, err := util.ReadLines(statfile)
 	if os.IsNotExist(err) {
 		log.Debugf("missing cgroup file: %s", statfile)
@@ -179,21 +286,203 @@ func (c ContainerCgroup) MemLimit() (uint64, error) {
 	if len(lines) != 1 {
 		return 0, fmt.Errorf("wrong format file: %s", statfile)
 	}
+	// cgroup v2 uses "max" when there is no limit.
+	if lines[0] == "max" {
+		return 0, nil
+	}
 	v, err := strconv.ParseUint(lines[0], 10, 64)
 	if err != nil {
 		return 0, err
 	}
-	// limit_in_bytes is a special case here, it's possible that it shows a ridiculous number,
-	// in which case it represents unlimited, so return 0 here
-	if v > uint64(math.Pow(2, 60)) {
+	if isUnlimitedMem(v) {
 		v = 0
 	}
 	return v, nil
 }
 
+// memPageSizes are the page sizes of the kernels the agent runs on.
+var memPageSizes = []uint64{4096, 16384, 65536}
+
+// isUnlimitedMem returns true if v is the value cgroup v1 reports when there
+// is no limit: the largest page counter times the page size, that is
+// math.MaxInt64 rounded down to the page size on 64-bit kernels and
+// math.MaxInt32 pages on 32-bit ones. Older kernels report math.MaxUint64.
+func isUnlimitedMem(v uint64) bool {
+	for _, pageSize := range memPageSizes {
+		if v == math.MaxInt64/pageSize*pageSize || v == math.MaxInt32*pageSize {
+			return true
+		}
+	}
+	// No machine has that much memory, anything larger is a sentinel too.
+	return v > 1<<60
+}
+
+// PidsCurrent returns the number of processes in the cgroup from the pids
+// controller. ok is false if the controller isn't available.
+func (c ContainerCgroup) PidsCurrent() (v uint64, ok bool, err error) {
+	return c.pidsValue("pids.current")
+}
+
+// PidsLimit returns the maximum number of processes of the cgroup from the
+// pids controller. It defaults to 0 if there is no limit or the controller
+// isn't available.
+func (c ContainerCgroup) PidsLimit() (uint64, error) {
+	v, _, err := c.pidsValue("pids.max")
+	return v, err
+}
+
+// pidsValue reads a file from the pids cgroup containing a single value, ok is
+// false if the controller isn't mounted or enabled for the cgroup. "max"
+// values return 0.
+func (c ContainerCgroup) pidsValue(file string) (v uint64, ok bool, err error) {
+	target := "pids"
+	if c.v2 {
+		target = unifiedTarget
+	}
+	_, mounted := c.Mounts[target]
+	_, hasPath := c.Paths[target]
+	if !mounted || !hasPath {
+		return 0, false, nil
+	}
+	statfile := c.cgroupFilePath(target, file)
+	lines, err := util.ReadLines(statfile)
+	if os.IsNotExist(err) {
+		log.Debugf("missing cgroup file: %s", statfile)
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	if len(lines) != 1 {
+		return 0, false, fmt.Errorf("wrong format file: %s", statfile)
+	}
+	if lines[0] == "max" {
+		return 0, true, nil
+	}
+	v, err = strconv.ParseUint(lines[0], 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return v, true, nil
+}
+
+// CpusetCount returns the number of CPUs the cgroup may run on from the
+// cpuset controller. It defaults to 0 if the controller isn't available.
+func (c ContainerCgroup) CpusetCount() (int, error) {
+	target, file := "cpuset", "cpuset.cpus"
+	if c.v2 {
+		target, file = unifiedTarget, "cpuset.cpus.effective"
+	}
+	_, mounted := c.Mounts[target]
+	_, hasPath := c.Paths[target]
+	if !mounted || !hasPath {
+		return 0, nil
+	}
+	statfile := c.cgroupFilePath(target, file)
+	lines, err := util.ReadLines(statfile)
+	if os.IsNotExist(err) {
+		log.Debugf("missing cgroup file: %s", statfile)
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if len(lines) == 0 {
+		return 0, nil
+	}
+	return parseCPUSet(lines[0])
+}
+
+// CPUShares returns the relative CPU weight of the cgroup in cpu.shares units,
+// from 2 to 262144 and 1024 by default. The cgroup v2 cpu.weight, from 1 to
+// 10000, is converted to shares. It defaults to 0 if the file is missing.
+func (c ContainerCgroup) CPUShares() (uint64, error) {
+	file := "cpu.shares"
+	if c.v2 {
+		file = "cpu.weight"
+	}
+	statfile := c.cgroupFilePath("cpu", file)
+	lines, err := util.ReadLines(statfile)
+	if os.IsNotExist(err) {
+		log.Debugf("missing cgroup file: %s", statfile)
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if len(lines) != 1 {
+		return 0, fmt.Errorf("wrong format file: %s", statfile)
+	}
+	v, err := strconv.ParseUint(lines[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if c.v2 {
+		return weightToShares(v), nil
+	}
+	return v, nil
+}
+
+// weightToShares converts a cgroup v2 cpu.weight to cpu.shares, reversing the
+// conversion of the runtimes setting the weight from the shares. The weight
+// is coarser so the default 1024 shares come back as 998.
+func weightToShares(weight uint64) uint64 {
+	if weight == 0 {
+		return 0
+	}
+	return 2 + (weight-1)*262142/9999
+}
+
+// parseCPUSet returns the number of CPUs of a cpuset list, e.g. 4 for
+// "0-2,8". An empty list returns 0.
+func parseCPUSet(s string) (int, error) {
+	count := 0
+	for _, part := range strings.Split(strings.TrimSpace(s), ",") {
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return 0, fmt.Errorf("invalid cpuset %q: %s", s, err)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, fmt.Errorf("invalid cpuset %q: %s", s, err)
+			}
+		}
+		if first < 0 || last < first {
+			return 0, fmt.Errorf("invalid cpuset %q", s)
+		}
+		count += last - first + 1
+	}
+	return count, nil
+}
+
+// memKeyedValue reads the value of a key from a file of the memory cgroup
+// with a "key value" pair per line. Missing files and keys return 0.
+func (c ContainerCgroup) memKeyedValue(file, key string) (uint64, error) {
+	statfile := c.cgroupFilePath("memory", file)
+	lines, err := util.ReadLines(statfile)
+	if os.IsNotExist(err) {
+		log.Debugf("missing cgroup file: %s", statfile)
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == key {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	return 0, nil
+}
+
 // CPU returns the CPU status for this cgroup instance
 // If the cgroup file does not exist then we just log debug return nothing.
 func (c ContainerCgroup) CPU() (*CgroupTimesStat, error) {
+	if c.v2 {
+		return c.cpuV2()
+	}
 	ret := &CgroupTimesStat{ContainerID: c.ContainerID}
 	statfile := c.cgroupFilePath("cpuacct", "cpuacct.stat")
 	f, err := os.Open(statfile)
@@ -223,9 +512,42 @@ func (c ContainerCgroup) CPU() (*CgroupTimesStat, error) {
 	if err := scanner.Err(); err != nil {
 		return ret, fmt.Errorf("error reading %s: %s", statfile, err)
 	}
+	if err := c.cpuThrottling(ret); err != nil {
+		return ret, err
+	}
 	return ret, nil
 }
 
+// cpuThrottling reads the CFS throttling stats from the cpu controller's
+// cpu.stat, which is separate from cpuacct.stat on cgroup v1.
+func (c ContainerCgroup) cpuThrottling(ret *CgroupTimesStat) error {
+	statfile := c.cgroupFilePath("cpu", "cpu.stat")
+	lines, err := util.ReadLines(statfile)
+	if os.IsNotExist(err) {
+		log.Debugf("missing cgroup file: %s", statfile)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "nr_throttled":
+			ret.NrThrottled = v
+		case "throttled_time":
+			ret.ThrottledTime = v
+		}
+	}
+	return nil
+}
+
 // CPULimit would show CPU limit for this cgroup.
 // It does so by checking the cpu period and cpu quota config
 // if a user does this:
@@ -236,6 +558,9 @@ func (c ContainerCgroup) CPU() (*CgroupTimesStat, error) {
 // If the limits files aren't available (on older version) then
 // we'll return the default value of 100.
 func (c ContainerCgroup) CPULimit() (float64, error) {
+	if c.v2 {
+		return c.cpuLimitV2()
+	}
 	periodFile := c.cgroupFilePath("cpu", "cpu.cfs_period_us")
 	quotaFile := c.cgroupFilePath("cpu", "cpu.cfs_quota_us")
 	plines, err := util.ReadLines(periodFile)
@@ -283,7 +608,10 @@ func (c ContainerCgroup) CPULimit() (float64, error) {
 // 252:0 Total 58945536
 //
 func (c ContainerCgroup) IO() (*CgroupIOStat, error) {
-	ret := &CgroupIOStat{ContainerID: c.ContainerID}
+	if c.v2 {
+		return c.ioV2()
+	}
+	ret := &CgroupIOStat{ContainerID: c.ContainerID, Devices: make(map[string]IODeviceStat)}
 	statfile := c.cgroupFilePath("blkio", "blkio.throttle.io_service_bytes")
 	f, err := os.Open(statfile)
 	if os.IsNotExist(err) {
@@ -294,20 +622,39 @@ func (c ContainerCgroup) IO() (*CgroupIOStat, error) {
 	}
 	defer f.Close()
 
+	// The file has a line per device and operation, e.g.
+	//
+	// 8:0 Read 49225728
+	// 8:0 Write 9850880
+	// 8:0 Total 59076608
+	// Total 59076608
+	//
+	// ReadBytes and WriteBytes are the sums over all the devices, not the
+	// values of the last device listed.
+	devices := blockDevices()
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
-		fields := strings.Split(scanner.Text(), " ")
-		if fields[1] == "Read" {
-			read, err := strconv.ParseUint(fields[2], 10, 64)
-			if err == nil {
-				ret.ReadBytes = read
-			}
-		} else if fields[1] == "Write" {
-			write, err := strconv.ParseUint(fields[2], 10, 64)
-			if err == nil {
-				ret.WriteBytes = write
-			}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
 		}
+		name := deviceName(devices, fields[0])
+		dev := ret.Devices[name]
+		switch fields[1] {
+		case "Read":
+			dev.ReadBytes += v
+			ret.ReadBytes += v
+		case "Write":
+			dev.WriteBytes += v
+			ret.WriteBytes += v
+		default:
+			continue
+		}
+		ret.Devices[name] = dev
 	}
 	if err := scanner.Err(); err != nil {
 		return ret, fmt.Errorf("error reading %s: %s", statfile, err)
@@ -315,6 +662,79 @@ func (c ContainerCgroup) IO() (*CgroupIOStat, error) {
 	return ret, nil
 }
 
+const (
+	// partitionsCacheKey is the cache key of the block device names.
+	partitionsCacheKey = "dockerutil.partitions"
+	// partitionsCacheTTL is how long the block device names are reused.
+	partitionsCacheTTL = time.Minute
+)
+
+// blockDevices returns the block device names by major:minor from the host
+// partitions file. The result is cached since devices rarely change.
+func blockDevices() map[string]string {
+	if cached, ok := cache.Get(partitionsCacheKey); ok {
+		if devices, ok := cached.(map[string]string); ok {
+			return devices
+		}
+	}
+	lines, err := util.ReadLines(util.HostProc("partitions"))
+	if err != nil {
+		log.Debugf("could not read the block device names: %s", err)
+	}
+	devices := parsePartitions(lines)
+	cache.SetWithTTL(partitionsCacheKey, devices, partitionsCacheTTL)
+	return devices
+}
+
+// parsePartitions parses the lines of /proc/partitions, e.g.
+//
+// major minor  #blocks  name
+//
+//	8        0  488386584 sda
+//	8        1     524288 sda1
+func parsePartitions(lines []string) map[string]string {
+	devices := make(map[string]string)
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[0]); err != nil {
+			continue
+		}
+		devices[fields[0]+":"+fields[1]] = fields[3]
+	}
+	return devices
+}
+
+// deviceName returns the name of the major:minor device, or the major:minor
+// itself if it's unknown.
+func deviceName(devices map[string]string, majorMinor string) string {
+	if name, ok := devices[majorMinor]; ok {
+		return name
+	}
+	return majorMinor
+}
+
+// Path returns the directory of the memory controller of the cgroup, or of
+// the unified hierarchy on cgroup v2, e.g. /sys/fs/cgroup/memory/docker/<id>.
+// It is empty if the memory controller isn't mounted.
+func (c ContainerCgroup) Path() string {
+	target := "memory"
+	if c.v2 {
+		target = unifiedTarget
+	}
+	mount, ok := c.Mounts[target]
+	if !ok {
+		return ""
+	}
+	targetPath, ok := c.Paths[target]
+	if !ok {
+		return ""
+	}
+	return filepath.Join(mount, targetPath)
+}
+
 // ContainerStartTime gets the stat for cgroup directory and use the mtime for that dir to determine the start time for the container
 // this should work because the cgroup dir for the container would be created only when it's started
 func (c ContainerCgroup) ContainerStartTime() (int64, error) {
@@ -331,6 +751,10 @@ func (c ContainerCgroup) ContainerStartTime() (int64, error) {
 
 // cgroupFilePath constructs file path to get targetted stats file.
 func (c ContainerCgroup) cgroupFilePath(target, file string) string {
+	// All controllers share the same hierarchy on cgroup v2.
+	if c.v2 {
+		target = unifiedTarget
+	}
 	mount, ok := c.Mounts[target]
 	if !ok {
 		log.Errorf("missing target %s from mounts", target)
@@ -357,52 +781,182 @@ func (c ContainerCgroup) cgroupFilePath(target, file string) string {
 //	 cgroup /sys/fs/cgroup/perf_event cgroup rw,relatime,perf_event 0 0
 //	 cgroup /sys/fs/cgroup/hugetlb cgroup rw,relatime,hugetlb 0 0
 //
-// Returns a map for every target (cpuset, cpu, cpuacct) => path
-func cgroupMountPoints() (map[string]string, error) {
-	mountsFile := "/proc/mounts"
-	if !util.PathExists(mountsFile) {
-		return nil, fmt.Errorf("/proc/mounts does not exist")
+// Returns a map for every target (cpuset, cpu, cpuacct) => path. The cgroup v2
+// unified hierarchy is returned with the "unified" target. The mount points
+// are re-pointed under cgroupRoot, see hostCgroupPath.
+func cgroupMountPoints(cgroupRoot string) (map[string]string, error) {
+	if !cgroupsAvailable() {
+		return nil, errNoCgroupMounts
 	}
-	f, err := os.Open(mountsFile)
+	f, err := os.Open(mountsFile())
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
-	return parseCgroupMountPoints(f), nil
+	return parseCgroupMountPoints(f, cgroupRoot), nil
+}
+
+// mountsFile returns the path of the file listing the mounted filesystems,
+// used to find the cgroup hierarchies. It honors HOST_PROC so the host mounts
+// are used when the host proc is mounted in the agent container.
+func mountsFile() string {
+	return util.HostProc("mounts")
+}
+
+// cgroupsAvailable returns true if the cgroup mount points can be read. Minimal
+// environments may have /proc without the mounts file.
+func cgroupsAvailable() bool {
+	return util.PathExists(mountsFile())
 }
 
-func parseCgroupMountPoints(r io.Reader) map[string]string {
+// defaultCgroupRoot is the standard mount point of the cgroup hierarchies.
+const defaultCgroupRoot = "/sys/fs/cgroup"
+
+// hostCgroupPath re-points a cgroup mount point, as listed in the mounts file,
+// to where the hierarchy can be read from: under cgroupRoot if set and the
+// hierarchy is under the standard root, under HOST_SYS otherwise. cgroupRoot
+// is the Config.CgroupRoot of the runtime.
+func hostCgroupPath(cgroupRoot, mountPath string) string {
+	if cgroupRoot != "" && (mountPath == defaultCgroupRoot || strings.HasPrefix(mountPath, defaultCgroupRoot+"/")) {
+		return filepath.Join(cgroupRoot, strings.TrimPrefix(mountPath, defaultCgroupRoot))
+	}
+	// Re-point /sys cgroups to /proc/sys
+	if strings.HasPrefix(mountPath, "/sys") {
+		return util.HostSys(strings.TrimPrefix(mountPath, "/sys"))
+	}
+	return mountPath
+}
+
+func parseCgroupMountPoints(r io.Reader, cgroupRoot string) map[string]string {
 	mountPoints := make(map[string]string)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		mount := scanner.Text()
 		if strings.HasPrefix(mount, "cgroup ") {
 			tokens := strings.Split(mount, " ")
-			cgroupPath := tokens[1]
-
-			// Re-point /sys cgroups to /proc/sys
-			if strings.HasPrefix(cgroupPath, "/sys") {
-				cgroupPath = util.HostSys(strings.TrimPrefix(cgroupPath, "/sys"))
-			}
+			cgroupPath := hostCgroupPath(cgroupRoot, tokens[1])
 
 			// Target can be comma-separate values like cpu,cpuacct
 			tsp := strings.Split(path.Base(cgroupPath), ",")
 			for _, target := range tsp {
 				mountPoints[target] = cgroupPath
 			}
+		} else if strings.HasPrefix(mount, "cgroup2 ") {
+			// The unified hierarchy holds all controllers, e.g.
+			//	 cgroup2 /sys/fs/cgroup cgroup2 rw,nosuid,nodev,noexec,relatime 0 0
+			cgroupPath := hostCgroupPath(cgroupRoot, strings.Split(mount, " ")[1])
+			mountPoints[unifiedTarget] = cgroupPath
 		}
 	}
 	return mountPoints
 }
 
+// pidContainersCacheKey is the cache key of the container ID of the pids of
+// the last containers collection.
+const pidContainersCacheKey = "dockerutil.pid_containers"
+
+// cachePidContainers caches the container ID of each pid, empty for the pids
+// not running in a container, for ContainerIDForPID. ttl is the one of the
+// containers so the IDs aren't reused after them.
+func cachePidContainers(pids []int32, cgs map[string]*ContainerCgroup, ttl time.Duration) {
+	byPid := make(map[int32]string, len(pids))
+	for _, pid := range pids {
+		byPid[pid] = ""
+	}
+	for id, cg := range cgs {
+		for _, pid := range cg.Pids {
+			byPid[pid] = id
+		}
+	}
+	cache.SetWithTTL(pidContainersCacheKey, byPid, ttl)
+}
+
+// ContainerIDForPID returns the ID of the container running pid, false if it
+// doesn't run in a container. It only reads the cgroups of the processes,
+// without any call to the container runtime. The pids of the last containers
+// collection are looked up in its cache, the others in their cgroup file.
+// The ID is always the full one, the only one in the cgroup paths, so it can
+// be looked up directly in the maps keyed by Container.ID while short IDs
+// need ResolveContainerID.
+func ContainerIDForPID(pid int32) (string, bool) {
+	if cached, ok := cache.Get(pidContainersCacheKey); ok {
+		if byPid, ok := cached.(map[int32]string); ok {
+			if id, ok := byPid[pid]; ok {
+				return id, id != ""
+			}
+		}
+	}
+	// The pid started since the last collection.
+	id, _, err := readCgroupPaths(util.HostProc(strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		log.Debugf("could not get the cgroups of pid %d: %s", pid, err)
+		return "", false
+	}
+	return id, id != ""
+}
+
+const (
+	// cgroupsCacheKey is the cache key of the last CgroupsForPids result.
+	cgroupsCacheKey = "dockerutil.cgroups_for_pids"
+	// cgroupsCacheTTL is how long a CgroupsForPids result is reused. It only
+	// dedupes calls from checks running in the same collection cycle so it
+	// must stay well below the check intervals.
+	cgroupsCacheTTL = 500 * time.Millisecond
+)
+
+// cgroupsCacheEntry is a CgroupsForPids result for the cgroup root and the
+// pids with the given hash.
+type cgroupsCacheEntry struct {
+	cgroupRoot string
+	pidsHash   uint64
+	cgroups    map[string]*ContainerCgroup
+}
+
+// hashPids returns a hash of a pid list used to check that a cached
+// CgroupsForPids result was computed for the same pids.
+func hashPids(pids []int32) uint64 {
+	h := fnv.New64a()
+	b := make([]byte, 4)
+	for _, pid := range pids {
+		binary.LittleEndian.PutUint32(b, uint32(pid))
+		h.Write(b)
+	}
+	return h.Sum64()
+}
+
 // CgroupsForPids returns ContainerCgroup for every container that's in a Cgroup.
-// We return as a map[containerID]Cgroup for easy look-up.
-func CgroupsForPids(pids []int32) (map[string]*ContainerCgroup, error) {
-	mountPoints, err := cgroupMountPoints()
+// We return as a map[containerID]Cgroup for easy look-up. The hierarchies
+// are read under cgroupRoot, see Config.CgroupRoot. The result is briefly
+// cached so checks running at the same time share a single parse.
+func CgroupsForPids(cgroupRoot string, pids []int32) (map[string]*ContainerCgroup, error) {
+	pidsHash := hashPids(pids)
+	if cached, ok := cache.Get(cgroupsCacheKey); ok {
+		if entry, ok := cached.(*cgroupsCacheEntry); ok && entry.cgroupRoot == cgroupRoot && entry.pidsHash == pidsHash {
+			return entry.cgroups, nil
+		}
+	}
+
+	cgs, err := cgroupsForPids(cgroupRoot, pids)
 	if err != nil {
 		return nil, err
 	}
+	// A single entry is kept since expired entries are only removed on lookup.
+	cache.SetWithTTL(cgroupsCacheKey, &cgroupsCacheEntry{cgroupRoot, pidsHash, cgs}, cgroupsCacheTTL)
+	return cgs, nil
+}
 
+func cgroupsForPids(cgroupRoot string, pids []int32) (map[string]*ContainerCgroup, error) {
+	mountPoints, err := cgroupMountPoints(cgroupRoot)
+	if err == errNoCgroupMounts {
+		// Minimal environments may not expose the mounts, we can still get
+		// the container metadata without the cgroups.
+		log.Debugf("%s does not exist, cgroup stats are not available", mountsFile())
+		return map[string]*ContainerCgroup{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	v2 := isCgroupV2(mountPoints)
 	cgs := make(map[string]*ContainerCgroup)
 	for _, pid := range pids {
 		cgPath := util.HostProc(strconv.Itoa(int(pid)), "cgroup")
@@ -422,7 +976,8 @@ func CgroupsForPids(pids []int32) (map[string]*ContainerCgroup, error) {
 				ContainerID: containerID,
 				Pids:        []int32{pid},
 				Paths:       paths,
-				Mounts:      mountPoints}
+				Mounts:      mountPoints,
+				v2:          v2}
 		}
 	}
 	return cgs, nil
@@ -473,6 +1028,11 @@ func parseCgroupPaths(r io.Reader) (string, map[string]string, error) {
 		if len(sp) < 3 {
 			continue
 		}
+		// The cgroup v2 hierarchy has no controllers: 0::/system.slice/docker-<id>.scope
+		if sp[1] == "" {
+			paths[unifiedTarget] = sp[2]
+			continue
+		}
 		// Target can be comma-separate values like cpu,cpuacct
 		tsp := strings.Split(sp[1], ",")
 		for _, target := range tsp {
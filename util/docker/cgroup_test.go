@@ -1,9 +1,15 @@
 package docker
 
 import (
-	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-process-agent/util/cache"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestParseCgroupMountPoints(t *testing.T) {
@@ -38,12 +44,37 @@ func TestParseCgroupMountPoints(t *testing.T) {
 			},
 			expected: map[string]string{},
 		},
+		{
+			contents: []string{
+				"sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0",
+				"cgroup2 /sys/fs/cgroup cgroup2 rw,nosuid,nodev,noexec,relatime,seclabel 0 0",
+			},
+			expected: map[string]string{
+				"unified": "/sys/fs/cgroup",
+			},
+		},
 	} {
 		contents := strings.NewReader(strings.Join(tc.contents, "\n"))
-		assert.Equal(t, tc.expected, parseCgroupMountPoints(contents))
+		assert.Equal(t, tc.expected, parseCgroupMountPoints(contents, ""))
 	}
 }
 
+func TestParseCgroupMountPointsCgroupRoot(t *testing.T) {
+	contents := strings.NewReader(strings.Join([]string{
+		"cgroup /sys/fs/cgroup/memory cgroup rw,relatime,memory 0 0",
+		"cgroup /sys/fs/cgroup/cpu,cpuacct cgroup rw,relatime,cpu,cpuacct 0 0",
+		"cgroup /cgroup/blkio cgroup rw,relatime,blkio 0 0",
+		"cgroup2 /sys/fs/cgroup cgroup2 rw,nosuid,nodev,noexec,relatime 0 0",
+	}, "\n"))
+	assert.Equal(t, map[string]string{
+		"memory":  "/host/sys/fs/cgroup/memory",
+		"cpu":     "/host/sys/fs/cgroup/cpu,cpuacct",
+		"cpuacct": "/host/sys/fs/cgroup/cpu,cpuacct",
+		"blkio":   "/cgroup/blkio",
+		"unified": "/host/sys/fs/cgroup",
+	}, parseCgroupMountPoints(contents, "/host/sys/fs/cgroup"))
+}
+
 func TestParseCgroupPaths(t *testing.T) {
 	for _, tc := range []struct {
 		contents          []string
@@ -90,6 +121,15 @@ func TestParseCgroupPaths(t *testing.T) {
 				"cpu": "/docker/a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419",
 			},
 		},
+		{
+			contents: []string{
+				"0::/system.slice/docker-a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419.scope",
+			},
+			expectedContainer: "a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419",
+			expectedPaths: map[string]string{
+				"unified": "/system.slice/docker-a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419.scope",
+			},
+		},
 	} {
 		contents := strings.NewReader(strings.Join(tc.contents, "\n"))
 		c, p, err := parseCgroupPaths(contents)
@@ -98,3 +138,488 @@ func TestParseCgroupPaths(t *testing.T) {
 		assert.Equal(t, p, tc.expectedPaths)
 	}
 }
+
+func TestMemValue(t *testing.T) {
+	assert := assert.New(t)
+
+	tmp, err := ioutil.TempDir("", "test-cgroup-mem-value")
+	assert.NoError(err)
+	defer os.RemoveAll(tmp)
+	cg := ContainerCgroup{
+		ContainerID: "test",
+		Mounts:      map[string]string{"memory": tmp},
+		Paths:       map[string]string{"memory": "/"},
+	}
+
+	for i, tc := range []struct {
+		contents string
+		expected uint64
+	}{
+		{"536870912\n", 536870912},
+		// cgroup v2 unset value
+		{"max\n", 0},
+		// cgroup v1 unset value
+		{"9223372036854771712\n", 0},
+		// cgroup v1 unset value on 32-bit kernels
+		{"8796093018112\n", 0},
+		{"0\n", 0},
+	} {
+		err := ioutil.WriteFile(filepath.Join(tmp, "memory.low"), []byte(tc.contents), 0644)
+		assert.NoError(err)
+		v, err := cg.MemLow()
+		assert.NoError(err)
+		assert.Equal(tc.expected, v, "test %d failed", i)
+	}
+
+	// Missing files default to 0.
+	v, err := cg.MemSoftLimit()
+	assert.NoError(err)
+	assert.Equal(uint64(0), v)
+}
+
+func TestIsUnlimitedMem(t *testing.T) {
+	for _, tc := range []struct {
+		v         uint64
+		unlimited bool
+	}{
+		// 64-bit kernels with 4K, 16K and 64K pages.
+		{9223372036854771712, true},
+		{9223372036854759424, true},
+		{9223372036854710272, true},
+		// 32-bit kernels with 4K and 64K pages.
+		{8796093018112, true},
+		{140737488289792, true},
+		// Older kernels.
+		{18446744073709551615, true},
+		{0, false},
+		{536870912, false},
+		// 8TiB is a real limit, one page off the 32-bit sentinel.
+		{8796093022208, false},
+	} {
+		assert.Equal(t, tc.unlimited, isUnlimitedMem(tc.v), "value %d", tc.v)
+	}
+}
+
+func TestCgroupsForPidsCache(t *testing.T) {
+	assert := assert.New(t)
+
+	tmp, err := ioutil.TempDir("", "test-cgroups-for-pids")
+	assert.NoError(err)
+	defer os.RemoveAll(tmp)
+	os.Setenv("HOST_PROC", tmp)
+	defer os.Setenv("HOST_PROC", "/proc")
+	cache.Delete(cgroupsCacheKey)
+	defer cache.Delete(cgroupsCacheKey)
+	assert.NoError(ioutil.WriteFile(filepath.Join(tmp, "mounts"), []byte("cgroup /sys/fs/cgroup/memory cgroup rw,memory 0 0\n"), 0644))
+
+	cid := "a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419"
+	for _, pid := range []string{"10", "11"} {
+		assert.NoError(os.MkdirAll(filepath.Join(tmp, pid), 0755))
+		assert.NoError(ioutil.WriteFile(filepath.Join(tmp, pid, "cgroup"), []byte("6:memory:/docker/"+cid+"\n"), 0644))
+	}
+
+	cgs, err := CgroupsForPids("", []int32{10})
+	assert.NoError(err)
+	assert.Equal([]int32{10}, cgs[cid].Pids)
+
+	// The same pids reuse the parse, even if /proc changed in the meantime.
+	assert.NoError(os.RemoveAll(filepath.Join(tmp, "10")))
+	cgs, err = CgroupsForPids("", []int32{10})
+	assert.NoError(err)
+	assert.Equal([]int32{10}, cgs[cid].Pids)
+
+	// Other cgroup roots and pids are parsed again.
+	cgs, err = CgroupsForPids("/host/sys/fs/cgroup", []int32{10})
+	assert.NoError(err)
+	assert.Empty(cgs)
+	cgs, err = CgroupsForPids("", []int32{10, 11})
+	assert.NoError(err)
+	assert.Equal([]int32{11}, cgs[cid].Pids)
+}
+
+func TestContainerIDForPID(t *testing.T) {
+	assert := assert.New(t)
+
+	tmp, err := ioutil.TempDir("", "test-container-id-for-pid")
+	assert.NoError(err)
+	defer os.RemoveAll(tmp)
+	os.Setenv("HOST_PROC", tmp)
+	defer os.Setenv("HOST_PROC", "/proc")
+	cache.Delete(cgroupsCacheKey)
+	defer cache.Delete(cgroupsCacheKey)
+	cache.Delete(pidContainersCacheKey)
+	assert.NoError(ioutil.WriteFile(filepath.Join(tmp, "mounts"), []byte("cgroup /sys/fs/cgroup/memory cgroup rw,memory 0 0\n"), 0644))
+
+	cid := "a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419"
+	for pid, cgroup := range map[string]string{
+		"10": "6:memory:/docker/" + cid + "\n",
+		"11": "6:memory:/user.slice\n",
+	} {
+		assert.NoError(os.MkdirAll(filepath.Join(tmp, pid), 0755))
+		assert.NoError(ioutil.WriteFile(filepath.Join(tmp, pid, "cgroup"), []byte(cgroup), 0644))
+	}
+
+	id, ok := ContainerIDForPID(10)
+	assert.True(ok)
+	assert.Equal(cid, id)
+	_, ok = ContainerIDForPID(11)
+	assert.False(ok)
+	_, ok = ContainerIDForPID(12)
+	assert.False(ok)
+
+	// The pids of the last collection are looked up in its cache, without
+	// reading their cgroup file again.
+	cgs, err := CgroupsForPids("", []int32{10, 11})
+	assert.NoError(err)
+	cachePidContainers([]int32{10, 11}, cgs, time.Minute)
+	defer cache.Delete(pidContainersCacheKey)
+	assert.NoError(os.RemoveAll(filepath.Join(tmp, "10")))
+	assert.NoError(ioutil.WriteFile(filepath.Join(tmp, "11", "cgroup"), []byte("6:memory:/docker/"+cid+"\n"), 0644))
+	id, ok = ContainerIDForPID(10)
+	assert.True(ok)
+	assert.Equal(cid, id)
+	_, ok = ContainerIDForPID(11)
+	assert.False(ok)
+	// The others still are.
+	assert.NoError(os.MkdirAll(filepath.Join(tmp, "12"), 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(tmp, "12", "cgroup"), []byte("6:memory:/docker/"+cid+"\n"), 0644))
+	id, ok = ContainerIDForPID(12)
+	assert.True(ok)
+	assert.Equal(cid, id)
+}
+
+func TestCgroupPids(t *testing.T) {
+	assert := assert.New(t)
+
+	mount, err := ioutil.TempDir("", "test-cgroup-pids")
+	assert.NoError(err)
+	defer os.RemoveAll(mount)
+
+	dir := filepath.Join(mount, "docker", "1")
+	assert.NoError(os.MkdirAll(dir, 0755))
+	write := func(name, contents string) {
+		assert.NoError(ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+	}
+
+	// Without the pids controller.
+	cg := ContainerCgroup{
+		ContainerID: "1",
+		Mounts:      map[string]string{"memory": mount},
+		Paths:       map[string]string{"memory": "/docker/1"},
+	}
+	_, ok, err := cg.PidsCurrent()
+	assert.NoError(err)
+	assert.False(ok)
+	limit, err := cg.PidsLimit()
+	assert.NoError(err)
+	assert.Equal(uint64(0), limit)
+
+	cg.Mounts["pids"] = mount
+	cg.Paths["pids"] = "/docker/1"
+	write("pids.current", "12\n")
+	write("pids.max", "512\n")
+	current, ok, err := cg.PidsCurrent()
+	assert.NoError(err)
+	assert.True(ok)
+	assert.Equal(uint64(12), current)
+	limit, err = cg.PidsLimit()
+	assert.NoError(err)
+	assert.Equal(uint64(512), limit)
+
+	write("pids.max", "max\n")
+	limit, err = cg.PidsLimit()
+	assert.NoError(err)
+	assert.Equal(uint64(0), limit)
+}
+
+func TestCgroupV1Mem(t *testing.T) {
+	assert := assert.New(t)
+
+	mount, err := ioutil.TempDir("", "test-cgroup-v1-mem")
+	assert.NoError(err)
+	defer os.RemoveAll(mount)
+
+	cg := ContainerCgroup{
+		ContainerID: "1",
+		Mounts:      map[string]string{"memory": mount},
+		Paths:       map[string]string{"memory": "/docker/1"},
+	}
+	dir := filepath.Join(mount, "docker", "1")
+	assert.NoError(os.MkdirAll(dir, 0755))
+	write := func(name, contents string) {
+		assert.NoError(ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+	}
+
+	write("memory.usage_in_bytes", "157286400\n")
+	write("memory.stat", detab(`
+		cache 52428800
+		rss 104857600
+		rss_huge 2097152
+		mapped_file 1048576
+		inactive_file 10485760
+		hierarchical_memory_limit 9223372036854771712
+		total_cache 52428800
+		total_rss 104857600
+		total_inactive_file 20971520
+		total_swap 4096
+	`))
+	mem, err := cg.Mem()
+	assert.NoError(err)
+	assert.Equal(&CgroupMemStat{
+		ContainerID:             "1",
+		Cache:                   52428800,
+		RSS:                     104857600,
+		RSSHuge:                 2097152,
+		MappedFile:              1048576,
+		InactiveFile:            10485760,
+		HierarchicalMemoryLimit: 9223372036854771712,
+		TotalCache:              52428800,
+		TotalRSS:                104857600,
+		TotalInactiveFile:       20971520,
+		MemUsageInBytes:         157286400,
+		Swap:                    4096,
+		WorkingSet:              136314880,
+	}, mem)
+
+	write("memory.oom_control", "oom_kill_disable 0\nunder_oom 0\noom_kill 2\n")
+	mem, err = cg.Mem()
+	assert.NoError(err)
+	assert.Equal(uint64(2), mem.OOMKills)
+
+	// Older kernels don't report the OOM kills.
+	write("memory.oom_control", "oom_kill_disable 0\nunder_oom 0\n")
+	mem, err = cg.Mem()
+	assert.NoError(err)
+	assert.Equal(uint64(0), mem.OOMKills)
+
+	// Without swap accounting there is no total_swap and the inactive file
+	// cache can exceed the usage.
+	write("memory.usage_in_bytes", "1024\n")
+	write("memory.stat", "total_inactive_file 4096\n")
+	mem, err = cg.Mem()
+	assert.NoError(err)
+	assert.Equal(uint64(0), mem.Swap)
+	assert.Equal(uint64(0), mem.WorkingSet)
+}
+
+func TestCgroupMemStatSetV1(t *testing.T) {
+	// The keys of memory.stat as written by the kernel, each with a distinct
+	// value so a key mapped to the wrong field fails.
+	keys := []string{
+		"cache", "rss", "rss_huge", "mapped_file", "pgpgin", "pgpgout", "pgfault",
+		"pgmajfault", "inactive_anon", "active_anon", "inactive_file", "active_file",
+		"unevictable", "hierarchical_memory_limit", "total_cache", "total_rss",
+		"total_rss_huge", "total_mapped_file", "total_pgpgin", "total_pgpgout",
+		"total_pgfault", "total_pgmajfault", "total_inactive_anon", "total_active_anon",
+		"total_inactive_file", "total_active_file", "total_unevictable", "total_swap",
+	}
+	mem := &CgroupMemStat{}
+	for i, key := range keys {
+		mem.setV1(key, uint64(i+1))
+	}
+	// The camelCase names of the docker stats API aren't memory.stat keys.
+	mem.setV1("totalInactiveFile", 1000)
+
+	assert.Equal(t, &CgroupMemStat{
+		Cache:                   1,
+		RSS:                     2,
+		RSSHuge:                 3,
+		MappedFile:              4,
+		Pgpgin:                  5,
+		Pgpgout:                 6,
+		Pgfault:                 7,
+		Pgmajfault:              8,
+		InactiveAnon:            9,
+		ActiveAnon:              10,
+		InactiveFile:            11,
+		ActiveFile:              12,
+		Unevictable:             13,
+		HierarchicalMemoryLimit: 14,
+		TotalCache:              15,
+		TotalRSS:                16,
+		TotalRSSHuge:            17,
+		TotalMappedFile:         18,
+		TotalPgpgIn:             19,
+		TotalPgpgOut:            20,
+		TotalPgFault:            21,
+		TotalPgMajFault:         22,
+		TotalInactiveAnon:       23,
+		TotalActiveAnon:         24,
+		TotalInactiveFile:       25,
+		TotalActiveFile:         26,
+		TotalUnevictable:        27,
+		Swap:                    28,
+	}, mem)
+}
+
+func TestCgroupV1CPU(t *testing.T) {
+	assert := assert.New(t)
+
+	mount, err := ioutil.TempDir("", "test-cgroup-v1-cpu")
+	assert.NoError(err)
+	defer os.RemoveAll(mount)
+
+	cg := ContainerCgroup{
+		ContainerID: "1",
+		Mounts:      map[string]string{"cpu": mount, "cpuacct": mount},
+		Paths:       map[string]string{"cpu": "/docker/1", "cpuacct": "/docker/1"},
+	}
+	dir := filepath.Join(mount, "docker", "1")
+	assert.NoError(os.MkdirAll(dir, 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "cpuacct.stat"), []byte("user 159\nsystem 88\n"), 0644))
+
+	// The throttling stats are optional.
+	cpu, err := cg.CPU()
+	assert.NoError(err)
+	assert.Equal(&CgroupTimesStat{ContainerID: "1", User: 159, System: 88}, cpu)
+
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "cpu.stat"), []byte(detab(`
+		nr_periods 100
+		nr_throttled 12
+		throttled_time 345678000
+	`)), 0644))
+	cpu, err = cg.CPU()
+	assert.NoError(err)
+	assert.Equal(&CgroupTimesStat{ContainerID: "1", User: 159, System: 88, NrThrottled: 12, ThrottledTime: 345678000}, cpu)
+}
+
+func TestCgroupV1IO(t *testing.T) {
+	assert := assert.New(t)
+
+	mount, err := ioutil.TempDir("", "test-cgroup-v1-io")
+	assert.NoError(err)
+	defer os.RemoveAll(mount)
+
+	cg := ContainerCgroup{
+		ContainerID: "1",
+		Mounts:      map[string]string{"blkio": mount},
+		Paths:       map[string]string{"blkio": "/docker/1"},
+	}
+	dir := filepath.Join(mount, "docker", "1")
+	assert.NoError(os.MkdirAll(dir, 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "blkio.throttle.io_service_bytes"), []byte(detab(`
+		8:0 Read 1000
+		8:0 Write 2000
+		8:0 Sync 3000
+		8:0 Async 0
+		8:0 Total 3000
+		252:0 Read 10
+		252:0 Write 20
+		252:0 Total 30
+
+		Total 3030
+	`)), 0644))
+
+	cache.Set(partitionsCacheKey, map[string]string{"8:0": "sda"})
+	defer cache.Delete(partitionsCacheKey)
+	io, err := cg.IO()
+	assert.NoError(err)
+	assert.Equal(&CgroupIOStat{
+		ContainerID: "1",
+		ReadBytes:   1010,
+		WriteBytes:  2020,
+		Devices: map[string]IODeviceStat{
+			"sda":   {ReadBytes: 1000, WriteBytes: 2000},
+			"252:0": {ReadBytes: 10, WriteBytes: 20},
+		},
+	}, io)
+}
+
+func TestParsePartitions(t *testing.T) {
+	lines := strings.Split(`major minor  #blocks  name
+
+   8        0  488386584 sda
+   8        1     524288 sda1
+ 252        0   41943040 dm-0`, "\n")
+	assert.Equal(t, map[string]string{
+		"8:0":   "sda",
+		"8:1":   "sda1",
+		"252:0": "dm-0",
+	}, parsePartitions(lines))
+}
+
+func TestSetCgroupLimitsFailures(t *testing.T) {
+	assert := assert.New(t)
+	defer pruneLimitFailures(nil)
+
+	mount, err := ioutil.TempDir("", "test-cgroup-limit-failures")
+	assert.NoError(err)
+	defer os.RemoveAll(mount)
+	dir := filepath.Join(mount, "docker", "1")
+	assert.NoError(os.MkdirAll(dir, 0755))
+	limitFile := filepath.Join(dir, "memory.limit_in_bytes")
+	assert.NoError(ioutil.WriteFile(limitFile, []byte("garbage\n"), 0644))
+
+	cg := &ContainerCgroup{
+		ContainerID: "1",
+		Mounts:      map[string]string{"memory": mount},
+		Paths:       map[string]string{"memory": "/docker/1"},
+	}
+	stats := &fakeStatsClient{counts: make(map[string]int64)}
+	for i := 0; i < limitFailuresWarnThreshold; i++ {
+		setCgroupLimits(&Container{ID: "1"}, cg, stats)
+	}
+	assert.Equal(int64(limitFailuresWarnThreshold), stats.counts["datadog.process.docker.cgroup_limit_errors"])
+	assert.Equal(limitFailuresWarnThreshold, limitFailures.byID["1"])
+
+	// A successful read ends the streak.
+	assert.NoError(ioutil.WriteFile(limitFile, []byte("1073741824\n"), 0644))
+	ctr := &Container{ID: "1"}
+	setCgroupLimits(ctr, cg, stats)
+	assert.Equal(uint64(1073741824), ctr.MemLimit)
+	assert.NotContains(limitFailures.byID, "1")
+
+	// The streaks of the containers which are gone are forgotten.
+	assert.NoError(ioutil.WriteFile(limitFile, []byte("garbage\n"), 0644))
+	setCgroupLimits(&Container{ID: "1"}, cg, nil)
+	assert.Equal(1, limitFailures.byID["1"])
+	pruneLimitFailures([]*Container{{ID: "2"}})
+	assert.NotContains(limitFailures.byID, "1")
+}
+
+func TestParseCPUSet(t *testing.T) {
+	for _, tc := range []struct {
+		cpuset string
+		count  int
+		err    bool
+	}{
+		{"0-3,8,10-11", 7, false},
+		{"0", 1, false},
+		{"0-63", 64, false},
+		{"2,4,6\n", 3, false},
+		{"", 0, false},
+		{"3-1", 0, true},
+		{"a-b", 0, true},
+		{"0-", 0, true},
+	} {
+		count, err := parseCPUSet(tc.cpuset)
+		assert.Equal(t, tc.count, count, tc.cpuset)
+		assert.Equal(t, tc.err, err != nil, tc.cpuset)
+	}
+}
+
+func TestCgroupCpusetCount(t *testing.T) {
+	assert := assert.New(t)
+
+	mount, err := ioutil.TempDir("", "test-cgroup-cpuset")
+	assert.NoError(err)
+	defer os.RemoveAll(mount)
+	dir := filepath.Join(mount, "docker", "1")
+	assert.NoError(os.MkdirAll(dir, 0755))
+
+	cg := ContainerCgroup{
+		ContainerID: "1",
+		Mounts:      map[string]string{"memory": mount},
+		Paths:       map[string]string{"memory": "/docker/1"},
+	}
+	count, err := cg.CpusetCount()
+	assert.NoError(err)
+	assert.Equal(0, count)
+
+	cg.Mounts["cpuset"] = mount
+	cg.Paths["cpuset"] = "/docker/1"
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "cpuset.cpus"), []byte("0-1\n"), 0644))
+	count, err = cg.CpusetCount()
+	assert.NoError(err)
+	assert.Equal(2, count)
+}
@@ -0,0 +1,287 @@
+package docker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/cihub/seelog"
+
+	"github.com/DataDog/datadog-process-agent/util"
+)
+
+// unifiedTarget is the target we use for the cgroup v2 unified hierarchy in
+// the mount points and paths maps.
+const unifiedTarget = "unified"
+
+// usecPerTick converts the microseconds from cgroup v2 cpu.stat into the
+// USER_HZ ticks reported by cgroup v1 cpuacct.stat.
+const usecPerTick = 10000
+
+// isCgroupV2 returns true if the host only uses the cgroup v2 unified hierarchy.
+// Hybrid hosts also mount cgroup2 but keep the v1 controllers, which we prefer.
+func isCgroupV2(mountPoints map[string]string) bool {
+	unified, ok := mountPoints[unifiedTarget]
+	if !ok {
+		return false
+	}
+	if _, ok := mountPoints["memory"]; ok {
+		return false
+	}
+	return util.PathExists(filepath.Join(unified, "cgroup.controllers"))
+}
+
+// memV2 returns the memory statistics for a cgroup v2 from memory.stat and
+// memory.current, mapping the unified fields to their v1 equivalent.
+func (c ContainerCgroup) memV2() (*CgroupMemStat, error) {
+	ret := &CgroupMemStat{ContainerID: c.ContainerID}
+	statfile := c.cgroupFilePath("memory", "memory.stat")
+
+	f, err := os.Open(statfile)
+	if os.IsNotExist(err) {
+		log.Debugf("missing cgroup file: %s", statfile)
+		return ret, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		ret.setV2(fields[0], v)
+	}
+	if err := scanner.Err(); err != nil {
+		return ret, fmt.Errorf("error reading %s: %s", statfile, err)
+	}
+
+	ret.MemUsageInBytes, err = c.memValue("memory.current")
+	if err != nil {
+		return ret, err
+	}
+	ret.WorkingSet = workingSet(ret.MemUsageInBytes, ret.InactiveFile)
+	// The file is missing when swap is disabled which leaves Swap at 0.
+	ret.Swap, err = c.memValue("memory.swap.current")
+	if err != nil {
+		return ret, err
+	}
+	ret.OOMKills, err = c.memKeyedValue("memory.events", "oom_kill")
+	if err != nil {
+		return ret, err
+	}
+	ret.MemoryPressure = c.pressure("memory", "memory.pressure")
+	return ret, nil
+}
+
+// setV2 sets the memory stat from a memory.stat key of cgroup v2, unknown
+// keys are ignored.
+func (m *CgroupMemStat) setV2(key string, v uint64) {
+	switch key {
+	case "anon":
+		m.RSS = v
+	case "file":
+		m.Cache = v
+	case "anon_thp":
+		m.RSSHuge = v
+	case "file_mapped":
+		m.MappedFile = v
+	case "pgfault":
+		m.Pgfault = v
+	case "pgmajfault":
+		m.Pgmajfault = v
+	case "inactive_anon":
+		m.InactiveAnon = v
+	case "active_anon":
+		m.ActiveAnon = v
+	case "inactive_file":
+		m.InactiveFile = v
+	case "active_file":
+		m.ActiveFile = v
+	case "unevictable":
+		m.Unevictable = v
+	}
+}
+
+// cpuV2 returns the CPU times for a cgroup v2 from cpu.stat. The format is:
+//
+// usage_usec 2475013
+// user_usec 1594536
+// system_usec 880477
+//
+func (c ContainerCgroup) cpuV2() (*CgroupTimesStat, error) {
+	ret := &CgroupTimesStat{ContainerID: c.ContainerID}
+	statfile := c.cgroupFilePath("cpu", "cpu.stat")
+	f, err := os.Open(statfile)
+	if os.IsNotExist(err) {
+		log.Debugf("missing cgroup file: %s", statfile)
+		return ret, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "user_usec":
+			ret.User = v / usecPerTick
+		case "system_usec":
+			ret.System = v / usecPerTick
+		case "nr_throttled":
+			ret.NrThrottled = v
+		case "throttled_usec":
+			ret.ThrottledTime = v * 1000
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ret, fmt.Errorf("error reading %s: %s", statfile, err)
+	}
+	ret.CPUPressure = c.pressure("cpu", "cpu.pressure")
+	return ret, nil
+}
+
+// cpuLimitV2 returns the CPU limit for a cgroup v2 from cpu.max which holds
+// both the quota and the period, e.g. "50000 100000" or "max 100000".
+func (c ContainerCgroup) cpuLimitV2() (float64, error) {
+	statfile := c.cgroupFilePath("cpu", "cpu.max")
+	lines, err := util.ReadLines(statfile)
+	if os.IsNotExist(err) {
+		log.Debugf("missing cgroup file: %s", statfile)
+		return 100, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if len(lines) != 1 {
+		return 0, fmt.Errorf("wrong format file: %s", statfile)
+	}
+	fields := strings.Fields(lines[0])
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("wrong format file: %s", statfile)
+	}
+	// default cpu limit is 100%
+	if fields[0] == "max" {
+		return 100, nil
+	}
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, err
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	limit := 100.0
+	if (period > 0) && (quota > 0) {
+		limit = (quota / period) * 100.0
+	}
+	return limit, nil
+}
+
+// ioV2 returns the disk read and write bytes for a cgroup v2 from io.stat,
+// by device and summed over all devices. The format is:
+//
+// 8:0 rbytes=49225728 wbytes=9850880 rios=1200 wios=210 dbytes=0 dios=0
+// 252:0 rbytes=49094656 wbytes=9850880 rios=1195 wios=210 dbytes=0 dios=0
+//
+func (c ContainerCgroup) ioV2() (*CgroupIOStat, error) {
+	ret := &CgroupIOStat{ContainerID: c.ContainerID, Devices: make(map[string]IODeviceStat)}
+	statfile := c.cgroupFilePath("io", "io.stat")
+	f, err := os.Open(statfile)
+	if os.IsNotExist(err) {
+		log.Debugf("missing cgroup file: %s", statfile)
+		return ret, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	devices := blockDevices()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		name := deviceName(devices, fields[0])
+		dev := ret.Devices[name]
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			v, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			switch kv[0] {
+			case "rbytes":
+				dev.ReadBytes += v
+				ret.ReadBytes += v
+			case "wbytes":
+				dev.WriteBytes += v
+				ret.WriteBytes += v
+			}
+		}
+		ret.Devices[name] = dev
+	}
+	if err := scanner.Err(); err != nil {
+		return ret, fmt.Errorf("error reading %s: %s", statfile, err)
+	}
+	ret.IOPressure = c.pressure("io", "io.pressure")
+	return ret, nil
+}
+
+// pressure returns the "some" avg10 value of a PSI file of cgroup v2, i.e.
+// the percentage of time some tasks were stalled over the last 10 seconds.
+// PSI is best-effort: it is 0 when the file can't be read or parsed, e.g.
+// PSI is disabled or unsupported, without failing the other stats. The
+// format is:
+//
+// some avg10=1.53 avg60=0.87 avg300=0.22 total=1349210
+// full avg10=0.93 avg60=0.51 avg300=0.13 total=904271
+//
+func (c ContainerCgroup) pressure(target, file string) float64 {
+	statfile := c.cgroupFilePath(target, file)
+	lines, err := util.ReadLines(statfile)
+	if os.IsNotExist(err) {
+		log.Debugf("missing cgroup file: %s", statfile)
+		return 0
+	} else if err != nil {
+		log.Debugf("unable to read cgroup pressure %s: %s", statfile, err)
+		return 0
+	}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "some" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "avg10=") {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimPrefix(field, "avg10="), 64)
+			if err != nil {
+				log.Debugf("wrong format cgroup pressure file: %s", statfile)
+				return 0
+			}
+			return v
+		}
+	}
+	return 0
+}
@@ -0,0 +1,137 @@
+package docker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/datadog-process-agent/util/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCgroupV2Stats(t *testing.T) {
+	assert := assert.New(t)
+
+	mount, err := ioutil.TempDir("", "test-cgroup-v2")
+	assert.NoError(err)
+	defer os.RemoveAll(mount)
+
+	mountPoints := map[string]string{unifiedTarget: mount}
+	assert.False(isCgroupV2(mountPoints))
+	files := map[string]string{
+		"cgroup.controllers": "cpuset cpu io memory pids\n",
+		"docker-1.scope/memory.stat": detab(`
+			anon 104857600
+			file 52428800
+			anon_thp 2097152
+			file_mapped 1048576
+			pgfault 1000
+			pgmajfault 10
+			inactive_file 10485760
+		`),
+		"docker-1.scope/memory.current":      "157286400\n",
+		"docker-1.scope/memory.swap.current": "4096\n",
+		"docker-1.scope/memory.events":       "low 0\nhigh 0\nmax 12\noom 3\noom_kill 2\n",
+		"docker-1.scope/memory.max":          "536870912\n",
+		"docker-1.scope/cpu.stat": detab(`
+			usage_usec 2475013
+			user_usec 1594536
+			system_usec 880477
+			nr_periods 100
+			nr_throttled 12
+			throttled_usec 345678
+		`),
+		"docker-1.scope/cpu.max": "50000 100000\n",
+		"docker-1.scope/memory.pressure": detab(`
+			some avg10=1.53 avg60=0.87 avg300=0.22 total=1349210
+			full avg10=0.93 avg60=0.51 avg300=0.13 total=904271
+		`),
+		"docker-1.scope/cpu.pressure": "some avg10=12.50 avg60=8.00 avg300=2.10 total=88213\n",
+		"docker-1.scope/io.stat": detab(`
+			8:0 rbytes=1000 wbytes=2000 rios=1 wios=2 dbytes=0 dios=0
+			252:0 rbytes=10 wbytes=20 rios=1 wios=2 dbytes=0 dios=0
+		`),
+	}
+	for name, contents := range files {
+		p := filepath.Join(mount, name)
+		assert.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		assert.NoError(ioutil.WriteFile(p, []byte(contents), 0644))
+	}
+	assert.True(isCgroupV2(mountPoints))
+	// Hybrid hosts keep the v1 controllers.
+	assert.False(isCgroupV2(map[string]string{unifiedTarget: mount, "memory": "/sys/fs/cgroup/memory"}))
+
+	cache.Set(partitionsCacheKey, map[string]string{"8:0": "sda"})
+	defer cache.Delete(partitionsCacheKey)
+	cg := ContainerCgroup{
+		ContainerID: "1",
+		Mounts:      mountPoints,
+		Paths:       map[string]string{unifiedTarget: "/docker-1.scope"},
+		v2:          true,
+	}
+
+	assert.Equal(filepath.Join(mount, "docker-1.scope"), cg.Path())
+
+	mem, err := cg.Mem()
+	assert.NoError(err)
+	assert.Equal(&CgroupMemStat{
+		ContainerID:     "1",
+		RSS:             104857600,
+		Cache:           52428800,
+		RSSHuge:         2097152,
+		MappedFile:      1048576,
+		Pgfault:         1000,
+		Pgmajfault:      10,
+		InactiveFile:    10485760,
+		MemUsageInBytes: 157286400,
+		Swap:            4096,
+		WorkingSet:      146800640,
+		OOMKills:        2,
+		MemoryPressure:  1.53,
+	}, mem)
+
+	memLimit, err := cg.MemLimit()
+	assert.NoError(err)
+	assert.Equal(uint64(536870912), memLimit)
+
+	cpu, err := cg.CPU()
+	assert.NoError(err)
+	assert.Equal(&CgroupTimesStat{ContainerID: "1", User: 159, System: 88, NrThrottled: 12, ThrottledTime: 345678000, CPUPressure: 12.5}, cpu)
+
+	cpuLimit, err := cg.CPULimit()
+	assert.NoError(err)
+	assert.Equal(50.0, cpuLimit)
+
+	// PSI is missing for I/O which leaves IOPressure at 0.
+	io, err := cg.IO()
+	assert.NoError(err)
+	assert.Equal(&CgroupIOStat{
+		ContainerID: "1",
+		ReadBytes:   1010,
+		WriteBytes:  2020,
+		Devices: map[string]IODeviceStat{
+			"sda":   {ReadBytes: 1000, WriteBytes: 2000},
+			"252:0": {ReadBytes: 10, WriteBytes: 20},
+		},
+	}, io)
+
+	// No limit.
+	assert.NoError(ioutil.WriteFile(filepath.Join(mount, "docker-1.scope", "cpu.max"), []byte("max 100000\n"), 0644))
+	cpuLimit, err = cg.CPULimit()
+	assert.NoError(err)
+	assert.Equal(100.0, cpuLimit)
+
+	// Unreadable or malformed PSI files don't fail the other stats, e.g.
+	// when the kernel doesn't support PSI.
+	assert.NoError(os.Mkdir(filepath.Join(mount, "docker-1.scope", "io.pressure"), 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(mount, "docker-1.scope", "cpu.pressure"), []byte("some avg10=x\n"), 0644))
+	io, err = cg.IO()
+	assert.NoError(err)
+	assert.Equal(uint64(1010), io.ReadBytes)
+	assert.Equal(0.0, io.IOPressure)
+	cpu, err = cg.CPU()
+	assert.NoError(err)
+	assert.Equal(uint64(159), cpu.User)
+	assert.Equal(0.0, cpu.CPUPressure)
+}
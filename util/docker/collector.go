@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"errors"
+
+	log "github.com/cihub/seelog"
+)
+
+// errHostnameNotSupported is returned by the collectors whose runtime doesn't
+// know the hostname of the node.
+var errHostnameNotSupported = errors.New("hostname not available from the container runtime")
+
+// Collector collects the containers of a container runtime so the checks
+// don't depend on the runtime in use.
+type Collector interface {
+	// AllContainers returns the containers with their latest stats.
+	AllContainers() ([]*Container, error)
+	// GetHostname returns the hostname of the node as known by the runtime.
+	GetHostname() (string, error)
+	// Close releases the resources of the collector.
+	Close()
+}
+
+// globalCollector is the collector of the runtime initialized with
+// InitDockerUtil or InitContainerdUtil, nil if none is.
+var globalCollector Collector
+
+// GetCollector returns the collector of the runtime which initialized
+// successfully, nil if there is none.
+func GetCollector() Collector {
+	return globalCollector
+}
+
+// CollectContainers returns the containers of the collector. Collection
+// errors are logged, once per streak of the same error, rather than returned
+// so the checks keep reporting. It returns no container without a collector.
+func CollectContainers(c Collector) ([]*Container, error) {
+	if c == nil {
+		return nil, nil
+	}
+	r, err := c.AllContainers()
+	if err == nil {
+		lastErr = ""
+		return r, nil
+	}
+	// A wedged or stopped daemon fails on every check so only warn on the
+	// first error of a kind in a streak.
+	if kind := errorKind(err); kind != lastErr {
+		log.Warnf("unable to collect container stats: %s", err)
+		lastErr = kind
+	} else {
+		log.Debugf("unable to collect container stats: %s", err)
+	}
+	return nil, nil
+}
+
+// errorKind returns what identifies the kind of a collection error in the
+// log deduplication: its sentinel if any, its message otherwise.
+func errorKind(err error) string {
+	if cerr, ok := err.(*CollectionError); ok {
+		return cerr.Kind.Error()
+	}
+	return err.Error()
+}
+
+// AllContainers returns the Docker containers with their latest stats, from
+// the snapshot of the background refresh with Config.BackgroundRefresh.
+func (d *dockerUtil) AllContainers() ([]*Container, error) {
+	if d.cfg.BackgroundRefresh {
+		return d.latestContainers()
+	}
+	return d.containers()
+}
+
+// GetHostname returns the name of the Docker daemon's host.
+func (d *dockerUtil) GetHostname() (string, error) {
+	return d.getHostname()
+}
+
+// Close stops the background refresh and the events watcher, if any, and
+// closes the client.
+func (d *dockerUtil) Close() {
+	if err := d.close(); err != nil {
+		log.Debugf("error closing docker client: %s", err)
+	}
+}
+
+// AllContainers returns the containerd containers with their latest stats.
+func (c *containerdUtil) AllContainers() ([]*Container, error) {
+	return c.containers()
+}
+
+// GetHostname is not supported by containerd.
+func (c *containerdUtil) GetHostname() (string, error) {
+	return "", errHostnameNotSupported
+}
+
+// Close closes the connection to containerd.
+func (c *containerdUtil) Close() {
+	if err := c.cli.Close(); err != nil {
+		log.Debugf("error closing containerd client: %s", err)
+	}
+}
@@ -0,0 +1,220 @@
+package docker
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// containerJSON is the JSON shape of a Container. The stats are flattened
+// with a prefix per kind (cpu_, mem_, io_, net_) and reported as 0 when
+// unknown, the internal fields aren't included. Fields may be added but the
+// existing ones keep their name and meaning.
+type containerJSON struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Names      []string `json:"names"`
+	Image      string   `json:"image"`
+	ImageID    string   `json:"image_id"`
+	ImageTag   string   `json:"image_tag"`
+	Command    string   `json:"command"`
+	State      string   `json:"state"`
+	Health     string   `json:"health"`
+	Created    int64    `json:"created"`
+	StartedAt  int64    `json:"started_at"`
+	Uptime     int64    `json:"uptime"`
+	Pids       []int32  `json:"pids"`
+	CgroupPath string   `json:"cgroup_path"`
+
+	CPULimit         float64 `json:"cpu_limit"`
+	CpusetCount      int     `json:"cpuset_count"`
+	CPUShares        int64   `json:"cpu_shares"`
+	CPUUser          uint64  `json:"cpu_user"`
+	CPUSystem        uint64  `json:"cpu_system"`
+	CPUNrThrottled   uint64  `json:"cpu_nr_throttled"`
+	CPUThrottledTime uint64  `json:"cpu_throttled_time"`
+	CPUPressure      float64 `json:"cpu_pressure"`
+
+	MemLimit       uint64  `json:"mem_limit"`
+	MemSoftLimit   uint64  `json:"mem_soft_limit"`
+	MemLow         uint64  `json:"mem_low"`
+	MemMin         uint64  `json:"mem_min"`
+	MemReservation uint64  `json:"mem_reservation"`
+	MemRSS         uint64  `json:"mem_rss"`
+	MemCache       uint64  `json:"mem_cache"`
+	MemSwap        uint64  `json:"mem_swap"`
+	MemWorkingSet  uint64  `json:"mem_working_set"`
+	MemUsage       uint64  `json:"mem_usage"`
+	MemFailCnt     uint64  `json:"mem_fail_cnt"`
+	MemOOMKills    uint64  `json:"mem_oom_kills"`
+	MemPressure    float64 `json:"mem_pressure"`
+
+	IOReadBytes  uint64                  `json:"io_read_bytes"`
+	IOWriteBytes uint64                  `json:"io_write_bytes"`
+	IODevices    map[string]ioDeviceJSON `json:"io_devices"`
+	IOPressure   float64                 `json:"io_pressure"`
+
+	NetBytesSent    uint64                 `json:"net_bytes_sent"`
+	NetBytesRcvd    uint64                 `json:"net_bytes_rcvd"`
+	NetPacketsSent  uint64                 `json:"net_packets_sent"`
+	NetPacketsRcvd  uint64                 `json:"net_packets_rcvd"`
+	NetPerInterface map[string]networkJSON `json:"net_per_interface"`
+
+	PidsCurrent uint64 `json:"pids_current"`
+	PidsLimit   uint64 `json:"pids_limit"`
+	SizeRw      int64  `json:"size_rw"`
+	SizeRootFs  int64  `json:"size_root_fs"`
+	StatErrors  int    `json:"stat_errors"`
+
+	Healthcheck         *healthcheckJSON  `json:"healthcheck"`
+	HealthFailingStreak int32             `json:"health_failing_streak"`
+	RestartCount        int32             `json:"restart_count"`
+	Labels              map[string]string `json:"labels"`
+	Tags                []string          `json:"tags"`
+	Annotations         map[string]int    `json:"annotations"`
+	ComposeProject      string            `json:"compose_project"`
+	ComposeService      string            `json:"compose_service"`
+	LogDriver           string            `json:"log_driver"`
+	LogPath             string            `json:"log_path"`
+	Privileged          bool              `json:"privileged"`
+	GpuCount            int32             `json:"gpu_count"`
+	GpuVendor           string            `json:"gpu_vendor"`
+	NetworkMode         string            `json:"network_mode"`
+	PidNamespaceOwner   string            `json:"pid_namespace_owner,omitempty"`
+	ExitCode            int32             `json:"exit_code"`
+	OOMKilled           bool              `json:"oom_killed"`
+	Mounts              []mountJSON       `json:"mounts"`
+	IPAddresses         []string          `json:"ip_addresses"`
+}
+
+type mountJSON struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Type        string `json:"type"`
+	RW          bool   `json:"rw"`
+}
+
+type ioDeviceJSON struct {
+	ReadBytes  uint64 `json:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes"`
+}
+
+type networkJSON struct {
+	BytesSent   uint64 `json:"bytes_sent"`
+	BytesRcvd   uint64 `json:"bytes_rcvd"`
+	PacketsSent uint64 `json:"packets_sent"`
+	PacketsRcvd uint64 `json:"packets_rcvd"`
+}
+
+type healthcheckJSON struct {
+	Test     []string      `json:"test"`
+	Interval time.Duration `json:"interval_ns"`
+	Retries  int           `json:"retries"`
+}
+
+// MarshalJSON encodes the container in the flat shape of containerJSON, e.g.
+// to print the containers collected by AllContainers when debugging.
+func (c *Container) MarshalJSON() ([]byte, error) {
+	j := containerJSON{
+		Type:                c.Type,
+		ID:                  c.ID,
+		Name:                c.Name,
+		Names:               c.Names,
+		Image:               c.Image,
+		ImageID:             c.ImageID,
+		ImageTag:            c.ImageTag,
+		Command:             c.Command,
+		State:               c.State,
+		Health:              c.Health,
+		Created:             c.Created,
+		StartedAt:           c.StartedAt,
+		Uptime:              c.Uptime,
+		Pids:                c.Pids,
+		CgroupPath:          c.CgroupPath,
+		CPULimit:            c.CPULimit,
+		CpusetCount:         c.CpusetCount,
+		CPUShares:           c.CpuShares,
+		MemLimit:            c.MemLimit,
+		MemSoftLimit:        c.MemSoftLimit,
+		MemLow:              c.MemLow,
+		MemMin:              c.MemMin,
+		MemReservation:      c.MemReservation,
+		PidsCurrent:         c.PidsCurrent,
+		PidsLimit:           c.PidsLimit,
+		SizeRw:              c.SizeRw,
+		SizeRootFs:          c.SizeRootFs,
+		StatErrors:          c.StatErrors,
+		HealthFailingStreak: c.HealthFailingStreak,
+		RestartCount:        c.RestartCount,
+		Labels:              c.Labels,
+		Tags:                c.Tags,
+		Annotations:         c.Annotations,
+		ComposeProject:      c.ComposeProject,
+		ComposeService:      c.ComposeService,
+		LogDriver:           c.LogDriver,
+		LogPath:             c.LogPath,
+		Privileged:          c.Privileged,
+		GpuCount:            c.GpuCount,
+		GpuVendor:           c.GpuVendor,
+		NetworkMode:         c.NetworkMode,
+		PidNamespaceOwner:   c.PidNamespaceOwner,
+		ExitCode:            c.ExitCode,
+		OOMKilled:           c.OOMKilled,
+		IPAddresses:         c.IPAddresses,
+	}
+	if c.CPU != nil {
+		j.CPUUser = c.CPU.User
+		j.CPUSystem = c.CPU.System
+		j.CPUNrThrottled = c.CPU.NrThrottled
+		j.CPUThrottledTime = c.CPU.ThrottledTime
+		j.CPUPressure = c.CPU.CPUPressure
+	}
+	if c.Memory != nil {
+		j.MemRSS = c.Memory.RSS
+		j.MemCache = c.Memory.Cache
+		j.MemSwap = c.Memory.Swap
+		j.MemWorkingSet = c.Memory.WorkingSet
+		j.MemUsage = c.Memory.MemUsageInBytes
+		j.MemFailCnt = c.Memory.MemFailCnt
+		j.MemOOMKills = c.Memory.OOMKills
+		j.MemPressure = c.Memory.MemoryPressure
+	}
+	if c.IO != nil {
+		j.IOReadBytes = c.IO.ReadBytes
+		j.IOWriteBytes = c.IO.WriteBytes
+		j.IOPressure = c.IO.IOPressure
+		if c.IO.Devices != nil {
+			j.IODevices = make(map[string]ioDeviceJSON, len(c.IO.Devices))
+			for name, dev := range c.IO.Devices {
+				j.IODevices[name] = ioDeviceJSON{ReadBytes: dev.ReadBytes, WriteBytes: dev.WriteBytes}
+			}
+		}
+	}
+	if c.Network != nil {
+		j.NetBytesSent = c.Network.BytesSent
+		j.NetBytesRcvd = c.Network.BytesRcvd
+		j.NetPacketsSent = c.Network.PacketsSent
+		j.NetPacketsRcvd = c.Network.PacketsRcvd
+		if c.Network.PerInterface != nil {
+			j.NetPerInterface = make(map[string]networkJSON, len(c.Network.PerInterface))
+			for name, nw := range c.Network.PerInterface {
+				if nw == nil {
+					continue
+				}
+				j.NetPerInterface[name] = networkJSON{
+					BytesSent:   nw.BytesSent,
+					BytesRcvd:   nw.BytesRcvd,
+					PacketsSent: nw.PacketsSent,
+					PacketsRcvd: nw.PacketsRcvd,
+				}
+			}
+		}
+	}
+	for _, m := range c.Mounts {
+		j.Mounts = append(j.Mounts, mountJSON{Source: m.Source, Destination: m.Destination, Type: m.Type, RW: m.RW})
+	}
+	if hc := c.HealthcheckConfig; hc != nil {
+		j.Healthcheck = &healthcheckJSON{Test: hc.Test, Interval: hc.Interval, Retries: hc.Retries}
+	}
+	return json.Marshal(j)
+}
@@ -0,0 +1,55 @@
+package docker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerMarshalJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	ctr := &Container{
+		Type:     "Docker",
+		ID:       "1",
+		Name:     "redis",
+		Image:    "redis:latest",
+		State:    "running",
+		CPULimit: 50,
+		CPU:      &CgroupTimesStat{ContainerID: "1", User: 100, System: 20},
+		Memory:   &CgroupMemStat{ContainerID: "1", RSS: 1024, WorkingSet: 2048},
+		IO:       &CgroupIOStat{ContainerID: "1", ReadBytes: 10, Devices: map[string]IODeviceStat{"sda": {ReadBytes: 10}}},
+		Network: &NetworkStat{
+			BytesSent:    300,
+			PerInterface: map[string]*NetworkStat{"bridge": {BytesSent: 300}},
+		},
+		cgroup: &ContainerCgroup{ContainerID: "1", Paths: map[string]string{"memory": "/docker/1"}},
+	}
+	b, err := json.Marshal(ctr)
+	assert.NoError(err)
+
+	var fields map[string]interface{}
+	assert.NoError(json.Unmarshal(b, &fields))
+	assert.Equal("1", fields["id"])
+	assert.Equal("redis:latest", fields["image"])
+	assert.Equal(float64(50), fields["cpu_limit"])
+	assert.Equal(float64(100), fields["cpu_user"])
+	assert.Equal(float64(1024), fields["mem_rss"])
+	assert.Equal(float64(2048), fields["mem_working_set"])
+	assert.Equal(float64(10), fields["io_read_bytes"])
+	assert.Equal(map[string]interface{}{"read_bytes": float64(10), "write_bytes": float64(0)}, fields["io_devices"].(map[string]interface{})["sda"])
+	assert.Equal(float64(300), fields["net_bytes_sent"])
+	assert.Contains(fields["net_per_interface"], "bridge")
+	assert.NotContains(fields, "cgroup")
+	assert.NotContains(fields, "CPU")
+	assert.NotContains(string(b), "/docker/1")
+
+	// The stats of a container without stats are reported as 0.
+	b, err = json.Marshal(&Container{ID: "2"})
+	assert.NoError(err)
+	fields = nil
+	assert.NoError(json.Unmarshal(b, &fields))
+	assert.Equal(float64(0), fields["cpu_user"])
+	assert.Equal(float64(0), fields["net_bytes_rcvd"])
+}
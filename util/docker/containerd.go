@@ -0,0 +1,214 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	log "github.com/cihub/seelog"
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/namespaces"
+
+	"github.com/DataDog/datadog-process-agent/util"
+)
+
+var (
+	// ErrContainerdNotAvailable is returned if containerd is not running on the current machine.
+	ErrContainerdNotAvailable = errors.New("containerd not available")
+)
+
+// containerdUtil wraps interactions with a local containerd daemon. It is
+// used on nodes running containerd directly without a Docker daemon.
+type containerdUtil struct {
+	cfg *Config
+	cli *containerd.Client
+	// image digest by image name cache
+	imageIDByName map[string]string
+	// namespace by container id, from the last listing
+	namespaceByID map[string]string
+	sync.Mutex
+}
+
+// connectToContainerd connects to the local containerd socket.
+// Returns ErrContainerdNotAvailable if the socket or mounts file is missing
+// otherwise it returns either a valid client or an error.
+func connectToContainerd() (*containerd.Client, error) {
+	sockPath := util.GetEnv("CONTAINERD_SOCKET_PATH", "/run/containerd/containerd.sock")
+	if !util.PathExists(sockPath) {
+		return nil, ErrContainerdNotAvailable
+	}
+	// Like Docker we rely on cgroups for the stats so only Linux is supported.
+	if !cgroupsAvailable() {
+		return nil, ErrContainerdNotAvailable
+	}
+	return containerd.New(sockPath)
+}
+
+// IsContainerdAvailable returns true if containerd is available on this machine via a socket.
+func IsContainerdAvailable() bool {
+	cli, err := connectToContainerd()
+	if err != nil {
+		if err != ErrContainerdNotAvailable {
+			log.Warnf("unable to connect to containerd: %s", err)
+		}
+		return false
+	}
+	cli.Close()
+	return true
+}
+
+// InitContainerdUtil initializes the containerd collector. It is only used by
+// AllContainers when Docker has not been initialized.
+func InitContainerdUtil(cfg *Config) error {
+	cli, err := connectToContainerd()
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.parse(); err != nil {
+		return err
+	}
+
+	c := &containerdUtil{
+		cfg:           cfg,
+		cli:           cli,
+		imageIDByName: make(map[string]string),
+		namespaceByID: make(map[string]string),
+	}
+	if globalDockerUtil == nil {
+		setDebugLogInterval(cfg.InvalidationInterval)
+		globalCollector = c
+	}
+	return nil
+}
+
+// containers gets a list of all containerd containers on the current node
+// with their cgroup stats. Network stats are not collected for containerd.
+func (c *containerdUtil) containers() ([]*Container, error) {
+	var fallback func(*Container) error
+	if c.cfg.UseContainerdMetrics {
+		fallback = c.metricsStats
+	}
+	containers, err := cgroupContainers("containerdutil.containers", c.cfg.CacheDuration, c.cfg.CgroupRoot, c.cfg.StatWorkers, c.cfg.CollectionDeadline, c.containerdContainers,
+		func(*Container) (*NetworkStat, error) { return NullContainer.Network, nil }, fallback, c.cfg.Statsd)
+	if err != nil {
+		return nil, err
+	}
+	containers = filterMinUptime(containers, c.cfg.MinUptime)
+	containers = capContainers(containers, nil, c.cfg.MaxContainers, c.cfg.Statsd)
+	enrichContainers(containers)
+	return containers, nil
+}
+
+// containerdContainers returns the containers with an active task in every
+// containerd namespace (e.g. "k8s.io" for Kubernetes, "moby" for Docker).
+func (c *containerdUtil) containerdContainers() ([]*Container, error) {
+	nss, err := c.cli.NamespaceService().List(context.Background())
+	if err != nil {
+		return nil, &CollectionError{Kind: ErrListContainers, Err: fmt.Errorf("error listing namespaces: %s", err)}
+	}
+
+	var ret []*Container
+	liveImages := make(map[string]struct{})
+	namespaceByID := make(map[string]string)
+	for _, ns := range nss {
+		ctx := namespaces.WithNamespace(context.Background(), ns)
+		ctrs, err := c.cli.Containers(ctx)
+		if err != nil {
+			return nil, &CollectionError{Kind: ErrListContainers, Err: fmt.Errorf("namespace %s: %s", ns, err)}
+		}
+		for _, ctr := range ctrs {
+			info, err := ctr.Info(ctx)
+			if err != nil {
+				log.Debugf("error getting info for containerd container %s: %s", ctr.ID(), err)
+				continue
+			}
+			// Containers without a task have no processes so there is nothing
+			// to report, similar to stopped Docker containers.
+			task, err := ctr.Task(ctx, nil)
+			if err != nil {
+				continue
+			}
+			status, err := task.Status(ctx)
+			if err != nil {
+				log.Debugf("error getting task status for containerd container %s: %s", ctr.ID(), err)
+				continue
+			}
+
+			liveImages[info.Image] = struct{}{}
+			namespaceByID[info.ID] = ns
+			container := &Container{
+				Type:    "containerd",
+				ID:      info.ID,
+				Name:    info.ID,
+				Image:   info.Image,
+				ImageID: c.imageID(ctx, info.Image),
+				Created: info.CreatedAt.Unix(),
+				State:   containerdState(status.Status),
+				Labels:  info.Labels,
+			}
+			container.ExitCode = unknownExitCode
+			if status.Status == containerd.Stopped {
+				container.ExitCode = int32(status.ExitStatus)
+			}
+			container.Tags = labelTags(info.Labels, c.cfg.LabelsAsTags)
+			container.Annotations = labelAnnotations(info.ID, info.Labels, c.cfg.AnnotationLabels)
+			if !c.cfg.isExcluded(container) {
+				container.Name = c.cfg.normalizeName(container.Name)
+				ret = append(ret, container)
+			}
+		}
+	}
+
+	c.Lock()
+	c.namespaceByID = namespaceByID
+	for image := range c.imageIDByName {
+		if _, ok := liveImages[image]; !ok {
+			delete(c.imageIDByName, image)
+		}
+	}
+	c.Unlock()
+
+	return ret, nil
+}
+
+// imageID resolves an image name to its digest. Unlike Docker, containerd
+// references images by name so this is the inverse of extractImageName.
+func (c *containerdUtil) imageID(ctx context.Context, image string) string {
+	c.Lock()
+	id, ok := c.imageIDByName[image]
+	c.Unlock()
+	if ok {
+		return id
+	}
+
+	// The lookup is done without the lock so the other users of the maps,
+	// e.g. metricsStats, don't wait for containerd.
+	img, err := c.cli.GetImage(ctx, image)
+	if err != nil {
+		log.Debugf("could not get containerd image %s: %s", image, err)
+	} else {
+		id = img.Target().Digest.String()
+	}
+	c.Lock()
+	c.imageIDByName[image] = id
+	c.Unlock()
+	return id
+}
+
+// containerdState maps a containerd task status to the Docker state names
+// used in the container payload.
+func containerdState(status containerd.ProcessStatus) string {
+	switch status {
+	case containerd.Running:
+		return "running"
+	case containerd.Created:
+		return "created"
+	case containerd.Paused, containerd.Pausing:
+		return "paused"
+	case containerd.Stopped:
+		return "exited"
+	}
+	return "unknown"
+}
@@ -0,0 +1,146 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/containerd/cgroups"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/typeurl"
+)
+
+// The task metrics are the cgroups.Metrics of the root package of
+// containerd/cgroups, at the revision vendored by containerd v1.0.3 and
+// pinned in glide.yaml. Later revisions moved the type to stats/v1, the pin
+// has to move along with containerd.
+
+// metricsStats fills the stats of a containerd container from the metrics of
+// its task, i.e. its cgroup stats as read by containerd. It is used in place
+// of the cgroup files when they can't be found, e.g. when the cgroup paths
+// don't contain the container IDs, at the cost of an API call per container.
+func (c *containerdUtil) metricsStats(container *Container) error {
+	c.Lock()
+	ns, ok := c.namespaceByID[container.ID]
+	c.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown namespace for container %s", container.ID)
+	}
+	ctx := namespaces.WithNamespace(context.Background(), ns)
+	ctr, err := c.cli.LoadContainer(ctx, container.ID)
+	if err != nil {
+		return err
+	}
+	task, err := ctr.Task(ctx, nil)
+	if err != nil {
+		return err
+	}
+	metric, err := task.Metrics(ctx)
+	if err != nil {
+		return err
+	}
+	v, err := typeurl.UnmarshalAny(metric.Data)
+	if err != nil {
+		return fmt.Errorf("unable to decode metrics for container %s: %s", container.ID, err)
+	}
+	metrics, ok := v.(*cgroups.Metrics)
+	if !ok {
+		return fmt.Errorf("unexpected metrics type %T for container %s", v, container.ID)
+	}
+
+	setCgroupMetrics(container, metrics)
+	container.Network = NullContainer.Network
+	container.Uptime = containerUptime(time.Now().Unix(), 0, 0, container.Created)
+	return nil
+}
+
+// setCgroupMetrics maps the cgroup metrics of a containerd task to the stats
+// of the container. Missing sections leave the stats at 0.
+func setCgroupMetrics(container *Container, m *cgroups.Metrics) {
+	id := container.ID
+	container.CPU = &CgroupTimesStat{ContainerID: id}
+	if cpu := m.CPU; cpu != nil {
+		// The CPU times are in nanoseconds like the Docker stats API.
+		if cpu.Usage != nil {
+			container.CPU.User = cpu.Usage.User / nsPerTick
+			container.CPU.System = cpu.Usage.Kernel / nsPerTick
+		}
+		if cpu.Throttling != nil {
+			container.CPU.NrThrottled = cpu.Throttling.ThrottledPeriods
+			container.CPU.ThrottledTime = cpu.Throttling.ThrottledTime
+		}
+	}
+
+	container.Memory = &CgroupMemStat{ContainerID: id}
+	if mem := m.Memory; mem != nil {
+		container.Memory = &CgroupMemStat{
+			ContainerID:             id,
+			Cache:                   mem.Cache,
+			RSS:                     mem.RSS,
+			RSSHuge:                 mem.RSSHuge,
+			MappedFile:              mem.MappedFile,
+			Pgpgin:                  mem.PgPgIn,
+			Pgpgout:                 mem.PgPgOut,
+			Pgfault:                 mem.PgFault,
+			Pgmajfault:              mem.PgMajFault,
+			InactiveAnon:            mem.InactiveAnon,
+			ActiveAnon:              mem.ActiveAnon,
+			InactiveFile:            mem.InactiveFile,
+			ActiveFile:              mem.ActiveFile,
+			Unevictable:             mem.Unevictable,
+			HierarchicalMemoryLimit: mem.HierarchicalMemoryLimit,
+			TotalCache:              mem.TotalCache,
+			TotalRSS:                mem.TotalRSS,
+			TotalRSSHuge:            mem.TotalRSSHuge,
+			TotalMappedFile:         mem.TotalMappedFile,
+			TotalPgpgIn:             mem.TotalPgPgIn,
+			TotalPgpgOut:            mem.TotalPgPgOut,
+			TotalPgFault:            mem.TotalPgFault,
+			TotalPgMajFault:         mem.TotalPgMajFault,
+			TotalInactiveAnon:       mem.TotalInactiveAnon,
+			TotalActiveAnon:         mem.TotalActiveAnon,
+			TotalInactiveFile:       mem.TotalInactiveFile,
+			TotalActiveFile:         mem.TotalActiveFile,
+			TotalUnevictable:        mem.TotalUnevictable,
+		}
+		if mem.Usage != nil {
+			container.Memory.MemUsageInBytes = mem.Usage.Usage
+			container.Memory.MemFailCnt = mem.Usage.Failcnt
+			if !isUnlimitedMem(mem.Usage.Limit) {
+				container.MemLimit = mem.Usage.Limit
+			}
+		}
+		// The swap entry is memory.memsw which includes the memory usage.
+		if mem.Swap != nil && mem.Swap.Usage > container.Memory.MemUsageInBytes {
+			container.Memory.Swap = mem.Swap.Usage - container.Memory.MemUsageInBytes
+		}
+		container.Memory.WorkingSet = workingSet(container.Memory.MemUsageInBytes, mem.TotalInactiveFile)
+	}
+
+	container.IO = &CgroupIOStat{ContainerID: id}
+	if m.Blkio != nil && len(m.Blkio.IoServiceBytesRecursive) > 0 {
+		devices := blockDevices()
+		container.IO.Devices = make(map[string]IODeviceStat)
+		for _, entry := range m.Blkio.IoServiceBytesRecursive {
+			name := deviceName(devices, fmt.Sprintf("%d:%d", entry.Major, entry.Minor))
+			dev := container.IO.Devices[name]
+			switch strings.ToLower(entry.Op) {
+			case "read":
+				dev.ReadBytes += entry.Value
+				container.IO.ReadBytes += entry.Value
+			case "write":
+				dev.WriteBytes += entry.Value
+				container.IO.WriteBytes += entry.Value
+			default:
+				continue
+			}
+			container.IO.Devices[name] = dev
+		}
+	}
+
+	if m.Pids != nil {
+		container.PidsCurrent = m.Pids.Current
+		container.PidsLimit = m.Pids.Limit
+	}
+}
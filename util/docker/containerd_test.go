@@ -0,0 +1,83 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/containerd/cgroups"
+	"github.com/containerd/containerd"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-process-agent/model"
+	"github.com/DataDog/datadog-process-agent/util/cache"
+)
+
+func TestContainerdState(t *testing.T) {
+	assert := assert.New(t)
+	for i, tc := range []struct {
+		input    containerd.ProcessStatus
+		expected string
+	}{
+		{containerd.Running, "running"},
+		{containerd.Created, "created"},
+		{containerd.Paused, "paused"},
+		{containerd.Pausing, "paused"},
+		{containerd.Stopped, "exited"},
+		{containerd.Unknown, "unknown"},
+		{"", "unknown"},
+	} {
+		state := containerdState(tc.input)
+		assert.Equal(tc.expected, state, "test %d failed", i)
+		// All states must be known by the payload.
+		_, ok := model.ContainerState_value[state]
+		assert.True(ok, "test %d: unknown payload state %s", i, state)
+	}
+}
+
+func TestSetCgroupMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	cache.Set(partitionsCacheKey, map[string]string{"8:0": "sda"})
+	defer cache.Delete(partitionsCacheKey)
+	ctr := &Container{ID: "1"}
+	setCgroupMetrics(ctr, &cgroups.Metrics{
+		CPU: &cgroups.CPUStat{
+			Usage:      &cgroups.CPUUsage{Total: 3e9, User: 2e9, Kernel: 1e9},
+			Throttling: &cgroups.Throttle{Periods: 100, ThrottledPeriods: 4, ThrottledTime: 5000},
+		},
+		Memory: &cgroups.MemoryStat{
+			Cache:             1024,
+			RSS:               4096,
+			TotalInactiveFile: 512,
+			Usage:             &cgroups.MemoryEntry{Usage: 6144, Limit: 1 << 30, Failcnt: 2},
+			Swap:              &cgroups.MemoryEntry{Usage: 8192},
+		},
+		Blkio: &cgroups.BlkIOStat{IoServiceBytesRecursive: []*cgroups.BlkIOEntry{
+			{Op: "Read", Major: 8, Minor: 0, Value: 100},
+			{Op: "Write", Major: 8, Minor: 0, Value: 200},
+			{Op: "Total", Major: 8, Minor: 0, Value: 300},
+		}},
+		Pids: &cgroups.PidsStat{Current: 3, Limit: 100},
+	})
+
+	assert.Equal(&CgroupTimesStat{ContainerID: "1", User: 200, System: 100, NrThrottled: 4, ThrottledTime: 5000}, ctr.CPU)
+	assert.Equal(uint64(4096), ctr.Memory.RSS)
+	assert.Equal(uint64(6144), ctr.Memory.MemUsageInBytes)
+	assert.Equal(uint64(2048), ctr.Memory.Swap)
+	assert.Equal(uint64(5632), ctr.Memory.WorkingSet)
+	assert.Equal(uint64(1<<30), ctr.MemLimit)
+	assert.Equal(&CgroupIOStat{
+		ContainerID: "1",
+		ReadBytes:   100,
+		WriteBytes:  200,
+		Devices:     map[string]IODeviceStat{"sda": {ReadBytes: 100, WriteBytes: 200}},
+	}, ctr.IO)
+	assert.Equal(uint64(3), ctr.PidsCurrent)
+	assert.Equal(uint64(100), ctr.PidsLimit)
+
+	// Missing sections are reported as 0.
+	ctr = &Container{ID: "2"}
+	setCgroupMetrics(ctr, &cgroups.Metrics{})
+	assert.Equal(&CgroupTimesStat{ContainerID: "2"}, ctr.CPU)
+	assert.Equal(&CgroupMemStat{ContainerID: "2"}, ctr.Memory)
+	assert.Equal(&CgroupIOStat{ContainerID: "2"}, ctr.IO)
+}
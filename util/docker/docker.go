@@ -1,23 +1,33 @@
 package docker
 
 import (
+	"bufio"
 	"context"
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
+	"unsafe"
 
 	"github.com/DataDog/gopsutil/process"
 	log "github.com/cihub/seelog"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/tlsconfig"
 
 	"github.com/DataDog/datadog-process-agent/util"
 	"github.com/DataDog/datadog-process-agent/util/cache"
@@ -27,10 +37,45 @@ var (
 	// ErrDockerNotAvailable is returned if Docker is not running on the current machine.
 	// We'll use this when configuring the DockerUtil so we don't error on non-docker machines.
 	ErrDockerNotAvailable = errors.New("docker not available")
-
-	globalDockerUtil     *dockerUtil
-	invalidationInterval = 5 * time.Minute
-	lastErr              string
+	// ErrDockerTimeout is returned if a Docker API call did not complete
+	// within the configured OperationTimeout.
+	ErrDockerTimeout = errors.New("docker API call timed out")
+	// ErrContainerNotFound is returned by GetContainer if the container
+	// doesn't exist anymore.
+	ErrContainerNotFound = errors.New("container not found")
+	// ErrAmbiguousContainerID is returned by GetContainer if a short ID is
+	// the prefix of the IDs of several containers.
+	ErrAmbiguousContainerID = errors.New("ambiguous container ID")
+	// ErrListContainers is the Kind of the CollectionError returned when the
+	// container runtime fails to list the containers, e.g. the daemon is down.
+	// Timeouts are returned as ErrDockerTimeout.
+	ErrListContainers = errors.New("error listing containers")
+	// ErrPidList is the Kind of the CollectionError returned when the
+	// processes of the host can't be listed.
+	ErrPidList = errors.New("could not get pids")
+	// ErrCgroupParse is the Kind of the CollectionError returned when the
+	// cgroups of the processes can't be read.
+	ErrCgroupParse = errors.New("could not get cgroups for pids")
+
+	globalDockerUtil *dockerUtil
+	// defaultInvalidationInterval is used when Config.InvalidationInterval is unset.
+	defaultInvalidationInterval = 5 * time.Minute
+	// defaultOperationTimeout is used when Config.OperationTimeout is unset.
+	defaultOperationTimeout = 10 * time.Second
+	// negativeImageTTL is how long we wait before retrying to resolve an image name.
+	negativeImageTTL = 2 * time.Minute
+	// After backoffThreshold consecutive failures we stop calling the daemon
+	// for backoffInitial, doubling on each failure up to backoffMax.
+	backoffThreshold = 3
+	backoffInitial   = 10 * time.Second
+	backoffMax       = 5 * time.Minute
+	// The last containers are served for up to maxLocalFailures consecutive
+	// failures to read the host processes or cgroups, then the error is.
+	maxLocalFailures = 3
+	lastErr          string
+	// apiVersionErrorRe matches the daemon errors returned when the client
+	// API version isn't supported anymore, e.g. after a daemon upgrade.
+	apiVersionErrorRe = regexp.MustCompile(`client version \S+ is too (old|new)|client is newer than server`)
 
 	// NullContainer is an empty container object that has
 	// default values for all fields including sub-fields.
@@ -44,12 +89,38 @@ var (
 	}
 )
 
+// Container types of the daemons serving the Docker API.
+const (
+	dockerContainerType = "Docker"
+	podmanContainerType = "podman"
+
+	// WhitelistModeOverride only uses the whitelist to include containers
+	// that are blacklisted.
+	WhitelistModeOverride = "override"
+	// WhitelistModeStrict excludes all the containers which don't match a
+	// non-empty whitelist.
+	WhitelistModeStrict = "strict"
+
+	// Labels set by Docker Compose on the containers of a service.
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+
+	// containersCacheKey caches the Docker containers with their cgroup.
+	containersCacheKey = "dockerutil.containers"
+
+	// maxCommandLength is the maximum length in bytes of Container.Command.
+	maxCommandLength = 255
+)
+
 // NetworkStat stores network statistics about a Docker container.
 type NetworkStat struct {
 	BytesSent   uint64
 	BytesRcvd   uint64
 	PacketsSent uint64
 	PacketsRcvd uint64
+	// PerInterface holds the stats keyed by docker network name. It is only
+	// set when Config.CollectNetworkPerInterface is enabled.
+	PerInterface map[string]*NetworkStat
 }
 
 type containerFilter struct {
@@ -58,51 +129,86 @@ type containerFilter struct {
 	NameWhitelist  []*regexp.Regexp
 	ImageBlacklist []*regexp.Regexp
 	NameBlacklist  []*regexp.Regexp
+	// The network mode filters need Container.NetworkMode, only known once
+	// the container was inspected.
+	NetworkModeWhitelist []*regexp.Regexp
+	NetworkModeBlacklist []*regexp.Regexp
+	// Strict excludes the containers which don't match the whitelist.
+	Strict bool
 }
 
 // NewcontainerFilter creates a new container filter from a two slices of
 // regexp patterns for a whitelist and blacklist. Each pattern should have
-// the following format: "field:pattern" where field can be: [image, name].
-// An error is returned if any of the expression don't compile.
-func newContainerFilter(whitelist, blacklist []string) (*containerFilter, error) {
-	iwl, nwl, err := parseFilters(whitelist)
+// the following format: "field:pattern" where field can be: [image, name,
+// networkmode]. The name patterns match the first name returned by Docker,
+// with its leading "/", e.g. "/redis".
+// The whitelist mode is one of WhitelistModeOverride, the default, and
+// WhitelistModeStrict. An error is returned if any of the expression don't
+// compile or if the mode is unknown.
+func newContainerFilter(whitelist, blacklist []string, whitelistMode string) (*containerFilter, error) {
+	var strict bool
+	switch whitelistMode {
+	case "", WhitelistModeOverride:
+	case WhitelistModeStrict:
+		strict = true
+	default:
+		return nil, fmt.Errorf("invalid whitelist mode '%s'", whitelistMode)
+	}
+	iwl, nwl, mwl, err := parseFilters(whitelist)
 	if err != nil {
 		return nil, err
 	}
-	ibl, nbl, err := parseFilters(blacklist)
+	ibl, nbl, mbl, err := parseFilters(blacklist)
 	if err != nil {
 		return nil, err
 	}
 
 	return &containerFilter{
-		Enabled:        len(whitelist) > 0 || len(blacklist) > 0,
-		ImageWhitelist: iwl,
-		NameWhitelist:  nwl,
-		ImageBlacklist: ibl,
-		NameBlacklist:  nbl,
+		Enabled:              len(whitelist) > 0 || len(blacklist) > 0,
+		ImageWhitelist:       iwl,
+		NameWhitelist:        nwl,
+		ImageBlacklist:       ibl,
+		NameBlacklist:        nbl,
+		NetworkModeWhitelist: mwl,
+		NetworkModeBlacklist: mbl,
+		Strict:               strict && len(iwl)+len(nwl)+len(mwl) > 0,
 	}, nil
 }
 
-func parseFilters(filters []string) (imageFilters, nameFilters []*regexp.Regexp, err error) {
+func parseFilters(filters []string) (imageFilters, nameFilters, networkModeFilters []*regexp.Regexp, err error) {
 	for _, filter := range filters {
 		switch {
 		case strings.HasPrefix(filter, "image:"):
 			pat := strings.TrimPrefix(filter, "image:")
 			r, err := regexp.Compile(strings.TrimPrefix(pat, "image:"))
 			if err != nil {
-				return nil, nil, fmt.Errorf("invalid regex '%s': %s", pat, err)
+				return nil, nil, nil, fmt.Errorf("invalid regex '%s': %s", pat, err)
 			}
 			imageFilters = append(imageFilters, r)
 		case strings.HasPrefix(filter, "name:"):
 			pat := strings.TrimPrefix(filter, "name:")
 			r, err := regexp.Compile(pat)
 			if err != nil {
-				return nil, nil, fmt.Errorf("invalid regex '%s': %s", pat, err)
+				return nil, nil, nil, fmt.Errorf("invalid regex '%s': %s", pat, err)
 			}
 			nameFilters = append(nameFilters, r)
+		case strings.HasPrefix(filter, "networkmode:"):
+			// The modes are a few keywords, e.g. host or none, matched as a
+			// whole rather than as substrings of the network names.
+			pat := strings.TrimPrefix(filter, "networkmode:")
+			r, err := regexp.Compile("^(?:" + pat + ")$")
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid regex '%s': %s", pat, err)
+			}
+			networkModeFilters = append(networkModeFilters, r)
 		}
 	}
-	return imageFilters, nameFilters, nil
+	return imageFilters, nameFilters, networkModeFilters, nil
+}
+
+// needsNetworkMode returns true if any filter matches the network mode.
+func (cf *containerFilter) needsNetworkMode() bool {
+	return cf != nil && len(cf.NetworkModeWhitelist)+len(cf.NetworkModeBlacklist) > 0
 }
 
 // IsExcluded returns a bool indicating if the container should be excluded
@@ -120,34 +226,66 @@ func (cf containerFilter) IsExcluded(container *Container) bool {
 		}
 	}
 	for _, r := range cf.NameBlacklist {
-		if r.MatchString(container.Name) {
+		if r.MatchString(container.filterName()) {
+			excluded = true
+			break
+		}
+	}
+	for _, r := range cf.NetworkModeBlacklist {
+		if r.MatchString(container.NetworkMode) {
 			excluded = true
 			break
 		}
 	}
 
-	// Any excluded container could be whitelisted.
-	if excluded {
+	// Any excluded container could be whitelisted. In strict mode all the
+	// containers have to be whitelisted.
+	if excluded || cf.Strict {
 		for _, r := range cf.ImageWhitelist {
 			if r.MatchString(container.Image) {
 				return false
 			}
 		}
 		for _, r := range cf.NameWhitelist {
-			if r.MatchString(container.Name) {
+			if r.MatchString(container.filterName()) {
 				return false
 			}
 		}
+		for _, r := range cf.NetworkModeWhitelist {
+			if r.MatchString(container.NetworkMode) {
+				return false
+			}
+		}
+		return true
 	}
 	return excluded
 }
 
+// pauseImages match the images of the infra containers holding the namespaces
+// of the Kubernetes pods, including the registry mirrors.
+var pauseImages = []*regexp.Regexp{
+	regexp.MustCompile(`(^|/)google_containers/pause(-[a-z0-9]+)?([:@]|$)`),
+	regexp.MustCompile(`^(k8s\.gcr\.io|registry\.k8s\.io)/pause(-[a-z0-9]+)?([:@]|$)`),
+	regexp.MustCompile(`(^|/)rancher/(mirrored-)?pause(-[a-z0-9]+)?([:@]|$)`),
+}
+
+// isPauseImage returns true if the image is the one of a pod infra container.
+func isPauseImage(image string) bool {
+	for _, r := range pauseImages {
+		if r.MatchString(image) {
+			return true
+		}
+	}
+	return false
+}
+
 // Container represents a single Docker container on a machine
 // and includes Cgroup-level statistics about the container.
 type Container struct {
 	Type    string
 	ID      string
 	Name    string
+	Names   []string
 	Image   string
 	ImageID string
 	Created int64
@@ -163,8 +301,295 @@ type Container struct {
 	Network   *NetworkStat
 	StartedAt int64
 
+	// Memory reservations, MemLow and MemMin are only set on cgroup v2
+	// and MemSoftLimit on cgroup v1.
+	MemLow       uint64
+	MemMin       uint64
+	MemSoftLimit uint64
+	// MemReservation is the memory soft limit in bytes set with
+	// --memory-reservation, 0 if unset, only known once the container was
+	// inspected. CpuShares is the relative CPU weight set with --cpu-shares,
+	// 1024 by default. It is read from the cgroup when the container wasn't
+	// inspected or has the default weight, the cgroup v2 cpu.weight being
+	// converted to shares.
+	MemReservation uint64
+	CpuShares      int64
+
+	// PidsCurrent is the number of processes of the container, from the pids
+	// controller or the processes of the cgroup without it. PidsLimit is the
+	// maximum number of processes, 0 if unlimited or unknown.
+	PidsCurrent uint64
+	PidsLimit   uint64
+
+	// CpusetCount is the number of CPUs the container may run on from its
+	// cpuset, 0 if unknown.
+	CpusetCount int
+
+	// SizeRw and SizeRootFs are only set when Config.CollectDiskStats is enabled.
+	SizeRw     int64
+	SizeRootFs int64
+
+	// StatErrors is the number of cgroup stats that could not be read for
+	// this collection, these stats are left to their null values.
+	StatErrors int
+
+	// HealthcheckConfig is only set when Config.CollectHealthcheckConfig is
+	// enabled and will be nil for containers without a healthcheck.
+	HealthcheckConfig *HealthcheckConfig
+	// RestartCount is only set when Config.CollectRestartCount is enabled.
+	RestartCount int32
+	// HealthFailingStreak is the number of consecutive failed health probes,
+	// only set when Config.CollectHealthcheckConfig is enabled.
+	HealthFailingStreak int32
+	// Labels are the labels set on the container, e.g. by orchestrators.
+	Labels map[string]string
+	// ComposeProject and ComposeService are the Docker Compose project and
+	// service of the container, empty if it wasn't started by Compose.
+	ComposeProject string
+	ComposeService string
+	// Command is the command the container was started with, truncated to
+	// maxCommandLength bytes to keep the payloads bounded.
+	Command string
+	// ImageTag is the tag of Image, empty if it isn't tagged.
+	ImageTag string
+	// Tags are the labels named by Config.LabelsAsTags formatted as key:value.
+	Tags []string
+	// Annotations are the labels named by Config.AnnotationLabels with an
+	// integer value, e.g. the findings of an image scanner.
+	Annotations map[string]int
+	// LogDriver is the logging driver of the container and LogPath the host
+	// path of its log file with the json-file driver. They are only set once
+	// the container was inspected, LogPath is for local use only.
+	LogDriver string
+	LogPath   string
+	// Privileged is true for the containers running in privileged mode. It's
+	// only known once the container was inspected, false may mean unknown
+	// when none of the collections need container.Inspect.
+	Privileged bool
+	// GpuCount is the number of GPUs assigned to the container, from GpuVendor.
+	// Like Privileged, it's only known once the container was inspected.
+	GpuCount  int32
+	GpuVendor string
+	// ExitCode is the exit code of a stopped container and OOMKilled whether
+	// the kernel killed it for lack of memory, from container.Inspect. Running
+	// containers, and stopped ones not inspected since they stopped, have an
+	// ExitCode of -1.
+	ExitCode  int32
+	OOMKilled bool
+	// NetworkMode is the network mode of the container, e.g. bridge, host,
+	// none or container:<id>. Like Privileged, it's only known once the
+	// container was inspected.
+	NetworkMode string
+	// PidNamespaceOwner is the ID of the container whose PID namespace this
+	// one joined with --pid=container:<id>, empty if it has its own. Its
+	// Pids are still the ones of its own cgroup, the owner's aren't counted
+	// twice. Like Privileged, it's only known once the container was
+	// inspected.
+	PidNamespaceOwner string
+	// Mounts are the bind mounts and volumes of the container. Like
+	// Privileged, they're only known once the container was inspected. Only
+	// their counts are sent in the payloads.
+	Mounts []MountPoint
+	// IPAddresses are the IPv4 addresses of the container in its networks,
+	// sorted by network name. Containers in network host mode have none
+	// since they use the addresses of the host.
+	IPAddresses []string
+
+	// Uptime is the number of seconds since the container started. It prefers
+	// the StartedAt from container.Inspect, when it was inspected, over the
+	// cgroup start time.
+	Uptime int64
+
+	// Endpoint is the Docker host of the daemon running the container when
+	// it's collected from one of several endpoints, see NewEndpointsCollector.
+	// It's empty for the containers of the local runtime.
+	Endpoint string
+
+	// CgroupPath is the cgroup directory the stats are read from, for local
+	// diagnostics only. It isn't sent in the payloads.
+	CgroupPath string
+
 	// For internal use only
-	cgroup *ContainerCgroup
+	cgroup           *ContainerCgroup
+	inspectStartedAt int64
+	// rawName is the name as returned by Docker, with its leading "/", which
+	// the name filters match for compatibility with the existing patterns.
+	rawName string
+}
+
+// Types of the MountPoint of the bind mounts and of the volumes.
+const (
+	mountTypeBind   = "bind"
+	mountTypeVolume = "volume"
+)
+
+// MountPoint is a bind mount or a volume of a container.
+type MountPoint struct {
+	// Source is the host path of a bind mount or the data directory of a
+	// volume.
+	Source      string
+	Destination string
+	// Type is "bind", "volume" or "tmpfs".
+	Type string
+	// RW is true for the read-write mounts.
+	RW bool
+}
+
+// MountCounts returns the number of mounts of a container and how many of
+// them are bind mounts and volumes.
+func (c *Container) MountCounts() (total, binds, volumes int) {
+	for _, m := range c.Mounts {
+		switch m.Type {
+		case mountTypeBind:
+			binds++
+		case mountTypeVolume:
+			volumes++
+		}
+	}
+	return len(c.Mounts), binds, volumes
+}
+
+// HealthcheckConfig is the healthcheck configured for a container.
+type HealthcheckConfig struct {
+	// Test is the healthcheck command, e.g. ["CMD-SHELL", "curl localhost"].
+	// ["NONE"] means that the healthcheck was disabled.
+	Test     []string
+	Interval time.Duration
+	Retries  int
+}
+
+// containerDetails is the container metadata only available with a call to
+// container.Inspect. It is cached per container since it does not change
+// during the lifetime of the container, except for the restart count which
+// is refreshed when the container state changes and the health which is
+// refreshed when it changes or while the container is unhealthy.
+type containerDetails struct {
+	healthcheck  *HealthcheckConfig
+	restartCount int32
+	// health status and number of consecutive failed probes
+	health        string
+	failingStreak int32
+	// startedAt is the unix time the container last started, 0 if unknown
+	startedAt int64
+	// state and health from the container list when the details were
+	// inspected
+	state      string
+	listHealth string
+	// logging driver and json-file log path
+	logDriver string
+	logPath   string
+	// privileged mode
+	privileged bool
+	// assigned GPUs
+	gpuCount  int32
+	gpuVendor string
+	mounts    []MountPoint
+	// network mode from the host config
+	networkMode string
+	// container whose PID namespace was joined, as given to --pid
+	pidNamespaceOwner string
+	// exit status of the last run
+	exitCode  int32
+	oomKilled bool
+	// reservations from the host config, 0 if unset
+	memReservation uint64
+	cpuShares      int64
+}
+
+func newContainerDetails(i types.ContainerJSON) *containerDetails {
+	details := &containerDetails{}
+	if i.ContainerJSONBase != nil {
+		details.restartCount = int32(i.RestartCount)
+		details.logPath = i.LogPath
+		if i.HostConfig != nil {
+			details.logDriver = i.HostConfig.LogConfig.Type
+			details.privileged = i.HostConfig.Privileged
+			details.networkMode = string(i.HostConfig.NetworkMode)
+			details.pidNamespaceOwner = sharedPidNamespace(i)
+			if i.HostConfig.MemoryReservation > 0 {
+				details.memReservation = uint64(i.HostConfig.MemoryReservation)
+			}
+			details.cpuShares = i.HostConfig.CPUShares
+		}
+		details.gpuCount, details.gpuVendor = containerGPUs(i)
+		if i.State != nil {
+			// Containers which never started have a zero time.
+			if t, err := time.Parse(time.RFC3339Nano, i.State.StartedAt); err == nil && t.Unix() > 0 {
+				details.startedAt = t.Unix()
+			}
+			if i.State.Health != nil {
+				details.health = i.State.Health.Status
+				details.failingStreak = int32(i.State.Health.FailingStreak)
+			}
+			details.exitCode = int32(i.State.ExitCode)
+			details.oomKilled = i.State.OOMKilled
+		}
+	}
+	details.mounts = containerMounts(i.Mounts)
+	if i.Config != nil && i.Config.Healthcheck != nil {
+		details.healthcheck = &HealthcheckConfig{
+			Test:     i.Config.Healthcheck.Test,
+			Interval: i.Config.Healthcheck.Interval,
+			Retries:  i.Config.Healthcheck.Retries,
+		}
+	}
+	return details
+}
+
+// containerMounts converts the mounts from container.Inspect.
+func containerMounts(mounts []types.MountPoint) []MountPoint {
+	if len(mounts) == 0 {
+		return nil
+	}
+	ret := make([]MountPoint, 0, len(mounts))
+	for _, m := range mounts {
+		ret = append(ret, MountPoint{
+			Source:      m.Source,
+			Destination: m.Destination,
+			Type:        string(m.Type),
+			RW:          m.RW,
+		})
+	}
+	return ret
+}
+
+// gpuVendorNvidia is the vendor of the GPUs found by containerGPUs.
+const gpuVendorNvidia = "nvidia"
+
+// nvidiaDeviceRe matches the nvidia GPU devices, e.g. /dev/nvidia0, but not
+// the shared devices like /dev/nvidiactl or /dev/nvidia-uvm.
+var nvidiaDeviceRe = regexp.MustCompile(`^/dev/nvidia[0-9]+$`)
+
+// containerGPUs returns the number of GPUs assigned to a container and their
+// vendor. The API version we use doesn't expose the DeviceRequests of --gpus
+// so the GPUs are found in the devices mapped in the container or, for the
+// nvidia runtime, in NVIDIA_VISIBLE_DEVICES. The count of
+// NVIDIA_VISIBLE_DEVICES=all isn't known and is reported as 0.
+func containerGPUs(i types.ContainerJSON) (int32, string) {
+	var count int32
+	if i.ContainerJSONBase != nil && i.HostConfig != nil {
+		for _, dev := range i.HostConfig.Devices {
+			if nvidiaDeviceRe.MatchString(dev.PathOnHost) {
+				count++
+			}
+		}
+	}
+	if count == 0 && i.Config != nil {
+		for _, env := range i.Config.Env {
+			if !strings.HasPrefix(env, "NVIDIA_VISIBLE_DEVICES=") {
+				continue
+			}
+			switch v := strings.TrimPrefix(env, "NVIDIA_VISIBLE_DEVICES="); v {
+			case "", "all", "none", "void":
+			default:
+				count = int32(len(strings.Split(v, ",")))
+			}
+		}
+	}
+	if count == 0 {
+		return 0, ""
+	}
+	return count, gpuVendorNvidia
 }
 
 type dockerNetwork struct {
@@ -185,48 +610,312 @@ type Config struct {
 	// containers and cgroups. The actual raw metrics (e.g. MemRSS) will _not_
 	// be cached but will be re-calculated on all calls to AllContainers.
 	CacheDuration time.Duration
+	// CgroupRoot is where the host cgroup hierarchies are mounted when it
+	// isn't the standard /sys/fs/cgroup, e.g. /host/sys/fs/cgroup in the
+	// agent container. The mount points listed under /sys/fs/cgroup are
+	// re-pointed to it, they're re-pointed under HOST_SYS when it's empty.
+	CgroupRoot string
 	// CollectNetwork enables network stats collection. This requires at least
 	// one call to container.Inspect for new containers and reads from the
 	// procfs for stats.
 	CollectNetwork bool
+	// CollectNetworkPerInterface additionally breaks down the network stats
+	// by docker network in NetworkStat.PerInterface.
+	CollectNetworkPerInterface bool
 	// Whitelist is a slice of filter strings in the form of key:regex where key
-	// is either 'image' or 'name' and regex is a valid regular expression.
+	// is either 'image', 'name' or 'networkmode' and regex is a valid regular
+	// expression. The networkmode regexes match the whole mode, e.g.
+	// networkmode:host or networkmode:none. The network mode is only known
+	// once the container was inspected so these filters force an inspect of
+	// the containers which wouldn't be otherwise.
 	Whitelist []string
 	// Blacklist is the same as whitelist but for exclusion.
 	Blacklist []string
+	// WhitelistMode is how the whitelist is applied, either
+	// WhitelistModeOverride (the default) to only include blacklisted
+	// containers again or WhitelistModeStrict to exclude all the containers
+	// which don't match it.
+	WhitelistMode string
+	// NameNormalization, if set, is a regular expression whose matches in the
+	// container names are replaced with NameReplacement, e.g. `-[a-z0-9]+$`
+	// to collapse the random suffix of replicas into a stable name. It's
+	// applied after the filters which still match the raw names.
+	NameNormalization string
+	NameReplacement   string
+	// LabelsAsTags are the keys of the container labels reported as
+	// key:value tags in Container.Tags.
+	LabelsAsTags []string
+	// AnnotationLabels are the keys of the container labels with an integer
+	// value reported in Container.Annotations. Labels which aren't integers
+	// are dropped.
+	AnnotationLabels []string
+	// ExcludePauseContainer excludes the Kubernetes pod infra containers,
+	// whose image is a known pause image, regardless of the filters.
+	ExcludePauseContainer bool
+	// CollectHealthcheckConfig enables collection of the configured healthcheck
+	// command, interval and retries and of the failing streak. This requires
+	// a call to container.Inspect for new containers, when their health
+	// changes and while they are unhealthy.
+	CollectHealthcheckConfig bool
+	// CollectRestartCount enables collection of the number of times the
+	// container was restarted. This requires a call to container.Inspect for
+	// new containers and when their state changes.
+	CollectRestartCount bool
+	// CollectDiskStats enables collection of the size of the container's
+	// writable layer and root filesystem. Docker computes these on every list
+	// call so this is expensive on hosts with many containers.
+	CollectDiskStats bool
+	// NoProxy connects directly to the daemons reached over TCP, ignoring the
+	// HTTP_PROXY and ALL_PROXY of the environment, e.g. when the proxy can't
+	// reach the daemons and listing them all in NO_PROXY isn't practical.
+	NoProxy bool
+	// OperationTimeout is the maximum duration of a single Docker API call.
+	// Defaults to 10 seconds.
+	OperationTimeout time.Duration
+	// StatWorkers is the number of containers whose cgroup stats are read
+	// concurrently. Defaults to the number of CPUs.
+	StatWorkers int
+	// CollectionDeadline, if set, bounds the time spent collecting the
+	// containers. Once it's exceeded the stats of the remaining containers
+	// aren't read and only the containers collected so far are returned, e.g.
+	// on hosts with thousands of containers and slow cgroup reads.
+	CollectionDeadline time.Duration
+	// MaxInspectsPerCycle, if set, caps the number of containers inspected
+	// per collection. The others reuse their previous details, if any, and
+	// are inspected on the next collections, which bounds the load on the
+	// daemon when many containers start at once. With networkmode filters
+	// the containers not inspected yet are only returned once they are.
+	MaxInspectsPerCycle int
+	// InvalidationInterval is how often the cached data of removed containers
+	// is dropped. Defaults to 5 minutes.
+	InvalidationInterval time.Duration
+	// WatchEvents starts a goroutine listening to the Docker events to drop
+	// the cached data of containers as soon as they die rather than on the
+	// next cache invalidation. It is stopped with StopEventsWatcher.
+	WatchEvents bool
+	// BackgroundRefresh collects the containers from a goroutine every
+	// RefreshInterval so AllContainers returns the latest snapshot without
+	// blocking on the daemon. The stats are as old as the snapshot so the
+	// interval shouldn't exceed the one of the checks. Each Docker endpoint
+	// has its own goroutine, stopped by CloseDockerUtil or by closing the
+	// collector.
+	BackgroundRefresh bool
+	// RefreshInterval is how often the containers are collected with
+	// BackgroundRefresh. Defaults to 10 seconds.
+	RefreshInterval time.Duration
+	// IncludeStopped also lists the containers that aren't running (created,
+	// exited, dead). They are reported with their metadata and zeroed stats.
+	IncludeStopped bool
+	// MetadataOnly only collects the inventory of the containers (names,
+	// images, state...) from the Docker API, without reading their cgroups
+	// nor their network stats which are all reported as 0. This saves most of
+	// the /proc and /sys reads on hosts with many containers.
+	MetadataOnly bool
+	// MaxContainers caps the number of containers returned, keeping the ones
+	// with the most CPU usage. It's applied after the filters, 0 means no
+	// limit.
+	MaxContainers int
+	// MinUptime drops the running containers started less than MinUptime
+	// ago, e.g. to ignore short-lived CI jobs. It's applied after the stats
+	// collection, independently of the filters.
+	MinUptime time.Duration
+	// CreatedAfter and CreatedBefore only keep the containers created within
+	// the window, e.g. for forensics, the zero time leaves it unbounded. Unlike
+	// MinUptime they use the original creation time which restarts don't
+	// change. They're applied with the filters, before any inspect.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Statsd, if set, receives the internal metrics of the collection, e.g.
+	// the hits and misses of the containers cache.
+	Statsd StatsClient
+	// UseDockerStatsAPI reads the stats of the containers whose cgroup can't
+	// be found from the Docker stats API, e.g. when the host cgroups aren't
+	// mounted. This costs an API call per container and collection. It's
+	// implied on Windows.
+	UseDockerStatsAPI bool
+	// UseContainerdMetrics reads the stats of the containerd containers whose
+	// cgroup can't be found from the metrics of their task, e.g. when the
+	// cgroup paths don't contain the container IDs. This costs an API call
+	// per container and collection.
+	UseContainerdMetrics bool
 
 	// internal use only
 	filter *containerFilter
+	nameRe *regexp.Regexp
+}
+
+// CollectionError is returned by AllContainers when a step of the collection
+// fails. Kind is one of ErrListContainers, ErrPidList or ErrCgroupParse, to
+// check with IsCollectionError, and Err the underlying cause.
+type CollectionError struct {
+	Kind error
+	Err  error
+}
+
+func (e *CollectionError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Kind, e.Err)
+}
+
+// IsCollectionError returns true if err is a CollectionError of the given kind.
+func IsCollectionError(err error, kind error) bool {
+	cerr, ok := err.(*CollectionError)
+	return ok && cerr.Kind == kind
+}
+
+// isLocalCollectionError returns true if err comes from reading the processes
+// or cgroups of the host rather than from the container runtime.
+func isLocalCollectionError(err error) bool {
+	return IsCollectionError(err, ErrPidList) || IsCollectionError(err, ErrCgroupParse)
+}
+
+// collectDetails returns true if any of the enabled collections need the
+// containerDetails from container.Inspect.
+func (c *Config) collectDetails() bool {
+	return c.CollectHealthcheckConfig || c.CollectRestartCount || c.filter.needsNetworkMode()
+}
+
+// createdInWindow returns true if a container created at the given epoch is
+// within [CreatedAfter, CreatedBefore].
+func (c *Config) createdInWindow(created int64) bool {
+	t := time.Unix(created, 0)
+	if !c.CreatedAfter.IsZero() && t.Before(c.CreatedAfter) {
+		return false
+	}
+	if !c.CreatedBefore.IsZero() && t.After(c.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// parse pre-parses the filters and the name normalization used internally.
+func (c *Config) parse() error {
+	var err error
+	c.filter, err = newContainerFilter(c.Whitelist, c.Blacklist, c.WhitelistMode)
+	if err != nil {
+		return err
+	}
+	c.nameRe = nil
+	if c.NameNormalization != "" {
+		c.nameRe, err = regexp.Compile(c.NameNormalization)
+		if err != nil {
+			return fmt.Errorf("invalid name normalization %q: %s", c.NameNormalization, err)
+		}
+	}
+	return nil
+}
+
+// isExcluded returns true if the container is a pause container to exclude
+// or if it's excluded by the filters.
+func (c *Config) isExcluded(container *Container) bool {
+	if c.ExcludePauseContainer && isPauseImage(container.Image) {
+		return true
+	}
+	return c.filter.IsExcluded(container)
+}
+
+// normalizeName applies the name normalization, if any, to a container name.
+func (c *Config) normalizeName(name string) string {
+	if c.nameRe == nil {
+		return name
+	}
+	return c.nameRe.ReplaceAllString(name, c.NameReplacement)
+}
+
+// imageNameEntry is a cached image name resolution. Negative entries are
+// images we couldn't resolve to a user-friendly name.
+type imageNameEntry struct {
+	name       string
+	resolvedAt time.Time
+	negative   bool
+}
+
+// StatsClient is the subset of the statsd client used to report internal
+// metrics. It's satisfied by *statsd.Client from datadog-go.
+type StatsClient interface {
+	Count(name string, value int64, tags []string, rate float64) error
+	Gauge(name string, value float64, tags []string, rate float64) error
+}
+
+// gaugeSince reports the milliseconds elapsed since start as a gauge, if
+// statsd is set.
+func gaugeSince(statsd StatsClient, name string, start time.Time) {
+	if statsd == nil {
+		return
+	}
+	statsd.Gauge(name, float64(time.Since(start))/float64(time.Millisecond), []string{}, 1)
+}
+
+// dockerClient is the subset of the Docker API client used by dockerUtil so
+// a fake client can be used in tests.
+type dockerClient interface {
+	ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
+	ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error)
+	Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error)
+	Info(ctx context.Context) (types.Info, error)
+	ServerVersion(ctx context.Context) (types.Version, error)
+	ContainerStats(ctx context.Context, containerID string, stream bool) (types.ContainerStats, error)
+	Close() error
 }
 
 // dockerUtil wraps interactions with a local docker API.
 type dockerUtil struct {
 	cfg *Config
-	cli *client.Client
+	// cli is replaced on API version mismatches so it must be accessed with
+	// client()
+	cli     dockerClient
+	cliLock sync.RWMutex
+	// reconnect builds a new client with a freshly detected API version, nil
+	// if the version can't change (e.g. DOCKER_API_VERSION set by the user)
+	reconnect func() (dockerClient, error)
+	// tracks the last time we rebuilt the client
+	lastReconnect time.Time
+	// containerType is the Container.Type of the containers, e.g. "podman"
+	// for Podman's Docker-compatible API
+	containerType string
+	// endpoint is the Container.Endpoint of the containers, empty for the
+	// global dockerUtil. remote is true for the daemons reached over TCP
+	// whose containers don't run on this host.
+	endpoint string
+	remote   bool
 	// tracks the last time we invalidate our internal caches
 	lastInvalidate time.Time
+	// now returns the current time, overridden in tests
+	now func() time.Time
 	// networkMappings by container id
 	networkMappings map[string][]dockerNetwork
+	// init pid from container.Inspect by container id, used for the network
+	// stats when cgroups aren't available
+	initPids map[string]int
 	// image sha mapping cache
-	imageNameBySha map[string]string
+	imageNameBySha map[string]imageNameEntry
+	// inspect details by container id
+	detailsByID map[string]*containerDetails
+	// consecutive containers() failures and the time until which we skip
+	// calling the daemon because of them
+	failures     int
+	backoffUntil time.Time
+	// consecutive containers() failures reading the host
+	localFailures int
+	// last successful containers() result, returned while backing off
+	lastContainers []*Container
+	// stops the events watcher, nil if it's not running
+	stopEvents func()
+	// latest *containersSnapshot of the background refresh and the function
+	// stopping it, nil if it's not running
+	snapshot    atomic.Value
+	stopRefresh func()
 	sync.Mutex
 }
 
 //
 // Expose module-level functions that will interact with a Singleton dockerUtil.
 
-// AllContainers returns a slice of all running containers.
+// AllContainers returns a slice of all running containers of the collector
+// returned by GetCollector. Docker is used when it has been initialized,
+// otherwise we fall back to containerd.
 func AllContainers() ([]*Container, error) {
-	if globalDockerUtil != nil {
-		r, err := globalDockerUtil.containers()
-		if err != nil && err.Error() != lastErr {
-			log.Warnf("unable to collect docker stats: %s", err)
-			lastErr = err.Error()
-		} else {
-			return r, nil
-		}
-	}
-	return nil, nil
+	return CollectContainers(globalCollector)
 }
 
 // GetHostname returns the Docker hostname.
@@ -237,45 +926,129 @@ func GetHostname() (string, error) {
 	return globalDockerUtil.getHostname()
 }
 
+// GetContainer returns a single Docker container with its latest stats
+// without listing all the containers. The ID may be a short ID, as accepted
+// by Docker. ErrContainerNotFound is returned if the container is gone.
+func GetContainer(id string) (*Container, error) {
+	if globalDockerUtil == nil {
+		return nil, ErrDockerNotAvailable
+	}
+	return globalDockerUtil.container(id)
+}
+
 // IsContainerized returns True if we're running in the docker-dd-agent container.
 func IsContainerized() bool {
 	return os.Getenv("DOCKER_DD_AGENT") == "yes"
 }
 
-// connectToDocker connects to a local docker socket.
-// Returns ErrDockerNotAvailable if the socket or mounts file is missing
-// otherwise it returns either a valid client or an error.
-func connectToDocker() (*client.Client, error) {
-	// If we don't have a docker.sock then return a known error.
-	sockPath := util.GetEnv("DOCKER_SOCKET_PATH", "/var/run/docker.sock")
-	if !util.PathExists(sockPath) {
-		return nil, ErrDockerNotAvailable
+// dockerSocket is a local socket serving the Docker API.
+type dockerSocket struct {
+	path string
+	// containerType is the Container.Type of the containers of this daemon
+	containerType string
+}
+
+// dockerSockets returns the sockets to probe in order of preference. The
+// DOCKER_SOCKET_PATH takes priority over the default Docker socket and the
+// Docker-compatible Podman sockets, rootless Podman uses XDG_RUNTIME_DIR.
+func dockerSockets() []dockerSocket {
+	var sockets []dockerSocket
+	if path := os.Getenv("DOCKER_SOCKET_PATH"); path != "" {
+		sockets = append(sockets, dockerSocket{path, socketContainerType(path)})
 	}
-	// The /proc/mounts file won't be availble on non-Linux systems
-	// and we only support Linux for now.
-	mountsFile := "/proc/mounts"
-	if !util.PathExists(mountsFile) {
-		return nil, ErrDockerNotAvailable
+	sockets = append(sockets,
+		dockerSocket{"/var/run/docker.sock", dockerContainerType},
+		dockerSocket{"/run/podman/podman.sock", podmanContainerType},
+	)
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		sockets = append(sockets, dockerSocket{filepath.Join(dir, "podman", "podman.sock"), podmanContainerType})
 	}
+	return sockets
+}
 
-	serverVersion, err := detectServerAPIVersion()
-	if err != nil {
-		return nil, err
+// windowsDockerHost is the named pipe the Docker daemon listens on by
+// default on Windows.
+const windowsDockerHost = "npipe:////./pipe/docker_engine"
+
+// isWindows is true on Windows hosts. They have neither procfs nor cgroups so
+// the container stats are always read from the Docker stats API.
+var isWindows = runtime.GOOS == "windows"
+
+// namedPipePath returns the Windows path of a npipe:// Docker host, e.g.
+// \\.\pipe\docker_engine.
+func namedPipePath(host string) string {
+	return strings.Replace(strings.TrimPrefix(host, "npipe://"), "/", `\`, -1)
+}
+
+// socketContainerType guesses the daemon serving a socket from its path.
+func socketContainerType(path string) string {
+	if strings.Contains(path, "podman") {
+		return podmanContainerType
 	}
-	os.Setenv("DOCKER_API_VERSION", serverVersion)
+	return dockerContainerType
+}
 
-	// Connect again using the known server version.
-	cli, err := client.NewEnvClient()
-	if err != nil {
-		return nil, err
+// connectToDocker connects to a docker socket, either the one from
+// DOCKER_HOST or the first existing one in dockerSockets, or to the default
+// named pipe on Windows. It also returns
+// the Container.Type to use for the containers of this daemon.
+// Returns ErrDockerNotAvailable if the socket is missing otherwise it returns
+// either a valid client or an error. The mounts file is not required
+// here since it's only used for the cgroup stats, see CgroupsForPids.
+func connectToDocker(noProxy bool) (*client.Client, string, error) {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" && isWindows {
+		host = windowsDockerHost
+	}
+	containerType := dockerContainerType
+	switch {
+	case isRemoteDockerHost(host):
+		// Remote daemons reached over TCP don't need a local socket.
+	case strings.HasPrefix(host, "npipe://"):
+		if !util.PathExists(namedPipePath(host)) {
+			return nil, "", ErrDockerNotAvailable
+		}
+	case host != "":
+		path := strings.TrimPrefix(host, "unix://")
+		if !util.PathExists(path) {
+			return nil, "", ErrDockerNotAvailable
+		}
+		containerType = socketContainerType(path)
+	default:
+		// If we don't have a socket then return a known error.
+		found := false
+		for _, sock := range dockerSockets() {
+			if util.PathExists(sock.path) {
+				host, containerType, found = "unix://"+sock.path, sock.containerType, true
+				break
+			}
+		}
+		if !found {
+			return nil, "", ErrDockerNotAvailable
+		}
 	}
 
-	return cli, err
+	httpClient, err := dockerHTTPClient(host, noProxy)
+	if err != nil {
+		return nil, "", err
+	}
+	serverVersion, err := detectServerAPIVersion(host, httpClient)
+	if err != nil {
+		return nil, "", err
+	}
+	// Connect again using the known server version. It isn't exported in
+	// DOCKER_API_VERSION, which only holds the version pinned by the user, so
+	// a reconnect detects the version again.
+	cli, err := client.NewClient(host, serverVersion, httpClient, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	return cli, containerType, nil
 }
 
 // IsAvailable returns true if Docker is available on this machine via a socket.
 func IsAvailable() bool {
-	if _, err := connectToDocker(); err != nil {
+	if _, _, err := connectToDocker(false); err != nil {
 		if err != ErrDockerNotAvailable {
 			log.Warnf("unable to connect to docker: %s", err)
 		}
@@ -287,222 +1060,1219 @@ func IsAvailable() bool {
 // InitDockerUtil initializes the global dockerUtil singleton. This _must_ be
 // called before accessing any of the top-level docker calls.
 func InitDockerUtil(cfg *Config) error {
-	cli, err := connectToDocker()
+	// A version set by the user is kept even if the daemon doesn't support it.
+	pinnedVersion := os.Getenv("DOCKER_API_VERSION") != ""
+	cli, containerType, err := connectToDocker(cfg.NoProxy)
 	if err != nil {
 		return err
 	}
+	var reconnect func() (dockerClient, error)
+	if !pinnedVersion {
+		reconnect = func() (dockerClient, error) {
+			cli, _, err := connectToDocker(cfg.NoProxy)
+			if err != nil {
+				return nil, err
+			}
+			return cli, nil
+		}
+	}
+	return initDockerUtil(cfg, cli, containerType, reconnect)
+}
+
+// InitDockerUtilWithClient initializes the global dockerUtil singleton with
+// the given client instead of connecting to the local socket. This is mostly
+// useful to inject a fake client in tests.
+func InitDockerUtilWithClient(cfg *Config, cli dockerClient) error {
+	return initDockerUtil(cfg, cli, dockerContainerType, nil)
+}
 
-	// Pre-parse the filter and use that internally.
-	cfg.filter, err = newContainerFilter(cfg.Whitelist, cfg.Blacklist)
+func initDockerUtil(cfg *Config, cli dockerClient, containerType string, reconnect func() (dockerClient, error)) error {
+	d, err := newDockerUtil(cfg, cli)
 	if err != nil {
 		return err
 	}
+	d.containerType = containerType
+	d.reconnect = reconnect
+	setDebugLogInterval(cfg.InvalidationInterval)
+	if err := CloseDockerUtil(); err != nil {
+		log.Debugf("error closing the previous docker client: %s", err)
+	}
+	if cfg.WatchEvents {
+		d.startEventsWatcher()
+	}
+	if cfg.BackgroundRefresh {
+		d.startRefresher()
+	}
+	globalDockerUtil = d
+	globalCollector = d
+	return nil
+}
+
+// CloseDockerUtil releases the global dockerUtil singleton: it stops the
+// events watcher and the background refresh and closes the client so
+// InitDockerUtil can be called again.
+// It is a no-op if Docker wasn't initialized.
+func CloseDockerUtil() error {
+	d := globalDockerUtil
+	if d == nil {
+		return nil
+	}
+	globalDockerUtil = nil
+	if globalCollector == Collector(d) {
+		globalCollector = nil
+	}
+	return d.close()
+}
+
+// close stops the background refresh and the events watcher, if any, and
+// closes the client.
+func (d *dockerUtil) close() error {
+	d.stopRefresher()
+	d.stopEventsWatcher()
+	return d.client().Close()
+}
+
+// newDockerUtil creates a dockerUtil using the given client.
+func newDockerUtil(cfg *Config, cli dockerClient) (*dockerUtil, error) {
+	if err := cfg.parse(); err != nil {
+		return nil, err
+	}
+	if cfg.OperationTimeout <= 0 {
+		cfg.OperationTimeout = defaultOperationTimeout
+	}
+	if cfg.InvalidationInterval <= 0 {
+		cfg.InvalidationInterval = defaultInvalidationInterval
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = defaultRefreshInterval
+	}
 
-	globalDockerUtil = &dockerUtil{
+	return &dockerUtil{
 		cfg:             cfg,
 		cli:             cli,
 		networkMappings: make(map[string][]dockerNetwork),
-		imageNameBySha:  make(map[string]string),
+		initPids:        make(map[string]int),
+		imageNameBySha:  make(map[string]imageNameEntry),
+		detailsByID:     make(map[string]*containerDetails),
 		lastInvalidate:  time.Now(),
-	}
-	return nil
+		now:             time.Now,
+		containerType:   dockerContainerType,
+	}, nil
 }
 
 // dockerContainers returns a list of Docker info for active containers using the
 // Docker API. This requires the running user to be in the "docker" user group
 // or have access to /tmp/docker.sock.
 func (d *dockerUtil) dockerContainers() ([]*Container, error) {
-	containers, err := d.cli.ContainerList(context.Background(), types.ContainerListOptions{})
+	containers, err := d.listContainers()
+	if err != nil && apiVersionErrorRe.MatchString(err.Error()) && d.redetectAPIVersion() {
+		containers, err = d.listContainers()
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error listing containers: %s", err)
+		return nil, err
 	}
 	ret := make([]*Container, 0, len(containers))
+	inspects, skippedInspects := 0, 0
 	for _, c := range containers {
-		if d.cfg.CollectNetwork {
-			// FIXME: We might need to invalidate this cache if a containers networks are changed live.
-			d.Lock()
-			if _, ok := d.networkMappings[c.ID]; !ok {
-				i, err := d.cli.ContainerInspect(context.Background(), c.ID)
-				if err != nil && client.IsErrContainerNotFound(err) {
-					d.Unlock()
-					log.Debugf("error inspecting container %s: %s", c.ID, err)
-					continue
+		if !d.cfg.createdInWindow(c.Created) {
+			continue
+		}
+		// FIXME: We might need to invalidate these caches if a containers networks are changed live.
+		d.Lock()
+		_, hasNetwork := d.networkMappings[c.ID]
+		details, hasDetails := d.detailsByID[c.ID]
+		// Stopped containers have no network namespace to read the routes from.
+		// The network namespaces of remote containers aren't on this host.
+		needsNetwork := d.cfg.CollectNetwork && !d.cfg.MetadataOnly && !d.remote && !hasNetwork && !IsStopped(c.State)
+		// Restarts don't change the container id so we refresh the details
+		// on state changes to get the latest restart count. The failing
+		// streak keeps changing while the container is unhealthy.
+		health := parseContainerHealth(c.Status)
+		staleDetails := hasDetails &&
+			((d.cfg.CollectRestartCount && details.state != c.State) ||
+				(d.cfg.CollectHealthcheckConfig && (details.listHealth != health || details.health == "unhealthy")))
+		// The exit status is inspected again whenever a container stops or
+		// starts, so the details of a stopped container date from its last exit.
+		stoppedOrStarted := hasDetails && details.state != c.State && (IsStopped(c.State) || IsStopped(details.state))
+		needsExitStatus := d.cfg.IncludeStopped && ((IsStopped(c.State) && !hasDetails) || stoppedOrStarted)
+		needsDetails := (d.cfg.collectDetails() && (!hasDetails || staleDetails)) || needsExitStatus
+		if (needsNetwork || needsDetails) && d.cfg.MaxInspectsPerCycle > 0 && inspects >= d.cfg.MaxInspectsPerCycle {
+			// Stale details are kept until the next collection.
+			needsNetwork, needsDetails = false, false
+			skippedInspects++
+		}
+		if needsNetwork || needsDetails {
+			inspects++
+			ctx, cancel := d.timeoutContext()
+			i, err := d.client().ContainerInspect(ctx, c.ID)
+			cancel()
+			if err != nil {
+				d.Unlock()
+				if ctx.Err() == context.DeadlineExceeded {
+					return nil, ErrDockerTimeout
 				}
-				d.networkMappings[c.ID] = findDockerNetworks(c.ID, i.State.Pid, c.NetworkSettings)
+				log.Debugf("error inspecting container %s: %s", c.ID, err)
+				continue
 			}
-			d.Unlock()
+			if needsNetwork {
+				d.networkMappings[c.ID] = containerNetworks(c.ID, i, c.NetworkSettings)
+				d.initPids[c.ID] = i.State.Pid
+			}
+			// Always keep the details since they are cheap once inspected.
+			details = newContainerDetails(i)
+			details.state = c.State
+			details.listHealth = health
+			d.detailsByID[c.ID] = details
 		}
+		d.Unlock()
 
 		container := &Container{
-			Type:    "Docker",
+			Type:    d.containerType,
 			ID:      c.ID,
-			Name:    c.Names[0],
+			Name:    containerName(c.Names),
+			Names:   trimNames(c.Names),
+			rawName: rawContainerName(c.Names),
 			Image:   d.extractImageName(c.Image),
 			ImageID: c.ImageID,
 			Created: c.Created,
 			State:   c.State,
-			Health:  parseContainerHealth(c.Status),
+			Health:  health,
+
+			SizeRw:     c.SizeRw,
+			SizeRootFs: c.SizeRootFs,
+			Labels:     c.Labels,
+
+			ComposeProject: c.Labels[composeProjectLabel],
+			ComposeService: c.Labels[composeServiceLabel],
+			Command:        truncateCommand(c.Command),
+
+			IPAddresses: containerIPs(c.NetworkSettings),
+		}
+		container.ImageTag = imageTag(container.Image)
+		container.Tags = labelTags(container.Labels, d.cfg.LabelsAsTags)
+		container.Annotations = labelAnnotations(container.ID, container.Labels, d.cfg.AnnotationLabels)
+		container.Endpoint = d.endpoint
+		if details != nil {
+			if d.cfg.CollectHealthcheckConfig {
+				container.HealthcheckConfig = details.healthcheck
+				container.HealthFailingStreak = details.failingStreak
+			}
+			if d.cfg.CollectRestartCount {
+				container.RestartCount = details.restartCount
+			}
+			container.inspectStartedAt = details.startedAt
+			container.LogDriver = details.logDriver
+			container.LogPath = details.logPath
+			container.Privileged = details.privileged
+			container.GpuCount = details.gpuCount
+			container.GpuVendor = details.gpuVendor
+			container.Mounts = details.mounts
+			container.NetworkMode = details.networkMode
+			container.PidNamespaceOwner = details.pidNamespaceOwner
+			container.MemReservation = details.memReservation
+			container.CpuShares = details.cpuShares
+		}
+		setExitStatus(container, details)
+		if details == nil && d.cfg.filter.needsNetworkMode() {
+			// The network mode filters can't be applied until the deferred
+			// inspect, so the container is held back until then.
+			continue
 		}
-		if !d.cfg.filter.IsExcluded(container) {
+		if !d.cfg.isExcluded(container) {
+			container.Name = d.cfg.normalizeName(container.Name)
 			ret = append(ret, container)
 		}
 	}
 
-	if d.lastInvalidate.Add(invalidationInterval).After(time.Now()) {
+	if skippedInspects > 0 {
+		log.Debugf("inspected %d containers, deferred %d to the next collection", inspects, skippedInspects)
+		if d.cfg.Statsd != nil {
+			d.cfg.Statsd.Count("datadog.process.docker.inspects_skipped", int64(skippedInspects), []string{}, 1)
+		}
+	}
+
+	// Drop the data of the containers which are gone once per interval.
+	now := d.now()
+	d.Lock()
+	invalidate := now.Sub(d.lastInvalidate) >= d.cfg.InvalidationInterval
+	if invalidate {
+		d.lastInvalidate = now
+	}
+	d.Unlock()
+	if invalidate {
 		d.invalidateCaches(containers)
 	}
 
+	resolvePidNamespaceOwners(ret, containers)
 	return ret, nil
 }
 
 // containers gets a list of all containers on the current node using a mix of
 // the Docker APIs and cgroups stats. We attempt to limit syscalls where possible.
 func (d *dockerUtil) containers() ([]*Container, error) {
-	cacheKey := "dockerutil.containers"
+	d.Lock()
+	if d.now().Before(d.backoffUntil) {
+		containers, failures, until := d.lastContainers, d.failures, d.backoffUntil
+		d.Unlock()
+		log.Debugf("skipping docker collection after %d failures, retrying at %s", failures, until)
+		return containers, nil
+	}
+	d.Unlock()
 
-	// Get the containers either from our cache or with API queries.
-	var containers []*Container
-	cached, hit := cache.Get(cacheKey)
-	if hit {
-		var ok bool
-		containers, ok = cached.([]*Container)
-		if !ok {
-			log.Errorf("invalid cache format, forcing a cache miss")
-			hit = false
+	if d.cfg.Statsd != nil {
+		if _, hit := cache.Get(d.containersCacheKey()); hit {
+			d.cfg.Statsd.Count("datadog.process.docker.cache.hit", 1, []string{}, 1)
+		} else {
+			d.cfg.Statsd.Count("datadog.process.docker.cache.miss", 1, []string{}, 1)
 		}
+	}
+	var containers []*Container
+	var err error
+	if d.cfg.MetadataOnly {
+		containers, err = metadataContainers(d.containersCacheKey(), d.cfg.CacheDuration, d.dockerContainers)
 	} else {
-		pids, err := process.Pids()
-		if err != nil {
-			return nil, fmt.Errorf("could not get pids: %s", err)
-		}
+		containers, err = cgroupContainers(d.containersCacheKey(), d.cfg.CacheDuration, d.cfg.CgroupRoot, d.cfg.StatWorkers, d.cfg.CollectionDeadline, d.dockerContainers, d.networkStats, d.statsFallback(), d.cfg.Statsd)
+	}
 
-		cgByContainer, err := CgroupsForPids(pids)
-		if err != nil {
-			return nil, fmt.Errorf("could not get cgroups for pids: %s", err)
-		}
-		containers, err = d.dockerContainers()
-		if err != nil {
-			return nil, fmt.Errorf("could not get docker containers: %s", err)
+	d.Lock()
+	defer d.Unlock()
+	if err != nil && isLocalCollectionError(err) {
+		// The daemon isn't at fault so we don't back off and serve the last
+		// containers for a few collections, in case the host reads fail
+		// transiently. Past that the outage is returned rather than hidden.
+		d.localFailures++
+		if d.lastContainers != nil && d.localFailures <= maxLocalFailures {
+			log.Debugf("serving the last containers: %s", err)
+			return d.lastContainers, nil
 		}
-
-		for _, container := range containers {
-			cgroup, ok := cgByContainer[container.ID]
-			if !ok {
-				continue
-			}
-			container.cgroup = cgroup
-			container.CPULimit, err = cgroup.CPULimit()
-			if err != nil {
-				log.Debugf("cgroup cpu limit: %s", err)
-			}
-			container.MemLimit, err = cgroup.MemLimit()
-			if err != nil {
-				log.Debugf("cgroup cpu limit: %s", err)
-			}
+		return nil, err
+	}
+	d.localFailures = 0
+	if err != nil {
+		d.failures++
+		if d.failures >= backoffThreshold {
+			d.backoffUntil = d.now().Add(backoffInterval(d.failures))
 		}
-		cache.SetWithTTL(cacheKey, containers, d.cfg.CacheDuration)
+		return nil, err
 	}
+	d.failures = 0
+	d.backoffUntil = time.Time{}
+	containers = filterMinUptime(containers, d.cfg.MinUptime)
+	containers = capContainers(containers, d.lastContainers, d.cfg.MaxContainers, d.cfg.Statsd)
+	enrichContainers(containers)
+	d.lastContainers = containers
+	return containers, nil
+}
 
-	// Fill in the latest statistics from the cgroups
-	// Creating a new list of containers with copies so we don't lose
-	// the previous state for calculations (e.g. last cpu).
-	var err error
-	newContainers := make([]*Container, 0, len(containers))
-	for _, lastContainer := range containers {
-		container := &Container{}
-		*container = *lastContainer
-
-		cgroup := container.cgroup
-		if cgroup == nil {
-			log.Debugf("container id %s has an empty cgroup, skipping", container.ID)
-			continue
-		}
+// containersCacheKey returns the key caching the containers of the daemon.
+func (d *dockerUtil) containersCacheKey() string {
+	if d.endpoint == "" {
+		return containersCacheKey
+	}
+	return containersCacheKey + "." + d.endpoint
+}
 
-		container.Memory, err = cgroup.Mem()
-		if err != nil {
-			log.Debugf("cgroup memory: %s", err)
+// capContainers keeps the maxContainers most active containers, by CPU time
+// since the last collection or since they started for the new ones, in
+// their original order. The number of dropped containers is reported to
+// statsd, if not nil.
+func capContainers(containers, lastContainers []*Container, maxContainers int, statsd StatsClient) []*Container {
+	if maxContainers <= 0 || len(containers) <= maxContainers {
+		return containers
+	}
+	lastByID := make(map[string]*Container, len(lastContainers))
+	for _, c := range lastContainers {
+		lastByID[c.ID] = c
+	}
+	activity := make(map[*Container]uint64, len(containers))
+	for _, c := range containers {
+		if c.CPU == nil {
 			continue
 		}
-		container.CPU, err = cgroup.CPU()
-		if err != nil {
-			log.Debugf("cgroup cpu: %s", err)
-			continue
+		cpu := c.CPU.User + c.CPU.System
+		if last, ok := lastByID[c.ID]; ok && last.CPU != nil && last.CPU.User+last.CPU.System <= cpu {
+			cpu -= last.CPU.User + last.CPU.System
 		}
-		container.IO, err = cgroup.IO()
-		if err != nil {
-			log.Debugf("cgroup i/o: %s", err)
-			continue
+		activity[c] = cpu
+	}
+
+	byActivity := make([]*Container, len(containers))
+	copy(byActivity, containers)
+	sort.SliceStable(byActivity, func(i, j int) bool {
+		return activity[byActivity[i]] > activity[byActivity[j]]
+	})
+	kept := make(map[*Container]struct{}, maxContainers)
+	for _, c := range byActivity[:maxContainers] {
+		kept[c] = struct{}{}
+	}
+	ret := make([]*Container, 0, maxContainers)
+	for _, c := range containers {
+		if _, ok := kept[c]; ok {
+			ret = append(ret, c)
 		}
+	}
 
-		if d.cfg.CollectNetwork {
-			d.Lock()
-			networks, ok := d.networkMappings[cgroup.ContainerID]
-			d.Unlock()
-			if ok && len(cgroup.Pids) > 0 {
-				netStat, err := collectNetworkStats(cgroup.ContainerID, int(cgroup.Pids[0]), networks)
-				if err != nil {
-					log.Debugf("could not collect network stats for container %s: %s", container.ID, err)
-					continue
-				}
-				container.Network = netStat
+	dropped := len(containers) - maxContainers
+	log.Debugf("dropping %d containers above the limit of %d", dropped, maxContainers)
+	if statsd != nil {
+		statsd.Count("datadog.process.containers.truncated", int64(dropped), []string{}, 1)
+	}
+	return ret
+}
+
+// filterMinUptime drops the running containers whose uptime is below
+// minUptime, stopped containers are kept.
+func filterMinUptime(containers []*Container, minUptime time.Duration) []*Container {
+	if minUptime <= 0 {
+		return containers
+	}
+	ret := containers[:0]
+	for _, c := range containers {
+		if IsStopped(c.State) || time.Duration(c.Uptime)*time.Second >= minUptime {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// listContainers lists the containers from the daemon.
+func (d *dockerUtil) listContainers() ([]types.Container, error) {
+	ctx, cancel := d.timeoutContext()
+	defer cancel()
+	containers, err := d.client().ContainerList(ctx, types.ContainerListOptions{
+		All:  d.cfg.IncludeStopped,
+		Size: d.cfg.CollectDiskStats,
+	})
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, ErrDockerTimeout
+		}
+		return nil, &CollectionError{Kind: ErrListContainers, Err: err}
+	}
+	return containers, nil
+}
+
+// client returns the current Docker client.
+func (d *dockerUtil) client() dockerClient {
+	d.cliLock.RLock()
+	defer d.cliLock.RUnlock()
+	return d.cli
+}
+
+// redetectAPIVersion rebuilds the client with the API version of the daemon
+// after a version mismatch, at most once per invalidation interval. It
+// returns true if the client was rebuilt.
+func (d *dockerUtil) redetectAPIVersion() bool {
+	if d.reconnect == nil {
+		return false
+	}
+	now := d.now()
+	d.Lock()
+	if !d.lastReconnect.IsZero() && now.Sub(d.lastReconnect) < d.cfg.InvalidationInterval {
+		d.Unlock()
+		return false
+	}
+	d.lastReconnect = now
+	d.Unlock()
+
+	cli, err := d.reconnect()
+	if err != nil {
+		log.Warnf("unable to reconnect to docker after an API version mismatch: %s", err)
+		return false
+	}
+	d.cliLock.Lock()
+	old := d.cli
+	d.cli = cli
+	d.cliLock.Unlock()
+	// Closing only drops the idle connections, the calls in flight on the
+	// previous client complete.
+	if old != cli {
+		if err := old.Close(); err != nil {
+			log.Debugf("error closing the previous docker client: %s", err)
+		}
+	}
+	log.Infof("reconnected to docker after an API version mismatch")
+	return true
+}
+
+// backoffInterval returns how long to wait before calling the daemon again
+// after the given number of consecutive failures.
+func backoffInterval(failures int) time.Duration {
+	interval := backoffInitial
+	for i := backoffThreshold; i < failures && interval < backoffMax; i++ {
+		interval *= 2
+	}
+	if interval > backoffMax {
+		interval = backoffMax
+	}
+	return interval
+}
+
+// networkStats returns the network stats for a container using the network
+// mappings collected when the container was first listed.
+func (d *dockerUtil) networkStats(container *Container) (*NetworkStat, error) {
+	if !d.cfg.CollectNetwork {
+		return NullContainer.Network, nil
+	}
+	d.Lock()
+	networks, ok := d.networkMappings[container.ID]
+	pid := d.initPids[container.ID]
+	d.Unlock()
+	if cgroup := container.cgroup; cgroup != nil && len(cgroup.Pids) > 0 {
+		pid = int(cgroup.Pids[0])
+	}
+	if !ok || pid == 0 {
+		return container.Network, nil
+	}
+	// Containers sharing the host network, or the PID namespace of another
+	// container, have no network of their own.
+	if len(networks) == 0 {
+		return NullContainer.Network, nil
+	}
+	return collectNetworkStats(container.ID, pid, networks, d.cfg.CollectNetworkPerInterface)
+}
+
+// statsFallback returns the function reading the stats of the containers
+// without a cgroup, nil if disabled. It's always enabled on Windows and for
+// remote daemons.
+func (d *dockerUtil) statsFallback() func(*Container) error {
+	if !d.cfg.UseDockerStatsAPI && !isWindows && !d.remote {
+		return nil
+	}
+	return d.apiStats
+}
+
+// cgroupContainers merges the containers returned by a runtime-specific listing
+// function with the cgroup of their processes. The listing and the cgroup
+// lookup are cached under cacheKey for cacheDuration while the raw metrics are
+// read from the cgroups on every call, by up to workers containers at a time.
+// The cgroups are read under cgroupRoot, see Config.CgroupRoot. The network
+// function returns the network stats for a container since these depend on
+// the runtime. The fallback function, if not nil, fills the stats of the
+// running containers without a cgroup. The time spent parsing the cgroups
+// and reading the stats is reported to statsd, if not nil. If deadline isn't
+// 0 and is exceeded, the stats of the remaining containers aren't read and
+// these containers are dropped.
+func cgroupContainers(
+	cacheKey string,
+	cacheDuration time.Duration,
+	cgroupRoot string,
+	workers int,
+	deadline time.Duration,
+	list func() ([]*Container, error),
+	network func(*Container) (*NetworkStat, error),
+	fallback func(*Container) error,
+	statsd StatsClient,
+) ([]*Container, error) {
+	var deadlineAt time.Time
+	if deadline > 0 {
+		deadlineAt = deadlineNow().Add(deadline)
+	}
+
+	// Get the containers either from our cache or with API queries.
+	var containers []*Container
+	cached, hit := cache.Get(cacheKey)
+	if hit {
+		var ok bool
+		containers, ok = cached.([]*Container)
+		if !ok {
+			log.Errorf("invalid cache format, forcing a cache miss")
+			hit = false
+		}
+	} else {
+		pids, err := process.Pids()
+		if err != nil {
+			return nil, &CollectionError{Kind: ErrPidList, Err: err}
+		}
+
+		start := time.Now()
+		cgByContainer, err := CgroupsForPids(cgroupRoot, pids)
+		if err != nil {
+			return nil, &CollectionError{Kind: ErrCgroupParse, Err: err}
+		}
+		gaugeSince(statsd, "datadog.process.docker.cgroup_parse_ms", start)
+		cachePidContainers(pids, cgByContainer, cacheDuration)
+		// Return the error as-is so callers can check for sentinels like ErrDockerTimeout.
+		containers, err = list()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, container := range containers {
+			cgroup, ok := cgByContainer[container.ID]
+			if !ok {
+				continue
 			}
-		} else {
-			container.Network = NullContainer.Network
+			container.cgroup = cgroup
+			container.CgroupPath = cgroup.Path()
+			setCgroupLimits(container, cgroup, statsd)
+		}
+		pruneLimitFailures(containers)
+		cache.SetWithTTL(cacheKey, containers, cacheDuration)
+	}
+
+	// Fill in the latest statistics from the cgroups
+	// Creating a new list of containers with copies so we don't lose
+	// the previous state for calculations (e.g. last cpu).
+	hasCgroups := cgroupsAvailable()
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	start := time.Now()
+	stats := make([]*Container, len(containers))
+	var skipped int32
+	parallelize(len(containers), workers, func(i int) {
+		if !deadlineAt.IsZero() && deadlineNow().After(deadlineAt) {
+			atomic.AddInt32(&skipped, 1)
+			return
+		}
+		stats[i] = containerStats(containers[i], hasCgroups, network, fallback)
+	})
+	gaugeSince(statsd, "datadog.process.docker.stat_collect_ms", start)
+	if skipped > 0 {
+		log.Warnf("collection deadline of %s exceeded, skipped %d of %d containers", deadline, skipped, len(containers))
+		if statsd != nil {
+			statsd.Count("datadog.process.containers.deadline_hit", 1, []string{}, 1)
+		}
+	}
+	newContainers := make([]*Container, 0, len(containers))
+	for _, container := range stats {
+		if container != nil {
+			newContainers = append(newContainers, container)
+		}
+	}
+	return newContainers, nil
+}
+
+// deadlineNow is the clock of the collection deadline of cgroupContainers,
+// tests override it to get deterministic deadlines.
+var deadlineNow = time.Now
+
+// metadataContainers returns the containers listed by list, from the cache if
+// possible, with zeroed stats. Unlike cgroupContainers it doesn't read the
+// cgroups of the processes nor the stats of the containers.
+func metadataContainers(cacheKey string, cacheDuration time.Duration, list func() ([]*Container, error)) ([]*Container, error) {
+	var containers []*Container
+	cached, hit := cache.Get(cacheKey)
+	if hit {
+		var ok bool
+		containers, ok = cached.([]*Container)
+		if !ok {
+			log.Errorf("invalid cache format, forcing a cache miss")
+			hit = false
+		}
+	}
+	if !hit {
+		var err error
+		containers, err = list()
+		if err != nil {
+			return nil, err
+		}
+		cache.SetWithTTL(cacheKey, containers, cacheDuration)
+	}
+
+	// Copy the containers so the cached ones aren't modified.
+	now := time.Now().Unix()
+	ret := make([]*Container, 0, len(containers))
+	for _, c := range containers {
+		container := &Container{}
+		*container = *c
+		container.Memory = NullContainer.Memory
+		container.CPU = NullContainer.CPU
+		container.IO = NullContainer.IO
+		container.Network = NullContainer.Network
+		if !IsStopped(container.State) {
+			container.Uptime = containerUptime(now, container.inspectStartedAt, 0, container.Created)
+		}
+		ret = append(ret, container)
+	}
+	return ret, nil
+}
+
+// parallelize calls f for every index in [0, n) from at most workers
+// goroutines and waits for all the calls to return.
+func parallelize(n, workers int, f func(int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// limitFailuresWarnThreshold is the number of consecutive failures to read
+// the limits of a container after which a warning is logged.
+const limitFailuresWarnThreshold = 3
+
+// limitFailures counts the consecutive failures to read the cpu or memory
+// limit of the containers by ID. Persistent failures likely mean a cgroup
+// layout we don't understand.
+var limitFailures = struct {
+	sync.Mutex
+	byID map[string]int
+}{byID: make(map[string]int)}
+
+// recordLimitFailure updates the failure streak of a container after reading
+// its limits, warning once the streak reaches limitFailuresWarnThreshold.
+// Failed reads are counted to statsd, if not nil.
+func recordLimitFailure(id string, failed bool, statsd StatsClient) {
+	limitFailures.Lock()
+	defer limitFailures.Unlock()
+	if !failed {
+		delete(limitFailures.byID, id)
+		return
+	}
+	limitFailures.byID[id]++
+	if limitFailures.byID[id] == limitFailuresWarnThreshold {
+		log.Warnf("unable to read the cgroup limits of container %s %d times in a row", id, limitFailuresWarnThreshold)
+	}
+	if statsd != nil {
+		statsd.Count("datadog.process.docker.cgroup_limit_errors", 1, []string{}, 1)
+	}
+}
+
+// pruneLimitFailures forgets the failure streaks of the containers which
+// aren't listed anymore.
+func pruneLimitFailures(containers []*Container) {
+	ids := make(map[string]struct{}, len(containers))
+	for _, c := range containers {
+		ids[c.ID] = struct{}{}
+	}
+	limitFailures.Lock()
+	defer limitFailures.Unlock()
+	for id := range limitFailures.byID {
+		if _, ok := ids[id]; !ok {
+			delete(limitFailures.byID, id)
 		}
+	}
+}
 
-		startedAt, err := cgroup.ContainerStartTime()
+// setCgroupLimits sets the limits of a container from its cgroup. Failures to
+// read the cpu or memory limit are tracked with recordLimitFailure.
+func setCgroupLimits(container *Container, cgroup *ContainerCgroup, statsd StatsClient) {
+	var err error
+	failed := false
+	container.CPULimit, err = cgroup.CPULimit()
+	if err != nil {
+		limitedDebugf("cgroup cpu limit: %s", err)
+		failed = true
+	}
+	container.MemLimit, err = cgroup.MemLimit()
+	if err != nil {
+		limitedDebugf("cgroup mem limit: %s", err)
+		failed = true
+	}
+	recordLimitFailure(container.ID, failed, statsd)
+	container.MemSoftLimit, err = cgroup.MemSoftLimit()
+	if err != nil {
+		limitedDebugf("cgroup memory soft limit: %s", err)
+	}
+	container.MemLow, err = cgroup.MemLow()
+	if err != nil {
+		limitedDebugf("cgroup memory low: %s", err)
+	}
+	container.MemMin, err = cgroup.MemMin()
+	if err != nil {
+		limitedDebugf("cgroup memory min: %s", err)
+	}
+	container.PidsLimit, err = cgroup.PidsLimit()
+	if err != nil {
+		limitedDebugf("cgroup pids limit: %s", err)
+	}
+	container.CpusetCount, err = cgroup.CpusetCount()
+	if err != nil {
+		limitedDebugf("cgroup cpuset: %s", err)
+	}
+	// The shares from container.Inspect are exact while the cgroup v2
+	// weight loses precision when converted.
+	if container.CpuShares == 0 {
+		shares, err := cgroup.CPUShares()
 		if err != nil {
-			log.Debugf("failed to get container start time: %s", err)
+			limitedDebugf("cgroup cpu shares: %s", err)
+		}
+		container.CpuShares = int64(shares)
+	}
+}
+
+// containerStats returns a copy of the container with the latest statistics
+// read from its cgroup, or nil if the container should be skipped because its
+// cgroup is missing.
+func containerStats(
+	lastContainer *Container,
+	hasCgroups bool,
+	network func(*Container) (*NetworkStat, error),
+	fallback func(*Container) error,
+) *Container {
+	var err error
+	container := &Container{}
+	*container = *lastContainer
+
+	cgroup := container.cgroup
+	if cgroup == nil && fallback != nil && !IsStopped(container.State) {
+		if err = fallback(container); err == nil {
+			return container
+		}
+		limitedDebugf("could not collect stats for container %s: %s", container.ID, err)
+	}
+	if cgroup == nil && !hasCgroups {
+		// Without cgroups we can still report the metadata and the network.
+		container.Memory = NullContainer.Memory
+		container.CPU = NullContainer.CPU
+		container.IO = NullContainer.IO
+		container.Network, err = network(container)
+		if err != nil {
+			limitedDebugf("could not collect network stats for container %s: %s", container.ID, err)
+			container.Network = NullContainer.Network
+		}
+		container.Uptime = containerUptime(time.Now().Unix(), container.inspectStartedAt, 0, container.Created)
+		return container
+	}
+	if cgroup == nil && IsStopped(container.State) {
+		// Stopped containers have no cgroup but we still report them.
+		container.Memory = NullContainer.Memory
+		container.CPU = NullContainer.CPU
+		container.IO = NullContainer.IO
+		container.Network = NullContainer.Network
+		return container
+	}
+	if cgroup == nil {
+		limitedDebugf("container id %s has an empty cgroup, skipping", container.ID)
+		return nil
+	}
+
+	// Reads can fail transiently during cgroup churn so we report partial
+	// stats rather than dropping the container.
+	container.Memory, err = cgroup.Mem()
+	if err != nil {
+		limitedDebugf("cgroup memory: %s", err)
+		container.Memory = NullContainer.Memory
+		container.StatErrors++
+	}
+	container.CPU, err = cgroup.CPU()
+	if err != nil {
+		limitedDebugf("cgroup cpu: %s", err)
+		container.CPU = NullContainer.CPU
+		container.StatErrors++
+	}
+	container.IO, err = cgroup.IO()
+	if err != nil {
+		limitedDebugf("cgroup i/o: %s", err)
+		container.IO = NullContainer.IO
+		container.StatErrors++
+	}
+
+	container.Network, err = network(container)
+	if err != nil {
+		limitedDebugf("could not collect network stats for container %s: %s", container.ID, err)
+		container.Network = NullContainer.Network
+		container.StatErrors++
+	}
+
+	container.StartedAt, err = cgroup.ContainerStartTime()
+	if err != nil {
+		limitedDebugf("failed to get container start time: %s", err)
+		container.StatErrors++
+	}
+	container.Uptime = containerUptime(time.Now().Unix(), container.inspectStartedAt, container.StartedAt, container.Created)
+	container.Pids = cgroup.Pids
+
+	// Fall back to the processes found in the cgroup without the pids
+	// controller.
+	var ok bool
+	container.PidsCurrent, ok, err = cgroup.PidsCurrent()
+	if err != nil {
+		limitedDebugf("cgroup pids: %s", err)
+	}
+	if !ok {
+		container.PidsCurrent = uint64(len(cgroup.Pids))
+	}
+	return container
+}
+
+// IsStopped returns true for the container states without any process, which
+// are only listed with Config.IncludeStopped.
+func IsStopped(state string) bool {
+	switch state {
+	case "created", "exited", "dead":
+		return true
+	}
+	return false
+}
+
+// unknownExitCode is the ExitCode of the running containers.
+const unknownExitCode = -1
+
+// setExitStatus sets the exit status of a stopped container from its details
+// if they were inspected in the same state, after it stopped. The exit code
+// is unknown otherwise.
+func setExitStatus(container *Container, details *containerDetails) {
+	if details == nil || !IsStopped(container.State) || details.state != container.State {
+		container.ExitCode = unknownExitCode
+		container.OOMKilled = false
+		return
+	}
+	container.ExitCode = details.exitCode
+	container.OOMKilled = details.oomKilled
+}
+
+// containerUptime returns the uptime in seconds of a container. The inspect
+// start time is preferred unless the cgroup was created after it, which means
+// the container restarted since it was inspected. The creation time is only
+// used as a last resort since it isn't reset by restarts.
+func containerUptime(now, inspectStartedAt, cgroupStartedAt, created int64) int64 {
+	start := inspectStartedAt
+	if cgroupStartedAt > start {
+		start = cgroupStartedAt
+	}
+	if start == 0 {
+		start = created
+	}
+	if start == 0 || start > now {
+		return 0
+	}
+	return now - start
+}
+
+// containerName returns the real name of a container out of the names
+// returned by the Docker API. Containers linked with --link also get an
+// alias for each link (e.g. "/web/db") so we prefer the shortest top-level
+// name and strip the leading "/".
+func containerName(names []string) string {
+	var name string
+	for _, n := range names {
+		n = strings.TrimPrefix(n, "/")
+		if strings.Contains(n, "/") {
 			continue
 		}
-		container.StartedAt = startedAt
-		container.Pids = cgroup.Pids
+		if name == "" || len(n) < len(name) {
+			name = n
+		}
+	}
+	if name == "" && len(names) > 0 {
+		// Only aliases, fall back to the first name.
+		return strings.TrimPrefix(names[0], "/")
+	}
+	return name
+}
 
-		newContainers = append(newContainers, container)
+// rawContainerName returns the name the containers used to be reported with,
+// the first one returned by the Docker API with its leading "/".
+func rawContainerName(names []string) string {
+	if len(names) == 0 {
+		return ""
 	}
-	return newContainers, nil
+	return names[0]
+}
+
+// filterName returns the name the name filters match, the raw name if known
+// so patterns like "^/redis$" keep matching now that Name is stripped.
+func (c *Container) filterName() string {
+	if c.rawName != "" {
+		return c.rawName
+	}
+	return c.Name
+}
+
+// trimNames strips the leading "/" from all container names.
+func trimNames(names []string) []string {
+	trimmed := make([]string, 0, len(names))
+	for _, n := range names {
+		trimmed = append(trimmed, strings.TrimPrefix(n, "/"))
+	}
+	return trimmed
+}
+
+// resolveID resolves a possibly short container ID against the containers
+// known from the previous collections.
+func (d *dockerUtil) resolveID(id string) (string, error) {
+	d.Lock()
+	ids := make(map[string]struct{}, len(d.lastContainers)+len(d.detailsByID))
+	for _, c := range d.lastContainers {
+		ids[c.ID] = struct{}{}
+	}
+	for cid := range d.detailsByID {
+		ids[cid] = struct{}{}
+	}
+	d.Unlock()
+	return ResolveContainerID(id, ids)
+}
+
+// ResolveContainerID returns the ID in ids which is id or starts with it,
+// Docker accepting short IDs interchangeably with full ones. id is returned
+// as-is if it matches none of them and ErrAmbiguousContainerID if it's the
+// prefix of several.
+func ResolveContainerID(id string, ids map[string]struct{}) (string, error) {
+	if _, ok := ids[id]; ok || id == "" {
+		return id, nil
+	}
+	var match string
+	for full := range ids {
+		if !strings.HasPrefix(full, id) {
+			continue
+		}
+		if match != "" {
+			return "", ErrAmbiguousContainerID
+		}
+		match = full
+	}
+	if match == "" {
+		return id, nil
+	}
+	return match, nil
+}
+
+// container inspects a single container and reads its stats from the cgroup
+// of its init process.
+func (d *dockerUtil) container(id string) (*Container, error) {
+	id, err := d.resolveID(id)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := d.timeoutContext()
+	i, err := d.client().ContainerInspect(ctx, id)
+	cancel()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, ErrDockerTimeout
+		}
+		if client.IsErrNotFound(err) {
+			return nil, ErrContainerNotFound
+		}
+		return nil, fmt.Errorf("error inspecting container %s: %s", id, err)
+	}
+	if i.ContainerJSONBase == nil || i.State == nil {
+		return nil, fmt.Errorf("invalid inspect response for container %s", id)
+	}
+	// The daemon resolves the short IDs of the containers we don't know yet,
+	// our caches are keyed by full ID.
+	id = i.ID
+
+	details := newContainerDetails(i)
+	container := &Container{
+		Type:             d.containerType,
+		ID:               i.ID,
+		Name:             strings.TrimPrefix(i.Name, "/"),
+		rawName:          i.Name,
+		Names:            []string{strings.TrimPrefix(i.Name, "/")},
+		Image:            d.extractImageName(i.Image),
+		ImageID:          i.Image,
+		State:            i.State.Status,
+		Command:          truncateCommand(strings.TrimSpace(i.Path + " " + strings.Join(i.Args, " "))),
+		inspectStartedAt: details.startedAt,
+	}
+	container.ImageTag = imageTag(container.Image)
+	container.LogDriver = details.logDriver
+	container.LogPath = details.logPath
+	container.Privileged = details.privileged
+	container.GpuCount = details.gpuCount
+	container.GpuVendor = details.gpuVendor
+	container.Mounts = details.mounts
+	container.NetworkMode = details.networkMode
+	container.PidNamespaceOwner = details.pidNamespaceOwner
+	container.MemReservation = details.memReservation
+	container.CpuShares = details.cpuShares
+	details.state = container.State
+	setExitStatus(container, details)
+	container.Endpoint = d.endpoint
+	container.Name = d.cfg.normalizeName(container.Name)
+	if t, err := time.Parse(time.RFC3339Nano, i.Created); err == nil {
+		container.Created = t.Unix()
+	}
+	if i.State.Health != nil {
+		container.Health = i.State.Health.Status
+	}
+	if i.Config != nil {
+		container.Labels = i.Config.Labels
+		container.ComposeProject = i.Config.Labels[composeProjectLabel]
+		container.ComposeService = i.Config.Labels[composeServiceLabel]
+		container.Tags = labelTags(i.Config.Labels, d.cfg.LabelsAsTags)
+		container.Annotations = labelAnnotations(id, i.Config.Labels, d.cfg.AnnotationLabels)
+	}
+	if d.cfg.CollectHealthcheckConfig {
+		container.HealthcheckConfig = details.healthcheck
+		container.HealthFailingStreak = details.failingStreak
+	}
+	if d.cfg.CollectRestartCount {
+		container.RestartCount = details.restartCount
+	}
+
+	var netSettings *types.SummaryNetworkSettings
+	if i.NetworkSettings != nil {
+		netSettings = &types.SummaryNetworkSettings{Networks: i.NetworkSettings.Networks}
+	}
+	container.IPAddresses = containerIPs(netSettings)
+
+	// The processes of remote containers aren't on this host.
+	if d.cfg.CollectNetwork && !d.remote && i.State.Pid > 0 {
+		d.Lock()
+		if _, ok := d.networkMappings[id]; !ok {
+			d.networkMappings[id] = containerNetworks(id, i, netSettings)
+			d.initPids[id] = i.State.Pid
+		}
+		d.Unlock()
+	}
+
+	if !d.remote && i.State.Pid > 0 {
+		// Skip the shared cache which holds the cgroups of all the processes.
+		cgs, err := CgroupsForPids(d.cfg.CgroupRoot, []int32{int32(i.State.Pid)})
+		if err != nil {
+			return nil, fmt.Errorf("could not get cgroups for container %s: %s", id, err)
+		}
+		if cgroup, ok := cgs[i.ID]; ok {
+			container.cgroup = cgroup
+			container.CgroupPath = cgroup.Path()
+			setCgroupLimits(container, cgroup, d.cfg.Statsd)
+		}
+	}
+	container = containerStats(container, cgroupsAvailable(), d.networkStats, d.statsFallback())
+	if container == nil {
+		return nil, fmt.Errorf("no cgroup found for container %s", id)
+	}
+	return container, nil
 }
 
 func (d *dockerUtil) getHostname() (string, error) {
-	info, err := d.cli.Info(context.Background())
+	ctx, cancel := d.timeoutContext()
+	defer cancel()
+	info, err := d.client().Info(ctx)
 	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", ErrDockerTimeout
+		}
 		return "", fmt.Errorf("unable to get Docker info: %s", err)
 	}
 	return info.Name, nil
 }
 
+// timeoutContext returns a context that expires after the configured
+// OperationTimeout. The cancel function must always be called.
+func (d *dockerUtil) timeoutContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), d.cfg.OperationTimeout)
+}
+
+// labelTags returns the labels with the given keys as key:value tags, in the
+// order of the keys. Missing labels are skipped.
+func labelTags(labels map[string]string, keys []string) []string {
+	var tags []string
+	for _, k := range keys {
+		if v, ok := labels[k]; ok {
+			tags = append(tags, k+":"+v)
+		}
+	}
+	return tags
+}
+
+// labelAnnotations returns the labels with the given keys parsed as integers.
+// Missing labels are skipped and the ones which aren't integers are dropped.
+// It is nil if none is found.
+func labelAnnotations(containerID string, labels map[string]string, keys []string) map[string]int {
+	var annotations map[string]int
+	for _, k := range keys {
+		v, ok := labels[k]
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			log.Debugf("dropping label %s=%q of container %s, not an integer", k, v, containerID)
+			continue
+		}
+		if annotations == nil {
+			annotations = make(map[string]int)
+		}
+		annotations[k] = n
+	}
+	return annotations
+}
+
+// imageTag returns the tag of an image name like host:5000/repo:tag, empty if
+// it has none. A colon before the last slash separates the registry port.
+func imageTag(name string) string {
+	if strings.HasPrefix(name, "sha256:") {
+		return ""
+	}
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		name = name[:i]
+	}
+	i := strings.LastIndexByte(name, ':')
+	if i < 0 || strings.LastIndexByte(name, '/') > i {
+		return ""
+	}
+	return name[i+1:]
+}
+
 // extractImageName will resolve sha image name to their user-friendly name.
-// For non-sha names we will just return the name as-is.
+// Digest references, e.g. quay.io/foo/bar@sha256:hash, are resolved to a tag
+// if possible and to their repository otherwise. For other names we will just
+// return the name as-is.
 func (d *dockerUtil) extractImageName(image string) string {
-	if !strings.HasPrefix(image, "sha256:") {
+	// fallback is the name used when no tag can be resolved.
+	fallback := image
+	if i := strings.Index(image, "@sha256:"); i >= 0 {
+		fallback = image[:i]
+	} else if !strings.HasPrefix(image, "sha256:") {
 		return image
 	}
 
 	d.Lock()
 	defer d.Unlock()
-	if _, ok := d.imageNameBySha[image]; !ok {
-		r, _, err := d.cli.ImageInspectWithRaw(context.Background(), image)
-		if err != nil {
-			// Only log errors that aren't "not found" because some images may
-			// just not be available in docker inspect.
-			if !client.IsErrNotFound(err) {
-				log.Errorf("could not extract image %s name: %s", image, err)
-			}
-			d.imageNameBySha[image] = image
+	if entry, ok := d.imageNameBySha[image]; ok {
+		// Negative lookups are retried after a while since the image may have
+		// been pulled or tagged in the meantime.
+		if !entry.negative || time.Since(entry.resolvedAt) < negativeImageTTL {
+			return entry.name
 		}
+	}
 
-		// Try RepoTags first and fall back to RepoDigest otherwise.
-		if len(r.RepoTags) > 0 {
-			d.imageNameBySha[image] = r.RepoTags[0]
-		} else if len(r.RepoDigests) > 0 {
-			// Digests formatted like quay.io/foo/bar@sha256:hash
-			sp := strings.SplitN(r.RepoDigests[0], "@", 2)
-			d.imageNameBySha[image] = sp[0]
-		} else {
-			d.imageNameBySha[image] = image
+	ctx, cancel := d.timeoutContext()
+	r, _, err := d.client().ImageInspectWithRaw(ctx, image)
+	cancel()
+	if err != nil {
+		// Don't cache the sha on timeouts so we retry on the next call.
+		if ctx.Err() == context.DeadlineExceeded {
+			log.Debugf("timed out extracting image %s name", image)
+			return fallback
+		}
+		// Only log errors that aren't "not found" because some images may
+		// just not be available in docker inspect.
+		if !client.IsErrNotFound(err) {
+			log.Errorf("could not extract image %s name: %s", image, err)
 		}
+		d.imageNameBySha[image] = imageNameEntry{name: fallback, resolvedAt: time.Now(), negative: true}
+		return fallback
 	}
-	return d.imageNameBySha[image]
+
+	// Try RepoTags first and fall back to RepoDigest otherwise.
+	name := fallback
+	if len(r.RepoTags) > 0 {
+		name = r.RepoTags[0]
+	} else if len(r.RepoDigests) > 0 {
+		// Digests formatted like quay.io/foo/bar@sha256:hash
+		sp := strings.SplitN(r.RepoDigests[0], "@", 2)
+		name = sp[0]
+	}
+	d.imageNameBySha[image] = imageNameEntry{name: name, resolvedAt: time.Now(), negative: name == fallback}
+	return name
 }
 
 func (d *dockerUtil) invalidateCaches(containers []types.Container) {
@@ -516,6 +2286,7 @@ func (d *dockerUtil) invalidateCaches(containers []types.Container) {
 	for cid := range d.networkMappings {
 		if _, ok := liveContainers[cid]; !ok {
 			delete(d.networkMappings, cid)
+			delete(d.initPids, cid)
 		}
 	}
 	for image := range d.imageNameBySha {
@@ -523,18 +2294,60 @@ func (d *dockerUtil) invalidateCaches(containers []types.Container) {
 			delete(d.imageNameBySha, image)
 		}
 	}
+	for cid := range d.detailsByID {
+		if _, ok := liveContainers[cid]; !ok {
+			delete(d.detailsByID, cid)
+		}
+	}
 	d.Unlock()
 }
 
-func detectServerAPIVersion() (string, error) {
+// isRemoteDockerHost returns true if host is a TCP endpoint.
+func isRemoteDockerHost(host string) bool {
+	return strings.HasPrefix(host, "tcp://")
+}
+
+// dockerDialTimeout is the connection timeout of the direct transport, the
+// same as the default one of the Docker client.
+const dockerDialTimeout = 32 * time.Second
+
+// dockerHTTPClient returns an HTTP client using the TLS certificates from
+// DOCKER_CERT_PATH, in the same way as client.NewEnvClient. It returns nil,
+// i.e. the default client which honors the proxy environment variables, if no
+// certificates are configured and noProxy isn't set for a TCP host.
+func dockerHTTPClient(host string, noProxy bool) (*http.Client, error) {
+	certPath := os.Getenv("DOCKER_CERT_PATH")
+	if certPath == "" {
+		if noProxy && isRemoteDockerHost(host) {
+			return &http.Client{Transport: directTransport()}, nil
+		}
+		return nil, nil
+	}
+	tlsc, err := tlsconfig.Client(tlsconfig.Options{
+		CAFile:             filepath.Join(certPath, "ca.pem"),
+		CertFile:           filepath.Join(certPath, "cert.pem"),
+		KeyFile:            filepath.Join(certPath, "key.pem"),
+		InsecureSkipVerify: os.Getenv("DOCKER_TLS_VERIFY") == "",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error loading docker TLS config from %s: %s", certPath, err)
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsc}}, nil
+}
+
+// directTransport returns a transport connecting to the daemon without any
+// proxy.
+func directTransport() *http.Transport {
+	return &http.Transport{
+		Dial: (&net.Dialer{Timeout: dockerDialTimeout}).Dial,
+	}
+}
+
+func detectServerAPIVersion(host string, httpClient *http.Client) (string, error) {
 	if os.Getenv("DOCKER_API_VERSION") != "" {
 		return os.Getenv("DOCKER_API_VERSION"), nil
 	}
-	host := os.Getenv("DOCKER_HOST")
-	if host == "" {
-		host = client.DefaultDockerHost
-	}
-	cli, err := client.NewClient(host, "", nil, nil)
+	cli, err := client.NewClient(host, "", httpClient, nil)
 	if err != nil {
 		return "", err
 	}
@@ -549,6 +2362,56 @@ func detectServerAPIVersion() (string, error) {
 
 var hostNetwork = dockerNetwork{"eth0", "bridge"}
 
+// containerNetworks returns the network mapping of an inspected container.
+// Containers joining the PID namespace of another one with
+// --pid=container:<id> are sidecars which usually share its network too,
+// e.g. the containers of a pod, so they get an empty mapping and their
+// network stats are left to the container owning the namespace rather than
+// counted twice.
+func containerNetworks(containerID string, i types.ContainerJSON, netSettings *types.SummaryNetworkSettings) []dockerNetwork {
+	if owner := sharedPidNamespace(i); owner != "" {
+		log.Debugf("Container %s shares the PID namespace of %s, skipping its network metrics", containerID, owner)
+		return []dockerNetwork{}
+	}
+	return findDockerNetworks(containerID, i.State.Pid, netSettings)
+}
+
+// sharedPidNamespace returns the container whose PID namespace the inspected
+// container joined, empty if it has its own.
+func sharedPidNamespace(i types.ContainerJSON) string {
+	if i.ContainerJSONBase == nil || i.HostConfig == nil || !i.HostConfig.PidMode.IsContainer() {
+		return ""
+	}
+	return i.HostConfig.PidMode.Container()
+}
+
+// resolvePidNamespaceOwners replaces the names and short IDs given to
+// --pid=container:<id> with the IDs of the listed containers. The owners
+// which aren't listed are kept as given.
+func resolvePidNamespaceOwners(ret []*Container, listed []types.Container) {
+	ids := make(map[string]struct{}, len(listed))
+	idByName := make(map[string]string, len(listed))
+	for _, c := range listed {
+		ids[c.ID] = struct{}{}
+		for _, n := range c.Names {
+			idByName[strings.TrimPrefix(n, "/")] = c.ID
+		}
+	}
+	for _, c := range ret {
+		if c.PidNamespaceOwner == "" {
+			continue
+		}
+		if id, ok := idByName[c.PidNamespaceOwner]; ok {
+			c.PidNamespaceOwner = id
+		} else if id, err := ResolveContainerID(c.PidNamespaceOwner, ids); err == nil {
+			c.PidNamespaceOwner = id
+		}
+	}
+}
+
+// findDockerNetworks maps the docker networks of a container to the
+// interfaces of its network namespace. Containers in network host mode get
+// an empty mapping since their interfaces are the host's.
 func findDockerNetworks(containerID string, pid int, netSettings *types.SummaryNetworkSettings) []dockerNetwork {
 	// Verify that we aren't using an older version of Docker that does
 	// not provider the network settings in container inspect.
@@ -559,10 +2422,21 @@ func findDockerNetworks(containerID string, pid int, netSettings *types.SummaryN
 
 	var err error
 	dockerGateways := make(map[string]int64)
+	ipv6Gateways := make(map[string]net.IP)
 	for netName, netConf := range netSettings.Networks {
 		gw := netConf.Gateway
-		if netName == "host" || gw == "" {
-			log.Debugf("Empty network gateway, container %s is in network host mode, its network metrics are for the whole host", containerID)
+		// IPv6-only networks don't have an IPv4 gateway.
+		if gw == "" {
+			gw = netConf.IPv6Gateway
+		}
+		if netName == "host" {
+			// The interfaces are the ones of the host, their traffic isn't
+			// the container's.
+			log.Debugf("Container %s is in network host mode, skipping its network metrics", containerID)
+			return []dockerNetwork{}
+		}
+		if gw == "" {
+			log.Debugf("Empty network gateway for container %s, defaulting to host network", containerID)
 			return []dockerNetwork{hostNetwork}
 		}
 
@@ -582,48 +2456,201 @@ func findDockerNetworks(containerID string, pid int, netSettings *types.SummaryN
 			}
 		}
 
+		if ip.To4() == nil {
+			ipv6Gateways[netName] = ip
+			continue
+		}
 		// Convert IP to int64 for comparison to network routes.
 		dockerGateways[netName] = int64(binary.BigEndian.Uint32(ip.To4()))
 	}
 
+	var networks []dockerNetwork
+	if len(dockerGateways) > 0 || len(ipv6Gateways) == 0 {
+		networks = findIPv4Networks(containerID, pid, dockerGateways)
+	}
+	if len(ipv6Gateways) > 0 {
+		networks = append(networks, findIPv6Networks(containerID, pid, ipv6Gateways)...)
+	}
+	if networks != nil {
+		sort.Sort(dockerNetworks(networks))
+	}
+	return networks
+}
+
+// containerIPs returns the IPv4 addresses of a container in all the networks
+// it's attached to, from the same network settings as findDockerNetworks,
+// sorted by network name. The host network has no address of its own, the
+// host's aren't reported.
+func containerIPs(netSettings *types.SummaryNetworkSettings) []string {
+	if netSettings == nil || len(netSettings.Networks) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(netSettings.Networks))
+	for name := range netSettings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var ips []string
+	for _, name := range names {
+		if conf := netSettings.Networks[name]; conf != nil && conf.IPAddress != "" {
+			ips = append(ips, conf.IPAddress)
+		}
+	}
+	return ips
+}
+
+// PrimaryIP returns the first IP address of the container, empty if it has
+// none, e.g. in network host mode.
+func (c *Container) PrimaryIP() string {
+	if len(c.IPAddresses) == 0 {
+		return ""
+	}
+	return c.IPAddresses[0]
+}
+
+// findIPv4Networks matches the IPv4 gateways of the docker networks with the
+// interfaces in the container's route table: a gateway belongs to the network
+// of the route whose destination is the gateway under the route's mask.
+func findIPv4Networks(containerID string, pid int, dockerGateways map[string]int64) []dockerNetwork {
 	// Read contents of file. Handle missing or unreadable file in case container was stopped.
+	// The file is streamed since hosts can have thousands of routes.
 	procNetFile := util.HostProc(strconv.Itoa(int(pid)), "net", "route")
 	if !util.PathExists(procNetFile) {
 		log.Debugf("Missing %s for container %s", procNetFile, containerID)
 		return nil
 	}
-	lines, err := util.ReadLines(procNetFile)
+	f, err := os.Open(procNetFile)
 	if err != nil {
 		log.Debugf("Unable to read %s for container %s", procNetFile, containerID)
 		return nil
 	}
-	if len(lines) < 1 {
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// Skip the header line.
+	if !scanner.Scan() {
 		log.Errorf("empty network file, unable to get docker networks: %s", procNetFile)
 		return nil
 	}
 
 	networks := make([]dockerNetwork, 0)
-	for _, line := range lines[1:] {
-		fields := strings.Fields(line)
+	matched := make(map[string]struct{}, len(dockerGateways))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
 		if len(fields) < 8 {
 			continue
 		}
-		if fields[0] == "00000000" {
+		dest, err := parseRouteAddr(fields[1])
+		if err != nil {
+			continue
+		}
+		mask, err := parseRouteAddr(fields[7])
+		// The default route, with an empty mask, would match any gateway.
+		if err != nil || mask == 0 {
 			continue
 		}
-		dest, _ := strconv.ParseInt(fields[1], 16, 32)
-		mask, _ := strconv.ParseInt(fields[7], 16, 32)
 		for net, gw := range dockerGateways {
 			if gw&mask == dest {
 				networks = append(networks, dockerNetwork{fields[0], net})
+				matched[net] = struct{}{}
+			}
+		}
+		// No need to read the rest of the routes once all gateways are found.
+		if len(matched) == len(dockerGateways) {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Debugf("Unable to read %s for container %s: %s", procNetFile, containerID, err)
+		return nil
+	}
+	return networks
+}
+
+// parseRouteAddr parses an address or mask of /proc/net/route into an integer
+// comparable with the gateways. They're printed as hex in the host byte
+// order, e.g. 000011AC for 172.17.0.0 on little-endian hosts.
+func parseRouteAddr(s string) (int64, error) {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, err
+	}
+	var b [4]byte
+	hostByteOrder.PutUint32(b[:], uint32(v))
+	return int64(binary.BigEndian.Uint32(b[:])), nil
+}
+
+// hostByteOrder is the byte order of the host, detected once from the
+// layout of a uint16 in memory.
+var hostByteOrder = func() binary.ByteOrder {
+	probe := uint16(1)
+	if *(*byte)(unsafe.Pointer(&probe)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}()
+
+// findIPv6Networks matches the IPv6 gateways of the docker networks with the
+// interfaces in the container's IPv6 route table. The format is:
+//
+// fd000000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0
+//
+// with the destination, destination prefix length, source, source prefix
+// length, next hop, metric, reference count, use count, flags and interface.
+// Addresses are in network byte order and the prefix lengths in hexadecimal.
+func findIPv6Networks(containerID string, pid int, gateways map[string]net.IP) []dockerNetwork {
+	procNetFile := util.HostProc(strconv.Itoa(int(pid)), "net", "ipv6_route")
+	if !util.PathExists(procNetFile) {
+		log.Debugf("Missing %s for container %s", procNetFile, containerID)
+		return nil
+	}
+	f, err := os.Open(procNetFile)
+	if err != nil {
+		log.Debugf("Unable to read %s for container %s", procNetFile, containerID)
+		return nil
+	}
+	defer f.Close()
+
+	networks := make([]dockerNetwork, 0)
+	matched := make(map[string]struct{}, len(gateways))
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[9] == "lo" {
+			continue
+		}
+		dest, err := hex.DecodeString(fields[0])
+		if err != nil || len(dest) != net.IPv6len {
+			continue
+		}
+		prefixLen, err := strconv.ParseUint(fields[1], 16, 8)
+		// Skip the default route, it matches every gateway.
+		if err != nil || prefixLen == 0 {
+			continue
+		}
+		subnet := net.IPNet{IP: net.IP(dest), Mask: net.CIDRMask(int(prefixLen), 8*net.IPv6len)}
+		for net, gw := range gateways {
+			if subnet.Contains(gw) {
+				networks = append(networks, dockerNetwork{fields[9], net})
+				matched[net] = struct{}{}
 			}
 		}
+		// No need to read the rest of the routes once all gateways are found.
+		if len(matched) == len(gateways) {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Debugf("Unable to read %s for container %s: %s", procNetFile, containerID, err)
+		return nil
 	}
-	sort.Sort(dockerNetworks(networks))
 	return networks
 }
 
-func collectNetworkStats(containerID string, pid int, networks []dockerNetwork) (*NetworkStat, error) {
+// collectNetworkStats sums the stats of the interfaces of the given networks.
+// When perInterface is set the stats of each network are also reported
+// separately in PerInterface.
+func collectNetworkStats(containerID string, pid int, networks []dockerNetwork, perInterface bool) (*NetworkStat, error) {
 	procNetFile := util.HostProc(strconv.Itoa(int(pid)), "net", "dev")
 	if !util.PathExists(procNetFile) {
 		log.Debugf("Unable to read %s for container %s", procNetFile, containerID)
@@ -638,12 +2665,14 @@ func collectNetworkStats(containerID string, pid int, networks []dockerNetwork)
 		return nil, fmt.Errorf("invalid format for %s", procNetFile)
 	}
 
-	nwByIface := make(map[string]dockerNetwork)
+	// Several docker networks can share the same interface.
+	nwByIface := make(map[string][]dockerNetwork)
 	for _, nw := range networks {
-		nwByIface[nw.iface] = nw
+		nwByIface[nw.iface] = append(nwByIface[nw.iface], nw)
 	}
 
-	// Format:
+	// Format, the counters may be glued to the interface name when they are
+	// wide enough:
 	//
 	// Inter-|   Receive                                                |  Transmit
 	// face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
@@ -651,41 +2680,99 @@ func collectNetworkStats(containerID string, pid int, networks []dockerNetwork)
 	// lo:       0       0    0    0    0     0          0         0        0       0    0    0    0     0       0          0
 	//
 	stat := &NetworkStat{}
+	if perInterface {
+		stat.PerInterface = make(map[string]*NetworkStat, len(networks))
+	}
 	for _, line := range lines[2:] {
-		fields := strings.Fields(line)
-		if len(fields) < 11 {
+		iface, ifaceStat, ok := parseNetDevLine(line)
+		if !ok {
+			log.Debugf("Skipping malformed line in %s: %q", procNetFile, line)
 			continue
 		}
-		iface := fields[0][:len(fields[0])-1]
 
-		if _, ok := nwByIface[iface]; ok {
-			rcvd, _ := strconv.Atoi(fields[1])
-			stat.BytesRcvd += uint64(rcvd)
-			pktRcvd, _ := strconv.Atoi(fields[2])
-			stat.PacketsRcvd += uint64(pktRcvd)
-			sent, _ := strconv.Atoi(fields[9])
-			stat.BytesSent += uint64(sent)
-			pktSent, _ := strconv.Atoi(fields[10])
-			stat.PacketsSent += uint64(pktSent)
+		if nws, ok := nwByIface[iface]; ok {
+			stat.BytesRcvd += ifaceStat.BytesRcvd
+			stat.PacketsRcvd += ifaceStat.PacketsRcvd
+			stat.BytesSent += ifaceStat.BytesSent
+			stat.PacketsSent += ifaceStat.PacketsSent
+			if perInterface {
+				for _, nw := range nws {
+					stat.PerInterface[nw.dockerName] = ifaceStat
+				}
+			}
 		}
 	}
 	return stat, nil
 }
 
-var healthRe = regexp.MustCompile(`\(health: (\w+)\)`)
+// parseNetDevLine parses an interface line of /proc/net/dev. The name is
+// everything before the first colon and only the receive and transmit bytes
+// and packets are kept, extra columns are ignored. ok is false if the line
+// is malformed.
+func parseNetDevLine(line string) (iface string, stat *NetworkStat, ok bool) {
+	idx := strings.IndexByte(line, ':')
+	if idx < 0 {
+		return "", nil, false
+	}
+	iface = strings.TrimSpace(line[:idx])
+	fields := strings.Fields(line[idx+1:])
+	if iface == "" || len(fields) < 10 {
+		return "", nil, false
+	}
+
+	var values [4]uint64
+	for i, col := range []int{0, 1, 8, 9} {
+		v, err := strconv.ParseUint(fields[col], 10, 64)
+		if err != nil {
+			return "", nil, false
+		}
+		values[i] = v
+	}
+	return iface, &NetworkStat{
+		BytesRcvd:   values[0],
+		PacketsRcvd: values[1],
+		BytesSent:   values[2],
+		PacketsSent: values[3],
+	}, true
+}
+
+var healthRe = regexp.MustCompile(`\((?:health: ([^)]+)|(healthy|unhealthy))\)`)
 
 // Parse the health out of a container status. The format is either:
 //  - 'Up 5 seconds (health: starting)'
+//  - 'Up 5 minutes (healthy)' or 'Up 5 minutes (unhealthy)'
 //  - 'Up about an hour'
 //
+// Other parenthesized values like the exit code in 'Exited (0) 5 minutes ago'
+// or '(Paused)' aren't health statuses. "none" means no healthcheck.
 func parseContainerHealth(status string) string {
 	// Avoid allocations in most cases by just checking for '('
 	if strings.IndexByte(status, '(') == -1 {
 		return ""
 	}
-	all := healthRe.FindAllStringSubmatch(status, -1)
-	if len(all) < 1 || len(all[0]) < 2 {
+	m := healthRe.FindStringSubmatch(status)
+	if m == nil {
+		return ""
+	}
+	health := strings.TrimSpace(m[1])
+	if health == "" {
+		health = m[2]
+	}
+	if health == "none" {
 		return ""
 	}
-	return all[0][1]
+	return health
+}
+
+// truncateCommand truncates cmd to maxCommandLength bytes without splitting
+// a multi-byte character.
+func truncateCommand(cmd string) string {
+	if len(cmd) <= maxCommandLength {
+		return cmd
+	}
+	cut := maxCommandLength
+	for cut > 0 && !utf8.RuneStart(cmd[cut]) {
+		cut--
+	}
+	return cmd[:cut]
 }
@@ -1,14 +1,31 @@
 package docker
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-process-agent/util"
+	"github.com/DataDog/datadog-process-agent/util/cache"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/mount"
 	dockernetwork "github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,6 +44,7 @@ func TestFindDockerNetworks(t *testing.T) {
 		pid         int
 		settings    *types.SummaryNetworkSettings
 		routes, dev string
+		ipv6Routes  string
 		networks    []dockerNetwork
 		stat        *NetworkStat
 	}{
@@ -35,7 +53,7 @@ func TestFindDockerNetworks(t *testing.T) {
 			settings: &types.SummaryNetworkSettings{
 				Networks: map[string]*dockernetwork.EndpointSettings{
 					"eth0": &dockernetwork.EndpointSettings{
-						Gateway: "172.0.0.1/24",
+						Gateway: "172.17.0.1/16",
 					},
 				},
 			},
@@ -67,7 +85,7 @@ func TestFindDockerNetworks(t *testing.T) {
 						Gateway: "172.18.0.1",
 					},
 					"eth0": &dockernetwork.EndpointSettings{
-						Gateway: "172.0.0.4/24",
+						Gateway: "172.18.0.4/24",
 					},
 				},
 			},
@@ -94,6 +112,104 @@ func TestFindDockerNetworks(t *testing.T) {
 				PacketsSent: 80,
 			},
 		},
+		// IPv6-only network alongside an IPv4 one.
+		{
+			pid: 5153,
+			settings: &types.SummaryNetworkSettings{
+				Networks: map[string]*dockernetwork.EndpointSettings{
+					"eth0": &dockernetwork.EndpointSettings{
+						Gateway: "172.18.0.1/16",
+					},
+					"v6_nw": &dockernetwork.EndpointSettings{
+						IPv6Gateway: "fd00:dead:beef::1",
+					},
+				},
+			},
+			routes: detab(`
+				Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+				eth0	00000000	010012AC	0003	0	0	0	00000000	0	0	0
+
+				eth0	000012AC	00000000	0001	0	0	0	0000FFFF	0	0	0
+			`),
+			ipv6Routes: detab(`
+				fd00deadbeef00000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth1
+				fe800000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0
+				00000000000000000000000000000000 00 00000000000000000000000000000000 00 fd00deadbeef00000000000000000001 00000400 00000001 00000000 00000003     eth1
+				00000000000000000000000000000001 80 00000000000000000000000000000000 00 00000000000000000000000000000000 00000000 00000002 00000000 80200001       lo
+			`),
+			dev: detab(`
+				Inter-|   Receive                                                |  Transmit
+				 face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+				  eth0:    1111       2    0    0    0     0          0         0     1024      80    0    0    0     0       0          0
+				  eth1:     100       1    0    0    0     0          0         0      200       2    0    0    0     0       0          0
+				    lo:       0       0    0    0    0     0          0         0        0       0    0    0    0     0       0          0
+			`),
+			networks: []dockerNetwork{
+				dockerNetwork{iface: "eth0", dockerName: "eth0"},
+				dockerNetwork{iface: "eth1", dockerName: "v6_nw"},
+			},
+			stat: &NetworkStat{
+				BytesRcvd:   1211,
+				PacketsRcvd: 3,
+				BytesSent:   1224,
+				PacketsSent: 82,
+			},
+		},
+		// Networks on different interfaces, with the default route on one of
+		// them. The /16 gateway only matches the route of its subnet.
+		{
+			pid: 5155,
+			settings: &types.SummaryNetworkSettings{
+				Networks: map[string]*dockernetwork.EndpointSettings{
+					"backend": &dockernetwork.EndpointSettings{
+						Gateway: "10.10.0.1",
+					},
+					"frontend": &dockernetwork.EndpointSettings{
+						Gateway: "172.19.0.1",
+					},
+				},
+			},
+			routes: detab(`
+				Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+				eth0	00000000	01000A0A	0003	0	0	0	00000000	0	0	0
+				eth0	00000A0A	00000000	0001	0	0	0	0000FFFF	0	0	0
+				eth1	000013AC	00000000	0001	0	0	0	0000FFFF	0	0	0
+			`),
+			dev: detab(`
+				Inter-|   Receive                                                |  Transmit
+				 face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+				  eth0:    1111       2    0    0    0     0          0         0     1024      80    0    0    0     0       0          0
+				  eth1:     100       1    0    0    0     0          0         0      200       2    0    0    0     0       0          0
+				    lo:       0       0    0    0    0     0          0         0        0       0    0    0    0     0       0          0
+			`),
+			networks: []dockerNetwork{
+				dockerNetwork{iface: "eth0", dockerName: "backend"},
+				dockerNetwork{iface: "eth1", dockerName: "frontend"},
+			},
+			stat: &NetworkStat{
+				BytesRcvd:   1211,
+				PacketsRcvd: 3,
+				BytesSent:   1224,
+				PacketsSent: 82,
+			},
+		},
+		// Host network mode, the interfaces are the host's.
+		{
+			pid: 5154,
+			settings: &types.SummaryNetworkSettings{
+				Networks: map[string]*dockernetwork.EndpointSettings{
+					"host": &dockernetwork.EndpointSettings{},
+				},
+			},
+			dev: detab(`
+				Inter-|   Receive                                                |  Transmit
+				 face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+				  eth0:    1111       2    0    0    0     0          0         0     1024      80    0    0    0     0       0          0
+				    lo:       0       0    0    0    0     0          0         0        0       0    0    0    0     0       0          0
+			`),
+			networks: []dockerNetwork{},
+			stat:     &NetworkStat{},
+		},
 		// Dumb error case to make sure we don't panic
 		{
 			pid: 5157,
@@ -128,18 +244,282 @@ func TestFindDockerNetworks(t *testing.T) {
 		f2, err := os.Create(util.HostProc(strconv.Itoa(int(tc.pid)), "net", "dev"))
 		assert.NoError(err)
 		f2.WriteString(tc.dev)
+		f3, err := os.Create(util.HostProc(strconv.Itoa(int(tc.pid)), "net", "ipv6_route"))
+		assert.NoError(err)
+		f3.WriteString(tc.ipv6Routes)
 
 		// Use the routes file and settings to get our networks.
 		networks := findDockerNetworks(containerID, tc.pid, tc.settings)
 		assert.Equal(tc.networks, networks)
 
 		// And collect the stats on these networks.
-		stat, err := collectNetworkStats(containerID, tc.pid, networks)
+		stat, err := collectNetworkStats(containerID, tc.pid, networks, false)
 		assert.NoError(err)
 		assert.Equal(tc.stat, stat)
 
 		f1.Close()
 		f2.Close()
+		f3.Close()
+	}
+}
+
+func TestContainerIPs(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(containerIPs(nil))
+	assert.Nil(containerIPs(&types.SummaryNetworkSettings{}))
+	// The host network has no address of its own.
+	assert.Nil(containerIPs(&types.SummaryNetworkSettings{Networks: map[string]*dockernetwork.EndpointSettings{
+		"host": {},
+	}}))
+
+	settings := &types.SummaryNetworkSettings{Networks: map[string]*dockernetwork.EndpointSettings{
+		"frontend": {IPAddress: "172.19.0.4", Gateway: "172.19.0.1"},
+		"backend":  {IPAddress: "172.18.0.2", Gateway: "172.18.0.1"},
+		"ipv6only": {GlobalIPv6Address: "fd00::2", IPv6Gateway: "fd00::1"},
+	}}
+	assert.Equal([]string{"172.18.0.2", "172.19.0.4"}, containerIPs(settings))
+
+	cli := &fakeDockerClient{containers: []types.Container{
+		{ID: "1", Names: []string{"/web"}, State: "running", NetworkSettings: settings},
+		{ID: "2", Names: []string{"/agent"}, State: "running"},
+	}}
+	d, err := newDockerUtil(&Config{}, cli)
+	assert.NoError(err)
+	containers, err := d.dockerContainers()
+	assert.NoError(err)
+	if assert.Len(containers, 2) {
+		assert.Equal("172.18.0.2", containers[0].PrimaryIP())
+		assert.Equal("", containers[1].PrimaryIP())
+	}
+}
+
+func TestContainerNetworksSharedPidNamespace(t *testing.T) {
+	assert := assert.New(t)
+
+	inspect := func(pidMode string) types.ContainerJSON {
+		return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+			State:      &types.ContainerState{Pid: 5160},
+			HostConfig: &container.HostConfig{PidMode: container.PidMode(pidMode)},
+		}}
+	}
+	assert.Equal("", sharedPidNamespace(types.ContainerJSON{}))
+	assert.Equal("", sharedPidNamespace(inspect("")))
+	assert.Equal("", sharedPidNamespace(inspect("host")))
+	assert.Equal("app", sharedPidNamespace(inspect("container:app")))
+
+	assert.Equal([]dockerNetwork{}, containerNetworks("sidecar", inspect("container:app"), nil))
+	assert.Equal([]dockerNetwork{hostNetwork}, containerNetworks("app", inspect(""), nil))
+}
+
+func TestDockerContainersPidNamespaceOwner(t *testing.T) {
+	assert := assert.New(t)
+
+	appID := "a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419"
+	inspect := func(pidMode string) types.ContainerJSON {
+		return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+			State:      &types.ContainerState{Pid: 1},
+			HostConfig: &container.HostConfig{PidMode: container.PidMode(pidMode)},
+		}}
+	}
+	cli := &fakeDockerClient{
+		containers: []types.Container{
+			{ID: appID, Names: []string{"/app"}, State: "running"},
+			{ID: "2", Names: []string{"/by-name"}, State: "running"},
+			{ID: "3", Names: []string{"/by-short-id"}, State: "running"},
+			{ID: "4", Names: []string{"/unknown"}, State: "running"},
+		},
+		inspects: map[string]types.ContainerJSON{
+			appID: inspect(""),
+			"2":   inspect("container:app"),
+			"3":   inspect("container:" + appID[:12]),
+			"4":   inspect("container:gone"),
+		},
+	}
+	d, err := newDockerUtil(&Config{CollectRestartCount: true}, cli)
+	assert.NoError(err)
+	containers, err := d.dockerContainers()
+	assert.NoError(err)
+	owners := make(map[string]string)
+	for _, c := range containers {
+		owners[c.Name] = c.PidNamespaceOwner
+	}
+	// The names and short IDs given to --pid are resolved to the full IDs.
+	assert.Equal(map[string]string{"app": "", "by-name": appID, "by-short-id": appID, "unknown": "gone"}, owners)
+}
+
+func TestNetworkStatsHostMode(t *testing.T) {
+	assert := assert.New(t)
+
+	d, err := newDockerUtil(&Config{CollectNetwork: true}, &fakeDockerClient{})
+	assert.NoError(err)
+	d.networkMappings["host"] = []dockerNetwork{}
+	d.initPids["host"] = os.Getpid()
+
+	stat, err := d.networkStats(&Container{ID: "host"})
+	assert.NoError(err)
+	assert.Equal(NullContainer.Network, stat)
+}
+
+func TestCollectNetworkStatsPerInterface(t *testing.T) {
+	assert := assert.New(t)
+
+	hostProc := "/tmp/test-collect-network-stats/proc/"
+	pid := 4242
+	err := os.MkdirAll(hostProc+strconv.Itoa(pid)+"/net", 0777)
+	assert.NoError(err)
+	os.Setenv("HOST_PROC", hostProc)
+	defer os.Setenv("HOST_PROC", "/proc")
+	defer os.RemoveAll(hostProc)
+
+	f, err := os.Create(util.HostProc(strconv.Itoa(pid), "net", "dev"))
+	assert.NoError(err)
+	f.WriteString(detab(`
+		Inter-|   Receive                                                |  Transmit
+		 face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+		  eth0:    1111       2    0    0    0     0          0         0     1024      80    0    0    0     0       0          0
+		  eth1:     100       1    0    0    0     0          0         0      200       2    0    0    0     0       0          0
+		  eth0     9999       9    0    0    0     0          0         0     9999       9    0    0    0     0       0          0
+		  eth1:    garbage    1    0    0    0     0          0         0      200       2    0    0    0     0       0          0
+		    lo:       0       0    0    0    0     0          0         0        0       0    0    0    0     0       0          0
+	`))
+	f.Close()
+
+	networks := []dockerNetwork{
+		dockerNetwork{iface: "eth0", dockerName: "bridge"},
+		dockerNetwork{iface: "eth1", dockerName: "backend"},
+	}
+	eth0 := &NetworkStat{BytesRcvd: 1111, PacketsRcvd: 2, BytesSent: 1024, PacketsSent: 80}
+	eth1 := &NetworkStat{BytesRcvd: 100, PacketsRcvd: 1, BytesSent: 200, PacketsSent: 2}
+
+	stat, err := collectNetworkStats("test", pid, networks, false)
+	assert.NoError(err)
+	assert.Nil(stat.PerInterface)
+
+	stat, err = collectNetworkStats("test", pid, networks, true)
+	assert.NoError(err)
+	assert.Equal(uint64(1211), stat.BytesRcvd)
+	assert.Equal(uint64(3), stat.PacketsRcvd)
+	assert.Equal(uint64(1224), stat.BytesSent)
+	assert.Equal(uint64(82), stat.PacketsSent)
+	assert.Equal(map[string]*NetworkStat{"bridge": eth0, "backend": eth1}, stat.PerInterface)
+}
+
+func TestParseRouteAddr(t *testing.T) {
+	assert := assert.New(t)
+	defer func(order binary.ByteOrder) { hostByteOrder = order }(hostByteOrder)
+	for _, tc := range []struct {
+		hex string
+		ip  string
+	}{
+		{"010011AC", "172.17.0.1"},
+		{"000011AC", "172.17.0.0"},
+		{"0000FFFF", "255.255.0.0"},
+		{"00FFFFFF", "255.255.255.0"},
+		{"FFFFFFFF", "255.255.255.255"},
+		{"00000000", "0.0.0.0"},
+	} {
+		// The routes are in the byte order of little-endian hosts like the
+		// other route tables of these tests.
+		hostByteOrder = binary.LittleEndian
+		v, err := parseRouteAddr(tc.hex)
+		assert.NoError(err, tc.hex)
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(v))
+		assert.Equal(tc.ip, net.IP(b[:]).String(), tc.hex)
+	}
+	// Big-endian hosts print them in network byte order.
+	hostByteOrder = binary.BigEndian
+	v, err := parseRouteAddr("AC110001")
+	assert.NoError(err)
+	assert.Equal(int64(0xAC110001), v)
+
+	_, err = parseRouteAddr("garbage")
+	assert.Error(err)
+	_, err = parseRouteAddr("1FFFFFFFF")
+	assert.Error(err)
+}
+
+func TestParseNetDevLine(t *testing.T) {
+	assert := assert.New(t)
+	for i, tc := range []struct {
+		line     string
+		iface    string
+		expected *NetworkStat
+	}{
+		{
+			line:     "  eth0:    1296      16    0    0    0     0          0         0      512       8    0    0    0     0       0          0",
+			iface:    "eth0",
+			expected: &NetworkStat{BytesRcvd: 1296, PacketsRcvd: 16, BytesSent: 512, PacketsSent: 8},
+		},
+		// Wide counters are glued to the interface name.
+		{
+			line:     "eth0:1234567890      16    0    0    0     0          0         0      512       8    0    0    0     0       0          0",
+			iface:    "eth0",
+			expected: &NetworkStat{BytesRcvd: 1234567890, PacketsRcvd: 16, BytesSent: 512, PacketsSent: 8},
+		},
+		// Extra columns are ignored.
+		{
+			line:     "veth1: 10 1 0 0 0 0 0 0 20 2 0 0 0 0 0 0 42 42",
+			iface:    "veth1",
+			expected: &NetworkStat{BytesRcvd: 10, PacketsRcvd: 1, BytesSent: 20, PacketsSent: 2},
+		},
+		// Only the first colon ends the name.
+		{
+			line:     "eth0.100: 10 1 0 0 0 0 0 0 20 2 0 0 0 0 0 0",
+			iface:    "eth0.100",
+			expected: &NetworkStat{BytesRcvd: 10, PacketsRcvd: 1, BytesSent: 20, PacketsSent: 2},
+		},
+		// Malformed lines.
+		{line: ""},
+		{line: "eth0 10 1 0 0 0 0 0 0 20 2 0 0 0 0 0 0"},
+		{line: ": 10 1 0 0 0 0 0 0 20 2 0 0 0 0 0 0"},
+		{line: "eth0: 10 1 0 0 0 0 0 0"},
+		{line: "eth0: 10 x 0 0 0 0 0 0 20 2 0 0 0 0 0 0"},
+		{line: "eth0: 10 1 0 0 0 0 0 0 -20 2 0 0 0 0 0 0"},
+	} {
+		iface, stat, ok := parseNetDevLine(tc.line)
+		assert.Equal(tc.expected != nil, ok, "case %d", i)
+		assert.Equal(tc.iface, iface, "case %d", i)
+		assert.Equal(tc.expected, stat, "case %d", i)
+	}
+}
+
+func BenchmarkFindDockerNetworks(b *testing.B) {
+	hostProc := "/tmp/benchmark-find-docker-networks/proc/"
+	os.Setenv("HOST_PROC", hostProc)
+	defer os.Setenv("HOST_PROC", "/proc")
+	defer os.RemoveAll(hostProc)
+
+	// A large route table where the matching route is at the very end.
+	pid := 1245
+	routePath := util.HostProc(strconv.Itoa(pid), "net")
+	if err := os.MkdirAll(routePath, 0777); err != nil {
+		b.Fatal(err)
+	}
+	f, err := os.Create(util.HostProc(strconv.Itoa(pid), "net", "route"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	f.WriteString("Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n")
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(f, "eth1\t%08X\t00000000\t0001\t0\t0\t0\tFFFFFFFF\t0\t0\t0\n", 0x0A000000+i)
+	}
+	f.WriteString("eth0\t00000000\t010011AC\t0003\t0\t0\t0\t00000000\t0\t0\t0\n")
+	f.WriteString("eth0\t000011AC\t00000000\t0001\t0\t0\t0\t0000FFFF\t0\t0\t0\n")
+	f.Close()
+
+	settings := &types.SummaryNetworkSettings{
+		Networks: map[string]*dockernetwork.EndpointSettings{
+			"bridge": &dockernetwork.EndpointSettings{
+				Gateway: "172.17.0.1",
+			},
+		},
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		findDockerNetworks("benchmark-find-docker-networks", pid, settings)
 	}
 }
 
@@ -174,9 +554,10 @@ func TestContainerFilter(t *testing.T) {
 	}
 
 	for i, tc := range []struct {
-		whitelist   []string
-		blacklist   []string
-		expectedIDs []string
+		whitelist     []string
+		blacklist     []string
+		whitelistMode string
+		expectedIDs   []string
 	}{
 		{
 			expectedIDs: []string{"1", "2", "3", "4", "5"},
@@ -207,9 +588,115 @@ func TestContainerFilter(t *testing.T) {
 			},
 			expectedIDs: []string{"1", "2", "3", "4"},
 		},
+		// Strict whitelists exclude everything else.
+		{
+			whitelist:     []string{"name:mysql", "image:alpine"},
+			whitelistMode: WhitelistModeStrict,
+			expectedIDs:   []string{"3", "4"},
+		},
+		{
+			whitelist:     []string{"name:mysql"},
+			whitelistMode: WhitelistModeOverride,
+			expectedIDs:   []string{"1", "2", "3", "4", "5"},
+		},
+		{
+			whitelist:     []string{"name:mysql"},
+			blacklist:     []string{"name:dd"},
+			whitelistMode: WhitelistModeStrict,
+			expectedIDs:   []string{"3"},
+		},
+		// An empty strict whitelist keeps the blacklist behavior.
+		{
+			blacklist:     []string{"name:secret"},
+			whitelistMode: WhitelistModeStrict,
+			expectedIDs:   []string{"2", "3", "4", "5"},
+		},
+	} {
+		f, err := newContainerFilter(tc.whitelist, tc.blacklist, tc.whitelistMode)
+		assert.NoError(err, "case %d", i)
+
+		var allowed []string
+		for _, c := range containers {
+			if !f.IsExcluded(c) {
+				allowed = append(allowed, c.ID)
+			}
+		}
+		assert.Equal(tc.expectedIDs, allowed, "case %d", i)
+	}
+
+	_, err := newContainerFilter(nil, nil, "exclusive")
+	assert.Error(err)
+}
+
+func TestContainerFilterRawName(t *testing.T) {
+	assert := assert.New(t)
+	cli := &fakeDockerClient{
+		containers: []types.Container{
+			{ID: "1", Names: []string{"/redis"}, Image: "redis:latest", State: "running"},
+			{ID: "2", Names: []string{"/web/db", "/db"}, Image: "postgres:latest", State: "running"},
+		},
+	}
+
+	// The name patterns match the first name with its leading "/", as before
+	// the names were stripped.
+	for i, tc := range []struct {
+		blacklist   []string
+		expectedIDs []string
+	}{
+		{blacklist: []string{"name:^/redis$"}, expectedIDs: []string{"2"}},
+		{blacklist: []string{"name:^redis$"}, expectedIDs: []string{"1", "2"}},
+		{blacklist: []string{"name:^/web/db$"}, expectedIDs: []string{"1"}},
+	} {
+		d, err := newDockerUtil(&Config{Blacklist: tc.blacklist}, cli)
+		assert.NoError(err)
+		containers, err := d.dockerContainers()
+		assert.NoError(err)
+		var ids []string
+		for _, c := range containers {
+			ids = append(ids, c.ID)
+		}
+		assert.Equal(tc.expectedIDs, ids, "case %d", i)
+	}
+}
+
+func TestContainerFilterNetworkMode(t *testing.T) {
+	assert := assert.New(t)
+	containers := []*Container{
+		{ID: "1", Name: "agent", NetworkMode: "host"},
+		{ID: "2", Name: "batch", NetworkMode: "none"},
+		{ID: "3", Name: "web", NetworkMode: "bridge"},
+		{ID: "4", Name: "app", NetworkMode: "hostnet"},
+		{ID: "5", Name: "sidecar", NetworkMode: "container:3"},
+	}
+
+	for i, tc := range []struct {
+		whitelist     []string
+		blacklist     []string
+		whitelistMode string
+		expectedIDs   []string
+	}{
+		{
+			blacklist:   []string{"networkmode:host", "networkmode:none"},
+			expectedIDs: []string{"3", "4", "5"},
+		},
+		{
+			blacklist:   []string{"networkmode:container:.*"},
+			expectedIDs: []string{"1", "2", "3", "4"},
+		},
+		{
+			whitelist:   []string{"name:agent"},
+			blacklist:   []string{"networkmode:host"},
+			expectedIDs: []string{"1", "2", "3", "4", "5"},
+		},
+		{
+			whitelist:     []string{"networkmode:bridge"},
+			whitelistMode: WhitelistModeStrict,
+			expectedIDs:   []string{"3"},
+		},
 	} {
-		f, err := newContainerFilter(tc.whitelist, tc.blacklist)
+		f, err := newContainerFilter(tc.whitelist, tc.blacklist, tc.whitelistMode)
 		assert.NoError(err, "case %d", i)
+		assert.True(f.needsNetworkMode(), "case %d", i)
 
 		var allowed []string
 		for _, c := range containers {
@@ -219,6 +706,56 @@ func TestContainerFilter(t *testing.T) {
 		}
 		assert.Equal(tc.expectedIDs, allowed, "case %d", i)
 	}
+
+	_, err := newContainerFilter(nil, []string{"networkmode:("}, "")
+	assert.Error(err)
+}
+
+func TestDockerContainersNetworkModeFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{inspects: make(map[string]types.ContainerJSON)}
+	for id, mode := range map[string]string{"1": "host", "2": "default", "3": "none"} {
+		cli.containers = append(cli.containers, types.Container{ID: id, Names: []string{"/" + id}, State: "running"})
+		cli.inspects[id] = types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+			State:      &types.ContainerState{Pid: 1},
+			HostConfig: &container.HostConfig{NetworkMode: container.NetworkMode(mode)},
+		}}
+	}
+	// The containers are inspected for their network mode alone.
+	d, err := newDockerUtil(&Config{Blacklist: []string{"networkmode:host|none"}}, cli)
+	assert.NoError(err)
+	containers, err := d.dockerContainers()
+	assert.NoError(err)
+	assert.Equal(3, cli.inspectCalls)
+	if assert.Len(containers, 1) {
+		assert.Equal("2", containers[0].ID)
+		assert.Equal("default", containers[0].NetworkMode)
+	}
+
+	// The containers whose inspect is deferred are held back until their
+	// network mode is known.
+	cli.inspectCalls = 0
+	d, err = newDockerUtil(&Config{Blacklist: []string{"networkmode:host|none"}, MaxInspectsPerCycle: 1}, cli)
+	assert.NoError(err)
+	for i := 1; i <= 3; i++ {
+		containers, err = d.dockerContainers()
+		assert.NoError(err)
+		assert.Equal(i, cli.inspectCalls)
+		for _, c := range containers {
+			assert.Equal("2", c.ID)
+		}
+	}
+	assert.Len(containers, 1)
+
+	// No inspect without these filters.
+	cli.inspectCalls = 0
+	d, err = newDockerUtil(&Config{Blacklist: []string{"name:^/1$"}}, cli)
+	assert.NoError(err)
+	containers, err = d.dockerContainers()
+	assert.NoError(err)
+	assert.Equal(0, cli.inspectCalls)
+	assert.Len(containers, 2)
 }
 
 func TestParseContainerHealth(t *testing.T) {
@@ -243,7 +780,1586 @@ func TestParseContainerHealth(t *testing.T) {
 			input:    "Up 1 minute (health: unhealthy)",
 			expected: "unhealthy",
 		},
+		{
+			input:    "Up 5 minutes (healthy)",
+			expected: "healthy",
+		},
+		{
+			input:    "Up 5 minutes (unhealthy)",
+			expected: "unhealthy",
+		},
+		{
+			input:    "Up 5 minutes (health: none)",
+			expected: "",
+		},
+		{
+			input:    "Up 5 minutes (health: waiting for probe)",
+			expected: "waiting for probe",
+		},
+		{
+			input:    "Up 5 minutes (Paused)",
+			expected: "",
+		},
+		{
+			input:    "Up Less than a second",
+			expected: "",
+		},
+		{
+			input:    "Restarting (1) 5 seconds ago",
+			expected: "",
+		},
+		{
+			input:    "Exited (137) 2 hours ago",
+			expected: "",
+		},
+		{
+			input:    "Created",
+			expected: "",
+		},
+		{
+			input:    "Removal In Progress",
+			expected: "",
+		},
+		{
+			input:    "Dead",
+			expected: "",
+		},
 	} {
 		assert.Equal(tc.expected, parseContainerHealth(tc.input), "test %d failed", i)
 	}
 }
+
+func TestNewContainerDetails(t *testing.T) {
+	assert := assert.New(t)
+
+	// No config or no healthcheck configured.
+	assert.Nil(newContainerDetails(types.ContainerJSON{}).healthcheck)
+	assert.Nil(newContainerDetails(types.ContainerJSON{Config: &container.Config{}}).healthcheck)
+
+	details := newContainerDetails(types.ContainerJSON{
+		Config: &container.Config{
+			Healthcheck: &container.HealthConfig{
+				Test:     []string{"CMD-SHELL", "curl -f http://localhost/"},
+				Interval: time.Second,
+				Timeout:  5 * time.Second,
+				Retries:  3,
+			},
+		},
+	})
+	assert.Equal(&HealthcheckConfig{
+		Test:     []string{"CMD-SHELL", "curl -f http://localhost/"},
+		Interval: time.Second,
+		Retries:  3,
+	}, details.healthcheck)
+
+	// The start time is only set for containers which started.
+	details = newContainerDetails(types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			State: &types.ContainerState{StartedAt: "2018-04-02T20:34:37.123456789Z"},
+		},
+	})
+	assert.Equal(int64(1522701277), details.startedAt)
+	details = newContainerDetails(types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			State: &types.ContainerState{StartedAt: "0001-01-01T00:00:00Z"},
+		},
+	})
+	assert.Equal(int64(0), details.startedAt)
+
+	details = newContainerDetails(types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			LogPath:    "/var/lib/docker/containers/abc/abc-json.log",
+			HostConfig: &container.HostConfig{LogConfig: container.LogConfig{Type: "json-file"}, Privileged: true},
+		},
+	})
+	assert.Equal("json-file", details.logDriver)
+	assert.True(details.privileged)
+	assert.Equal("/var/lib/docker/containers/abc/abc-json.log", details.logPath)
+
+	details = newContainerDetails(types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			HostConfig: &container.HostConfig{Resources: container.Resources{CPUShares: 512, MemoryReservation: 268435456}},
+		},
+	})
+	assert.Equal(uint64(268435456), details.memReservation)
+	assert.Equal(int64(512), details.cpuShares)
+}
+
+func TestDockerContainersMounts(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{
+		containers: []types.Container{{ID: "1", Names: []string{"/postgres"}, State: "running"}},
+		inspects: map[string]types.ContainerJSON{
+			"1": {
+				ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Pid: 1}},
+				Mounts: []types.MountPoint{
+					{Type: mount.TypeBind, Source: "/etc/postgres", Destination: "/etc/postgresql", RW: false},
+					{Type: mount.TypeVolume, Name: "pgdata", Source: "/var/lib/docker/volumes/pgdata/_data", Destination: "/var/lib/postgresql/data", RW: true},
+					{Type: mount.TypeTmpfs, Destination: "/run"},
+				},
+			},
+		},
+	}
+	// The mounts come with the details of the inspect.
+	d, err := newDockerUtil(&Config{CollectRestartCount: true}, cli)
+	assert.NoError(err)
+	containers, err := d.dockerContainers()
+	assert.NoError(err)
+	if assert.Len(containers, 1) {
+		c := containers[0]
+		assert.Equal([]MountPoint{
+			{Source: "/etc/postgres", Destination: "/etc/postgresql", Type: "bind"},
+			{Source: "/var/lib/docker/volumes/pgdata/_data", Destination: "/var/lib/postgresql/data", Type: "volume", RW: true},
+			{Destination: "/run", Type: "tmpfs"},
+		}, c.Mounts)
+		total, binds, volumes := c.MountCounts()
+		assert.Equal(3, total)
+		assert.Equal(1, binds)
+		assert.Equal(1, volumes)
+	}
+}
+
+func TestContainerGPUs(t *testing.T) {
+	inspect := func(devices []string, env ...string) types.ContainerJSON {
+		hc := &container.HostConfig{}
+		for _, dev := range devices {
+			hc.Devices = append(hc.Devices, container.DeviceMapping{PathOnHost: dev, PathInContainer: dev, CgroupPermissions: "rwm"})
+		}
+		return types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{HostConfig: hc},
+			Config:            &container.Config{Env: env},
+		}
+	}
+	for _, tc := range []struct {
+		inspect types.ContainerJSON
+		count   int32
+		vendor  string
+	}{
+		{inspect(nil, "PATH=/usr/bin"), 0, ""},
+		{inspect([]string{"/dev/fuse"}), 0, ""},
+		{inspect([]string{"/dev/nvidiactl", "/dev/nvidia-uvm", "/dev/nvidia0", "/dev/nvidia1"}), 2, "nvidia"},
+		{inspect(nil, "NVIDIA_VISIBLE_DEVICES=GPU-0c5f8ef7-6b61-41d0-8a7c-7a4c2c1de3c6"), 1, "nvidia"},
+		{inspect(nil, "NVIDIA_VISIBLE_DEVICES=0,2,3"), 3, "nvidia"},
+		{inspect(nil, "NVIDIA_VISIBLE_DEVICES=all"), 0, ""},
+		{inspect(nil, "NVIDIA_VISIBLE_DEVICES=none"), 0, ""},
+		{types.ContainerJSON{}, 0, ""},
+	} {
+		count, vendor := containerGPUs(tc.inspect)
+		assert.Equal(t, tc.count, count)
+		assert.Equal(t, tc.vendor, vendor)
+	}
+
+	details := newContainerDetails(inspect([]string{"/dev/nvidia0"}))
+	assert.Equal(t, int32(1), details.gpuCount)
+	assert.Equal(t, "nvidia", details.gpuVendor)
+}
+
+func TestLabelTags(t *testing.T) {
+	labels := map[string]string{"team": "core", "env": "prod", "empty": ""}
+	assert.Nil(t, labelTags(labels, nil))
+	assert.Nil(t, labelTags(nil, []string{"team"}))
+	assert.Equal(t, []string{"env:prod", "team:core", "empty:"}, labelTags(labels, []string{"env", "missing", "team", "empty"}))
+}
+
+func TestLabelAnnotations(t *testing.T) {
+	labels := map[string]string{
+		"com.mycompany.scan.critical": "3",
+		"com.mycompany.scan.high":     " 12\n",
+		"com.mycompany.scan.low":      "unknown",
+		"com.mycompany.scan.medium":   "-1",
+		"team":                        "core",
+	}
+	assert.Nil(t, labelAnnotations("1", labels, nil))
+	assert.Nil(t, labelAnnotations("1", labels, []string{"team", "missing"}))
+	assert.Equal(t, map[string]int{
+		"com.mycompany.scan.critical": 3,
+		"com.mycompany.scan.high":     12,
+		"com.mycompany.scan.medium":   -1,
+	}, labelAnnotations("1", labels, []string{
+		"com.mycompany.scan.critical",
+		"com.mycompany.scan.high",
+		"com.mycompany.scan.medium",
+		"com.mycompany.scan.low",
+	}))
+}
+
+func TestImageTag(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		tag  string
+	}{
+		{"redis", ""},
+		{"redis:latest", "latest"},
+		{"quay.io/foo/bar:1.2.3", "1.2.3"},
+		// The registry port isn't a tag.
+		{"host:5000/repo", ""},
+		{"host:5000/repo:tag", "tag"},
+		{"host:5000/team/repo:v1", "v1"},
+		// Digests aren't tags.
+		{"quay.io/foo/bar@sha256:abcdef", ""},
+		{"quay.io/foo/bar:1.2.3@sha256:abcdef", "1.2.3"},
+		{"sha256:abcdef", ""},
+		{"", ""},
+	} {
+		assert.Equal(t, tc.tag, imageTag(tc.name), "image %s", tc.name)
+	}
+}
+
+func TestTruncateCommand(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("", truncateCommand(""))
+	assert.Equal("nginx -g daemon off;", truncateCommand("nginx -g daemon off;"))
+
+	long := strings.Repeat("a", maxCommandLength+10)
+	assert.Equal(long[:maxCommandLength], truncateCommand(long))
+
+	// Multi-byte characters are never split.
+	multi := strings.Repeat("a", maxCommandLength-1) + "é"
+	assert.Equal(multi[:maxCommandLength-1], truncateCommand(multi))
+}
+
+func TestContainerName(t *testing.T) {
+	assert := assert.New(t)
+	for i, tc := range []struct {
+		names    []string
+		expected string
+	}{
+		{nil, ""},
+		{[]string{"/redis"}, "redis"},
+		// Linked containers get an alias per link.
+		{[]string{"/web/db", "/db"}, "db"},
+		{[]string{"/web/db", "/app/db", "/db"}, "db"},
+		// The shortest top-level name wins.
+		{[]string{"/long_name", "/short"}, "short"},
+		// Only aliases, fall back to the first one.
+		{[]string{"/web/db"}, "web/db"},
+	} {
+		assert.Equal(tc.expected, containerName(tc.names), "test %d failed", i)
+	}
+	assert.Equal([]string{"web/db", "db"}, trimNames([]string{"/web/db", "/db"}))
+}
+
+func TestDockerTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	// A wedged daemon that never answers in time.
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	cli, err := client.NewClient("tcp://"+ts.Listener.Addr().String(), "1.25", nil, nil)
+	assert.NoError(err)
+	d, err := newDockerUtil(&Config{OperationTimeout: 50 * time.Millisecond}, cli)
+	assert.NoError(err)
+
+	_, err = d.dockerContainers()
+	assert.Equal(ErrDockerTimeout, err)
+	_, err = d.getHostname()
+	assert.Equal(ErrDockerTimeout, err)
+
+	// Timed out image lookups aren't cached so they are retried.
+	assert.Equal("sha256:abc", d.extractImageName("sha256:abc"))
+	assert.Empty(d.imageNameBySha)
+}
+
+func TestExtractImageNameNegativeTTL(t *testing.T) {
+	assert := assert.New(t)
+
+	// The image isn't available at first and gets pulled later.
+	pulled := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !pulled {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(types.ImageInspect{RepoTags: []string{"redis:latest"}})
+	}))
+	defer ts.Close()
+
+	cli, err := client.NewClient("tcp://"+ts.Listener.Addr().String(), "1.25", nil, nil)
+	assert.NoError(err)
+	d := &dockerUtil{
+		cfg:            &Config{OperationTimeout: time.Second},
+		cli:            cli,
+		imageNameBySha: make(map[string]imageNameEntry),
+	}
+
+	sha := "sha256:abc"
+	assert.Equal(sha, d.extractImageName(sha))
+	assert.True(d.imageNameBySha[sha].negative)
+
+	// The negative entry is used until it expires.
+	pulled = true
+	assert.Equal(sha, d.extractImageName(sha))
+	entry := d.imageNameBySha[sha]
+	entry.resolvedAt = entry.resolvedAt.Add(-negativeImageTTL)
+	d.imageNameBySha[sha] = entry
+	assert.Equal("redis:latest", d.extractImageName(sha))
+	assert.False(d.imageNameBySha[sha].negative)
+}
+
+func TestExtractImageNameDigest(t *testing.T) {
+	assert := assert.New(t)
+
+	ref := "quay.io/foo/bar@sha256:0123456789abcdef"
+	cli := &fakeDockerClient{images: map[string]types.ImageInspect{}}
+	d, err := newDockerUtil(&Config{}, cli)
+	assert.NoError(err)
+
+	// The digest is stripped when the image can't be inspected...
+	assert.Equal("quay.io/foo/bar", d.extractImageName(ref))
+	assert.True(d.imageNameBySha[ref].negative)
+
+	// ...and resolved to a tag once it can.
+	cli.images[ref] = types.ImageInspect{RepoTags: []string{"quay.io/foo/bar:1.2"}}
+	delete(d.imageNameBySha, ref)
+	assert.Equal("quay.io/foo/bar:1.2", d.extractImageName(ref))
+	assert.Equal("quay.io/foo/bar:1.2", d.imageNameBySha[ref].name)
+
+	// Without tag the repository is kept.
+	cli.images[ref] = types.ImageInspect{RepoDigests: []string{ref}}
+	delete(d.imageNameBySha, ref)
+	assert.Equal("quay.io/foo/bar", d.extractImageName(ref))
+
+	assert.Equal("redis:latest", d.extractImageName("redis:latest"))
+}
+
+// fakeDockerClient is a dockerClient serving canned responses.
+type fakeDockerClient struct {
+	containers []types.Container
+	inspects   map[string]types.ContainerJSON
+	images     map[string]types.ImageInspect
+	info       types.Info
+	version    types.Version
+	// listErr is returned by ContainerList if set
+	listErr      error
+	listCalls    int
+	inspectCalls int
+	// events and eventErrs are returned by Events
+	events    chan events.Message
+	eventErrs chan error
+	// stats are returned by ContainerStats
+	stats map[string]types.StatsJSON
+	// closed is set by Close
+	closed bool
+}
+
+// errNotFound satisfies the not found check from the docker client.
+type errNotFound struct{ id string }
+
+func (e errNotFound) Error() string  { return fmt.Sprintf("no such object: %s", e.id) }
+func (e errNotFound) NotFound() bool { return true }
+
+func (f *fakeDockerClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	f.listCalls++
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	if options.All {
+		return f.containers, nil
+	}
+	running := make([]types.Container, 0, len(f.containers))
+	for _, c := range f.containers {
+		if !IsStopped(c.State) {
+			running = append(running, c)
+		}
+	}
+	return running, nil
+}
+
+func (f *fakeDockerClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	f.inspectCalls++
+	i, ok := f.inspects[containerID]
+	if !ok {
+		return i, errNotFound{containerID}
+	}
+	return i, nil
+}
+
+func (f *fakeDockerClient) ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error) {
+	i, ok := f.images[imageID]
+	if !ok {
+		return i, nil, errNotFound{imageID}
+	}
+	return i, nil, nil
+}
+
+func (f *fakeDockerClient) Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	return f.events, f.eventErrs
+}
+
+func (f *fakeDockerClient) Info(ctx context.Context) (types.Info, error) {
+	return f.info, nil
+}
+
+func (f *fakeDockerClient) ServerVersion(ctx context.Context) (types.Version, error) {
+	return f.version, nil
+}
+
+func (f *fakeDockerClient) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeDockerClient) ContainerStats(ctx context.Context, containerID string, stream bool) (types.ContainerStats, error) {
+	s, ok := f.stats[containerID]
+	if !ok {
+		return types.ContainerStats{}, errNotFound{containerID}
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return types.ContainerStats{}, err
+	}
+	return types.ContainerStats{Body: ioutil.NopCloser(bytes.NewReader(b)), OSType: "linux"}, nil
+}
+
+func TestDockerContainersWithClient(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{
+		containers: []types.Container{
+			{ID: "1", Names: []string{"/redis"}, Image: "sha256:aaa", ImageID: "sha256:aaa", State: "running", Status: "Up 5 seconds (health: starting)"},
+			{
+				ID: "2", Names: []string{"/web"}, Image: "sha256:bbb", ImageID: "sha256:bbb", State: "running", Status: "Up about an hour",
+				Command: "gunicorn app:app",
+				Labels:  map[string]string{"com.docker.compose.project": "shop", "com.docker.compose.service": "web"},
+			},
+			{ID: "3", Names: []string{"/pause"}, Image: "gcr.io/google_containers/pause-amd64:3.0", State: "running", Status: "Up 2 days"},
+		},
+		inspects: map[string]types.ContainerJSON{
+			"1": types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Pid: 1}},
+				Config: &container.Config{
+					Healthcheck: &container.HealthConfig{Test: []string{"CMD", "true"}, Retries: 3},
+				},
+			},
+		},
+		images: map[string]types.ImageInspect{
+			"sha256:aaa": types.ImageInspect{RepoTags: []string{"redis:latest"}},
+		},
+	}
+
+	for i, tc := range []struct {
+		cfg      *Config
+		expected []*Container
+	}{
+		{
+			cfg: &Config{},
+			expected: []*Container{
+				{Type: "Docker", ID: "1", Name: "redis", Names: []string{"redis"}, rawName: "/redis", Image: "redis:latest", ImageID: "sha256:aaa", ImageTag: "latest", State: "running", Health: "starting", ExitCode: -1},
+				{
+					Type: "Docker", ID: "2", Name: "web", Names: []string{"web"}, rawName: "/web", Image: "sha256:bbb", ImageID: "sha256:bbb", State: "running", ExitCode: -1,
+					Labels:         map[string]string{"com.docker.compose.project": "shop", "com.docker.compose.service": "web"},
+					ComposeProject: "shop",
+					ComposeService: "web",
+					Command:        "gunicorn app:app",
+				},
+				{Type: "Docker", ID: "3", Name: "pause", Names: []string{"pause"}, rawName: "/pause", Image: "gcr.io/google_containers/pause-amd64:3.0", ImageTag: "3.0", State: "running", ExitCode: -1},
+			},
+		},
+		{
+			cfg: &Config{
+				Blacklist:                []string{"image:gcr.io/google_containers/pause.*", "name:web"},
+				CollectHealthcheckConfig: true,
+			},
+			expected: []*Container{
+				{
+					Type: "Docker", ID: "1", Name: "redis", Names: []string{"redis"}, rawName: "/redis", Image: "redis:latest", ImageID: "sha256:aaa", ImageTag: "latest", State: "running", Health: "starting", ExitCode: -1,
+					HealthcheckConfig: &HealthcheckConfig{Test: []string{"CMD", "true"}, Retries: 3},
+				},
+			},
+		},
+		{
+			cfg: &Config{
+				Blacklist:    []string{"image:gcr.io/google_containers/pause.*", "name:redis"},
+				LabelsAsTags: []string{"com.docker.compose.service", "team"},
+			},
+			expected: []*Container{
+				{
+					Type: "Docker", ID: "2", Name: "web", Names: []string{"web"}, rawName: "/web", Image: "sha256:bbb", ImageID: "sha256:bbb", State: "running", ExitCode: -1,
+					Labels:         map[string]string{"com.docker.compose.project": "shop", "com.docker.compose.service": "web"},
+					ComposeProject: "shop",
+					ComposeService: "web",
+					Command:        "gunicorn app:app",
+					Tags:           []string{"com.docker.compose.service:web"},
+				},
+			},
+		},
+	} {
+		d, err := newDockerUtil(tc.cfg, cli)
+		assert.NoError(err)
+		containers, err := d.dockerContainers()
+		assert.NoError(err)
+		assert.Equal(tc.expected, containers, "case %d", i)
+	}
+}
+
+func TestDockerContainersNameNormalization(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{
+		containers: []types.Container{
+			{ID: "1", Names: []string{"/api-x7k2p9"}, State: "running"},
+			{ID: "2", Names: []string{"/api-q4m8z1"}, State: "running"},
+			{ID: "3", Names: []string{"/db"}, State: "running"},
+		},
+	}
+
+	// The filters match the raw names.
+	d, err := newDockerUtil(&Config{
+		NameNormalization: "-[a-z0-9]{6}$",
+		Blacklist:         []string{"name:api-q4m8z1"},
+	}, cli)
+	assert.NoError(err)
+	containers, err := d.dockerContainers()
+	assert.NoError(err)
+	names := make([]string, 0, len(containers))
+	for _, c := range containers {
+		names = append(names, c.Name)
+	}
+	assert.Equal([]string{"api", "db"}, names)
+	assert.Equal([]string{"api-x7k2p9"}, containers[0].Names)
+
+	// The replacement may refer to the groups.
+	d, err = newDockerUtil(&Config{NameNormalization: "^(.+)-[a-z0-9]{6}$", NameReplacement: "${1}-replica"}, cli)
+	assert.NoError(err)
+	containers, err = d.dockerContainers()
+	assert.NoError(err)
+	assert.Equal("api-replica", containers[0].Name)
+	assert.Equal("db", containers[2].Name)
+
+	_, err = newDockerUtil(&Config{NameNormalization: "-[a-z"}, cli)
+	assert.Error(err)
+}
+
+func TestIsPauseImage(t *testing.T) {
+	for _, tc := range []struct {
+		image string
+		pause bool
+	}{
+		{"gcr.io/google_containers/pause-amd64:3.0", true},
+		{"eu.gcr.io/google_containers/pause:2.0", true},
+		{"k8s.gcr.io/pause:3.1", true},
+		{"registry.k8s.io/pause:3.9", true},
+		{"registry.k8s.io/pause@sha256:7031c1b283388d2c2e09b57badb803c05ebed362dc88d84b480cc47f72a21097", true},
+		{"rancher/pause:3.1", true},
+		{"docker.io/rancher/mirrored-pause:3.6", true},
+		{"rancher/mirrored-pause-amd64", true},
+		{"redis:latest", false},
+		{"example.com/pause:1.0", false},
+		{"registry.k8s.io/pause-tester:1.0", true},
+		{"registry.k8s.io/pauser:1.0", false},
+		{"rancher/mirrored-pausefoo:1.0", false},
+	} {
+		assert.Equal(t, tc.pause, isPauseImage(tc.image), tc.image)
+	}
+}
+
+func TestDockerContainersExcludePause(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{
+		containers: []types.Container{
+			{ID: "1", Names: []string{"/k8s_POD_web"}, Image: "registry.k8s.io/pause:3.9", State: "running"},
+			{ID: "2", Names: []string{"/k8s_web"}, Image: "nginx:latest", State: "running"},
+		},
+	}
+
+	// The pause containers are excluded even when whitelisted.
+	d, err := newDockerUtil(&Config{ExcludePauseContainer: true, Whitelist: []string{"name:k8s_.*"}}, cli)
+	assert.NoError(err)
+	containers, err := d.dockerContainers()
+	assert.NoError(err)
+	if assert.Len(containers, 1) {
+		assert.Equal("2", containers[0].ID)
+	}
+
+	d, err = newDockerUtil(&Config{}, cli)
+	assert.NoError(err)
+	containers, err = d.dockerContainers()
+	assert.NoError(err)
+	assert.Len(containers, 2)
+}
+
+func TestRemoteDockerHost(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(types.Version{APIVersion: "1.25"})
+	}))
+	defer ts.Close()
+
+	os.Setenv("DOCKER_SOCKET_PATH", "/tmp/test-remote-docker-host/docker.sock")
+	defer os.Unsetenv("DOCKER_SOCKET_PATH")
+	defer os.Unsetenv("DOCKER_HOST")
+
+	// A missing local socket means docker isn't available...
+	os.Unsetenv("DOCKER_HOST")
+	assert.False(IsAvailable())
+
+	// ...unless we connect to a remote daemon.
+	os.Setenv("DOCKER_HOST", "tcp://"+ts.Listener.Addr().String())
+	assert.True(IsAvailable())
+	cli, _, err := connectToDocker(false)
+	assert.NoError(err)
+	assert.Equal("1.25", cli.ClientVersion())
+	// The detected version isn't pinned for the next connections.
+	assert.Equal("", os.Getenv("DOCKER_API_VERSION"))
+
+	// Invalid certificates are reported rather than ignored.
+	os.Setenv("DOCKER_CERT_PATH", "/tmp/test-remote-docker-host/certs")
+	defer os.Unsetenv("DOCKER_CERT_PATH")
+	_, _, err = connectToDocker(false)
+	assert.Error(err)
+	assert.NotEqual(ErrDockerNotAvailable, err)
+}
+
+func TestCgroupContainersWithoutMounts(t *testing.T) {
+	assert := assert.New(t)
+
+	// A proc without the mounts file.
+	tmp, err := ioutil.TempDir("", "test-cgroup-containers-without-mounts")
+	assert.NoError(err)
+	defer os.RemoveAll(tmp)
+	os.Setenv("HOST_PROC", tmp)
+	defer os.Setenv("HOST_PROC", "/proc")
+
+	cgs, err := CgroupsForPids("", []int32{1})
+	assert.NoError(err)
+	assert.Empty(cgs)
+
+	list := func() ([]*Container, error) {
+		return []*Container{{Type: "Docker", ID: "1", Name: "redis", Image: "redis:latest"}}, nil
+	}
+	netStat := &NetworkStat{BytesRcvd: 10, PacketsRcvd: 1}
+	network := func(*Container) (*NetworkStat, error) { return netStat, nil }
+
+	containers, err := cgroupContainers("test.containers.without.mounts", time.Second, "", 0, 0, list, network, nil, nil)
+	assert.NoError(err)
+	if assert.Len(containers, 1) {
+		c := containers[0]
+		assert.Equal("redis", c.Name)
+		assert.Equal(NullContainer.CPU, c.CPU)
+		assert.Equal(NullContainer.Memory, c.Memory)
+		assert.Equal(netStat, c.Network)
+	}
+}
+
+func TestMetadataContainers(t *testing.T) {
+	assert := assert.New(t)
+	cache.Delete("test.metadata.containers")
+	defer cache.Delete("test.metadata.containers")
+
+	listed := 0
+	list := func() ([]*Container, error) {
+		listed++
+		return []*Container{
+			{Type: "Docker", ID: "1", Name: "redis", State: "running", Created: time.Now().Unix() - 60},
+			{Type: "Docker", ID: "2", Name: "job", State: "exited"},
+		}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		containers, err := metadataContainers("test.metadata.containers", time.Minute, list)
+		assert.NoError(err)
+		if assert.Len(containers, 2) {
+			c := containers[0]
+			assert.Equal("redis", c.Name)
+			assert.Equal(NullContainer.CPU, c.CPU)
+			assert.Equal(NullContainer.Memory, c.Memory)
+			assert.Equal(NullContainer.IO, c.IO)
+			assert.Equal(NullContainer.Network, c.Network)
+			assert.InDelta(60, c.Uptime, 2)
+			assert.Equal(int64(0), containers[1].Uptime)
+		}
+	}
+	// The second collection is served from the cache.
+	assert.Equal(1, listed)
+}
+
+func TestCgroupContainersDeadline(t *testing.T) {
+	assert := assert.New(t)
+
+	// A proc without the mounts file so the network stats are read.
+	tmp, err := ioutil.TempDir("", "test-cgroup-containers-deadline")
+	assert.NoError(err)
+	defer os.RemoveAll(tmp)
+	os.Setenv("HOST_PROC", tmp)
+	defer os.Setenv("HOST_PROC", "/proc")
+
+	list := func() ([]*Container, error) {
+		var containers []*Container
+		for i := 0; i < 5; i++ {
+			containers = append(containers, &Container{Type: "Docker", ID: strconv.Itoa(i), State: "running"})
+		}
+		return containers, nil
+	}
+	// A slow host where the stats of a container take 20ms.
+	now := time.Now()
+	defer func() { deadlineNow = time.Now }()
+	deadlineNow = func() time.Time { return now }
+	network := func(*Container) (*NetworkStat, error) {
+		now = now.Add(20 * time.Millisecond)
+		return &NetworkStat{}, nil
+	}
+	stats := &fakeStatsClient{counts: make(map[string]int64), gauges: make(map[string]int)}
+
+	containers, err := cgroupContainers("test.containers.deadline", time.Second, "", 1, 30*time.Millisecond, list, network, nil, stats)
+	assert.NoError(err)
+	// The containers collected before the deadline are returned, the
+	// second one starting at 20ms and the third one after the deadline.
+	assert.Len(containers, 2)
+	assert.Equal(int64(1), stats.counts["datadog.process.containers.deadline_hit"])
+
+	stats.counts = make(map[string]int64)
+	containers, err = cgroupContainers("test.containers.deadline", time.Second, "", 1, 0, list, network, nil, stats)
+	assert.NoError(err)
+	assert.Len(containers, 5)
+	assert.Empty(stats.counts["datadog.process.containers.deadline_hit"])
+}
+
+func TestDockerContainersHealthFailingStreak(t *testing.T) {
+	assert := assert.New(t)
+
+	inspect := func(status string, streak int) types.ContainerJSON {
+		return types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				State: &types.ContainerState{
+					Pid:    1,
+					Health: &types.Health{Status: status, FailingStreak: streak},
+				},
+			},
+		}
+	}
+	cli := &fakeDockerClient{
+		containers: []types.Container{{ID: "1", Names: []string{"/redis"}, State: "running", Status: "Up 1 second (health: starting)"}},
+		inspects:   map[string]types.ContainerJSON{"1": inspect("starting", 0)},
+	}
+	d, err := newDockerUtil(&Config{CollectHealthcheckConfig: true}, cli)
+	assert.NoError(err)
+	streak := func() int32 {
+		containers, err := d.dockerContainers()
+		assert.NoError(err)
+		if !assert.Len(containers, 1) {
+			return -1
+		}
+		return containers[0].HealthFailingStreak
+	}
+
+	assert.Equal(int32(0), streak())
+
+	// The details are refreshed when the health changes...
+	cli.containers[0].Status = "Up 2 minutes (unhealthy)"
+	cli.inspects["1"] = inspect("unhealthy", 3)
+	assert.Equal(int32(3), streak())
+
+	// ...and while the container stays unhealthy.
+	cli.inspects["1"] = inspect("unhealthy", 4)
+	assert.Equal(int32(4), streak())
+
+	// Healthy containers aren't inspected again once they recovered.
+	cli.containers[0].Status = "Up 3 minutes (healthy)"
+	cli.inspects["1"] = inspect("healthy", 0)
+	assert.Equal(int32(0), streak())
+	cli.inspects["1"] = inspect("healthy", 1)
+	assert.Equal(int32(0), streak())
+}
+
+func TestDockerContainersIncludeStopped(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{
+		containers: []types.Container{
+			{ID: "1", Names: []string{"/redis"}, State: "running"},
+			{ID: "2", Names: []string{"/migrate"}, State: "exited"},
+		},
+		inspects: map[string]types.ContainerJSON{
+			"1": {ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Pid: 1}}},
+			"2": {ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{ExitCode: 137, OOMKilled: true}}},
+		},
+	}
+
+	d, err := newDockerUtil(&Config{CollectNetwork: true}, cli)
+	assert.NoError(err)
+	containers, err := d.dockerContainers()
+	assert.NoError(err)
+	if assert.Len(containers, 1) {
+		assert.Equal("redis", containers[0].Name)
+	}
+
+	// The exited container isn't inspected for its network.
+	d, err = newDockerUtil(&Config{CollectNetwork: true, IncludeStopped: true}, cli)
+	assert.NoError(err)
+	containers, err = d.dockerContainers()
+	assert.NoError(err)
+	if assert.Len(containers, 2) {
+		assert.Equal(int32(-1), containers[0].ExitCode)
+		assert.Equal(int32(137), containers[1].ExitCode)
+		assert.True(containers[1].OOMKilled)
+	}
+	assert.NotContains(d.networkMappings, "2")
+
+	// The exit status is inspected again once the container ran again.
+	cli.containers[1].State = "running"
+	cli.inspects["2"] = types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Pid: 2}}}
+	restarted, err := d.dockerContainers()
+	assert.NoError(err)
+	if assert.Len(restarted, 2) {
+		assert.Equal(int32(-1), restarted[1].ExitCode)
+		assert.False(restarted[1].OOMKilled)
+	}
+	cli.containers[1].State = "exited"
+	cli.inspects["2"] = types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{ExitCode: 1}}}
+	restarted, err = d.dockerContainers()
+	assert.NoError(err)
+	if assert.Len(restarted, 2) {
+		assert.Equal(int32(1), restarted[1].ExitCode)
+		assert.False(restarted[1].OOMKilled)
+	}
+
+	// Stopped containers have no cgroup but are still reported, unlike
+	// running ones.
+	containers[0].cgroup = nil
+	list := func() ([]*Container, error) { return containers, nil }
+	network := func(*Container) (*NetworkStat, error) { return &NetworkStat{BytesRcvd: 10}, nil }
+	containers, err = cgroupContainers("test.containers.include.stopped", time.Second, "", 0, 0, list, network, nil, nil)
+	assert.NoError(err)
+	if assert.Len(containers, 1) {
+		c := containers[0]
+		assert.Equal("migrate", c.Name)
+		assert.Equal("exited", c.State)
+		assert.Equal(NullContainer.CPU, c.CPU)
+		assert.Equal(NullContainer.Memory, c.Memory)
+		assert.Equal(NullContainer.Network, c.Network)
+	}
+}
+
+func TestDockerContainersMaxInspects(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{inspects: make(map[string]types.ContainerJSON)}
+	for _, id := range []string{"1", "2", "3"} {
+		cli.containers = append(cli.containers, types.Container{ID: id, Names: []string{"/" + id}, State: "running"})
+		cli.inspects[id] = types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+			State:      &types.ContainerState{Pid: 1},
+			HostConfig: &container.HostConfig{Privileged: true},
+		}}
+	}
+	stats := &fakeStatsClient{counts: make(map[string]int64), gauges: make(map[string]int)}
+	d, err := newDockerUtil(&Config{CollectRestartCount: true, MaxInspectsPerCycle: 2, Statsd: stats}, cli)
+	assert.NoError(err)
+
+	// The last container isn't inspected yet.
+	containers, err := d.dockerContainers()
+	assert.NoError(err)
+	assert.Len(containers, 3)
+	assert.Equal(2, cli.inspectCalls)
+	assert.True(containers[0].Privileged)
+	assert.True(containers[1].Privileged)
+	assert.False(containers[2].Privileged)
+	assert.Equal(int64(1), stats.counts["datadog.process.docker.inspects_skipped"])
+
+	// It is on the next collection, the others are cached.
+	containers, err = d.dockerContainers()
+	assert.NoError(err)
+	assert.Equal(3, cli.inspectCalls)
+	assert.True(containers[2].Privileged)
+	assert.Equal(int64(1), stats.counts["datadog.process.docker.inspects_skipped"])
+}
+
+func TestDockerContainersRestartCount(t *testing.T) {
+	assert := assert.New(t)
+
+	inspect := func(restarts int) types.ContainerJSON {
+		return types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				State:        &types.ContainerState{Pid: 1},
+				RestartCount: restarts,
+			},
+		}
+	}
+	cli := &fakeDockerClient{
+		containers: []types.Container{{ID: "1", Names: []string{"/redis"}, State: "running"}},
+		inspects:   map[string]types.ContainerJSON{"1": inspect(1)},
+	}
+	d, err := newDockerUtil(&Config{CollectRestartCount: true}, cli)
+	assert.NoError(err)
+
+	containers, err := d.dockerContainers()
+	assert.NoError(err)
+	assert.Equal(int32(1), containers[0].RestartCount)
+
+	// The details are cached while the state doesn't change...
+	cli.inspects["1"] = inspect(2)
+	containers, err = d.dockerContainers()
+	assert.NoError(err)
+	assert.Equal(int32(1), containers[0].RestartCount)
+
+	// ...and refreshed once it does.
+	cli.containers[0].State = "restarting"
+	containers, err = d.dockerContainers()
+	assert.NoError(err)
+	assert.Equal(int32(2), containers[0].RestartCount)
+}
+
+func TestDockerContainersBackoff(t *testing.T) {
+	assert := assert.New(t)
+
+	cache.Delete(containersCacheKey)
+	defer cache.Delete(containersCacheKey)
+	cli := &fakeDockerClient{listErr: fmt.Errorf("daemon is down")}
+	d, err := newDockerUtil(&Config{}, cli)
+	assert.NoError(err)
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	// We keep calling the daemon until we reach the threshold...
+	for i := 0; i < backoffThreshold; i++ {
+		_, err = d.containers()
+		assert.True(IsCollectionError(err, ErrListContainers))
+		assert.EqualError(err, "error listing containers: daemon is down")
+	}
+	assert.Equal(backoffThreshold, cli.listCalls)
+
+	// ...and then back off.
+	_, err = d.containers()
+	assert.NoError(err)
+	assert.Equal(backoffThreshold, cli.listCalls)
+	assert.Equal(now.Add(backoffInitial), d.backoffUntil)
+	now = now.Add(backoffInitial - time.Second)
+	_, err = d.containers()
+	assert.NoError(err)
+	assert.Equal(backoffThreshold, cli.listCalls)
+
+	// A success once the backoff expires resets it.
+	cli.listErr = nil
+	now = now.Add(time.Second)
+	_, err = d.containers()
+	assert.NoError(err)
+	assert.Equal(backoffThreshold+1, cli.listCalls)
+	assert.Equal(0, d.failures)
+	assert.True(d.backoffUntil.IsZero())
+}
+
+func TestDockerContainersLocalFailures(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{}
+	d, err := newDockerUtil(&Config{}, cli)
+	assert.NoError(err)
+	d.endpoint = "test.local.failures"
+	last := []*Container{{ID: "1"}}
+	d.lastContainers = last
+
+	// The processes of the host can't be listed.
+	hostProc, hasHostProc := os.LookupEnv("HOST_PROC")
+	os.Setenv("HOST_PROC", filepath.Join(os.TempDir(), "test-local-failures-missing"))
+	defer func() {
+		if hasHostProc {
+			os.Setenv("HOST_PROC", hostProc)
+		} else {
+			os.Unsetenv("HOST_PROC")
+		}
+	}()
+
+	// The last containers are served for a few collections...
+	for i := 0; i < maxLocalFailures; i++ {
+		containers, err := d.containers()
+		assert.NoError(err)
+		assert.Equal(last, containers)
+	}
+	// ...and then the error is returned, without backing off.
+	_, err = d.containers()
+	assert.True(IsCollectionError(err, ErrPidList))
+	assert.Equal(0, d.failures)
+	assert.Equal(0, cli.listCalls)
+}
+
+func TestCollectionError(t *testing.T) {
+	assert := assert.New(t)
+
+	cause := fmt.Errorf("open /proc/12/cgroup: no such file or directory")
+	err := error(&CollectionError{Kind: ErrCgroupParse, Err: cause})
+	assert.True(IsCollectionError(err, ErrCgroupParse))
+	assert.False(IsCollectionError(err, ErrListContainers))
+	assert.False(IsCollectionError(cause, ErrCgroupParse))
+	assert.True(isLocalCollectionError(err))
+	assert.False(isLocalCollectionError(&CollectionError{Kind: ErrListContainers, Err: cause}))
+
+	// The logs are deduplicated by kind rather than by message.
+	assert.Equal(errorKind(err), errorKind(&CollectionError{Kind: ErrCgroupParse, Err: fmt.Errorf("other")}))
+	assert.Equal(ErrDockerTimeout.Error(), errorKind(ErrDockerTimeout))
+	assert.Equal("daemon is down", errorKind(fmt.Errorf("daemon is down")))
+}
+
+// fakeStatsClient records the counters and the number of gauges sent to statsd.
+type fakeStatsClient struct {
+	counts map[string]int64
+	gauges map[string]int
+}
+
+func (f *fakeStatsClient) Count(name string, value int64, tags []string, rate float64) error {
+	f.counts[name] += value
+	return nil
+}
+
+func (f *fakeStatsClient) Gauge(name string, value float64, tags []string, rate float64) error {
+	f.gauges[name]++
+	return nil
+}
+
+func TestCapContainers(t *testing.T) {
+	assert := assert.New(t)
+
+	ctr := func(id string, user, system uint64) *Container {
+		return &Container{ID: id, CPU: &CgroupTimesStat{User: user, System: system}}
+	}
+	ids := func(containers []*Container) []string {
+		ret := make([]string, 0, len(containers))
+		for _, c := range containers {
+			ret = append(ret, c.ID)
+		}
+		return ret
+	}
+	containers := []*Container{ctr("busy", 900, 100), ctr("idle", 5000, 0), ctr("new", 300, 0), {ID: "nostats"}}
+	last := []*Container{ctr("busy", 100, 50), ctr("idle", 4990, 0)}
+
+	stats := &fakeStatsClient{counts: make(map[string]int64)}
+	assert.Equal([]string{"busy", "new"}, ids(capContainers(containers, last, 2, stats)))
+	assert.Equal(int64(2), stats.counts["datadog.process.containers.truncated"])
+
+	// Without a previous collection the CPU time since start is used.
+	assert.Equal([]string{"idle"}, ids(capContainers(containers, nil, 1, nil)))
+
+	assert.Len(capContainers(containers, last, 0, stats), 4)
+	assert.Len(capContainers(containers, last, 4, stats), 4)
+	assert.Equal(int64(2), stats.counts["datadog.process.containers.truncated"])
+}
+
+func TestDockerContainersCacheMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	tmp, err := ioutil.TempDir("", "test-docker-containers-cache-metrics")
+	assert.NoError(err)
+	defer os.RemoveAll(tmp)
+	os.Setenv("HOST_PROC", tmp)
+	defer os.Setenv("HOST_PROC", "/proc")
+
+	stats := &fakeStatsClient{counts: make(map[string]int64), gauges: make(map[string]int)}
+	cli := &fakeDockerClient{}
+	d, err := newDockerUtil(&Config{CacheDuration: time.Minute, Statsd: stats}, cli)
+	assert.NoError(err)
+
+	cache.Delete(containersCacheKey)
+	for i := 0; i < 3; i++ {
+		_, err = d.containers()
+		assert.NoError(err)
+	}
+	assert.Equal(1, cli.listCalls)
+	assert.Equal(map[string]int64{
+		"datadog.process.docker.cache.miss": 1,
+		"datadog.process.docker.cache.hit":  2,
+	}, stats.counts)
+	// The cgroups are only parsed on cache misses.
+	assert.Equal(map[string]int{
+		"datadog.process.docker.cgroup_parse_ms": 1,
+		"datadog.process.docker.stat_collect_ms": 3,
+	}, stats.gauges)
+}
+
+func TestFilterMinUptime(t *testing.T) {
+	containers := func() []*Container {
+		return []*Container{
+			{ID: "job", State: "running", Uptime: 5},
+			{ID: "web", State: "running", Uptime: 3600},
+			{ID: "edge", State: "running", Uptime: 60},
+			{ID: "done", State: "exited"},
+		}
+	}
+	ids := func(containers []*Container) []string {
+		ret := make([]string, 0, len(containers))
+		for _, c := range containers {
+			ret = append(ret, c.ID)
+		}
+		return ret
+	}
+
+	assert.Equal(t, []string{"job", "web", "edge", "done"}, ids(filterMinUptime(containers(), 0)))
+	assert.Equal(t, []string{"web", "edge", "done"}, ids(filterMinUptime(containers(), time.Minute)))
+	assert.Equal(t, []string{"web", "done"}, ids(filterMinUptime(containers(), time.Minute+time.Millisecond)))
+
+	// The containers without cgroups get their uptime from the metadata.
+	network := func(*Container) (*NetworkStat, error) { return &NetworkStat{}, nil }
+	c := containerStats(&Container{ID: "nocgroup", State: "running", Created: time.Now().Unix() - 3600}, false, network, nil)
+	assert.InDelta(t, 3600, c.Uptime, 5)
+	assert.Equal(t, []string{"nocgroup"}, ids(filterMinUptime([]*Container{c}, time.Minute)))
+}
+
+func TestDockerContainersCreatedWindow(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{containers: []types.Container{
+		{ID: "old", Names: []string{"/old"}, State: "running", Created: 1000},
+		{ID: "in", Names: []string{"/in"}, State: "running", Created: 2000},
+		{ID: "new", Names: []string{"/new"}, State: "running", Created: 3000},
+	}}
+	cfg := &Config{CreatedAfter: time.Unix(1500, 0), CreatedBefore: time.Unix(2000, 0)}
+	d, err := newDockerUtil(cfg, cli)
+	assert.NoError(err)
+
+	containers, err := d.dockerContainers()
+	assert.NoError(err)
+	if assert.Len(containers, 1) {
+		assert.Equal("in", containers[0].ID)
+	}
+
+	// The zero time leaves the window unbounded.
+	cfg.CreatedBefore = time.Time{}
+	containers, err = d.dockerContainers()
+	assert.NoError(err)
+	assert.Len(containers, 2)
+}
+
+func TestBackoffInterval(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(backoffInitial, backoffInterval(backoffThreshold))
+	assert.Equal(2*backoffInitial, backoffInterval(backoffThreshold+1))
+	assert.Equal(4*backoffInitial, backoffInterval(backoffThreshold+2))
+	assert.Equal(backoffMax, backoffInterval(backoffThreshold+100))
+}
+
+func TestDockerContainersInvalidation(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{
+		containers: []types.Container{{ID: "1", Names: []string{"/redis"}, State: "running"}},
+	}
+	d, err := newDockerUtil(&Config{}, cli)
+	assert.NoError(err)
+	now := d.lastInvalidate
+	d.now = func() time.Time { return now }
+
+	// invalidated returns true if the data of a removed container was dropped.
+	invalidated := func() bool {
+		d.networkMappings["gone"] = []dockerNetwork{hostNetwork}
+		_, err := d.dockerContainers()
+		assert.NoError(err)
+		_, ok := d.networkMappings["gone"]
+		return !ok
+	}
+
+	assert.False(invalidated())
+	now = now.Add(d.cfg.InvalidationInterval / 2)
+	assert.False(invalidated())
+	now = now.Add(d.cfg.InvalidationInterval / 2)
+	assert.True(invalidated())
+	// Only once per interval.
+	assert.False(invalidated())
+	now = now.Add(d.cfg.InvalidationInterval - time.Second)
+	assert.False(invalidated())
+	now = now.Add(time.Second)
+	assert.True(invalidated())
+
+	// The interval is per instance.
+	d, err = newDockerUtil(&Config{InvalidationInterval: time.Minute}, cli)
+	assert.NoError(err)
+	now = d.lastInvalidate
+	d.now = func() time.Time { return now }
+	now = now.Add(time.Minute - time.Second)
+	assert.False(invalidated())
+	now = now.Add(time.Second)
+	assert.True(invalidated())
+}
+
+func TestDockerContainersAPIVersionMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	mismatch := fmt.Errorf("Error response from daemon: client version 1.41 is too new. Maximum supported API version is 1.40")
+	oldCli := &fakeDockerClient{listErr: mismatch}
+	newCli := &fakeDockerClient{
+		containers: []types.Container{{ID: "1", Names: []string{"/redis"}, State: "running"}},
+	}
+	d, err := newDockerUtil(&Config{}, oldCli)
+	assert.NoError(err)
+	now := time.Now()
+	d.now = func() time.Time { return now }
+	reconnects := 0
+	d.reconnect = func() (dockerClient, error) {
+		reconnects++
+		return newCli, nil
+	}
+
+	// The client is rebuilt and the list retried.
+	containers, err := d.dockerContainers()
+	assert.NoError(err)
+	assert.Len(containers, 1)
+	assert.Equal(1, reconnects)
+	assert.Equal(newCli, d.client())
+	assert.True(oldCli.closed)
+	assert.False(newCli.closed)
+
+	// At most once per invalidation interval.
+	newCli.listErr = mismatch
+	_, err = d.dockerContainers()
+	assert.Error(err)
+	assert.Equal(1, reconnects)
+	now = now.Add(d.cfg.InvalidationInterval)
+	_, err = d.dockerContainers()
+	assert.Error(err)
+	assert.Equal(2, reconnects)
+	// The client in use isn't closed.
+	assert.False(newCli.closed)
+
+	// Other errors don't rebuild the client.
+	now = now.Add(d.cfg.InvalidationInterval)
+	newCli.listErr = fmt.Errorf("daemon is down")
+	_, err = d.dockerContainers()
+	assert.Error(err)
+	assert.Equal(2, reconnects)
+}
+
+func TestGetContainer(t *testing.T) {
+	assert := assert.New(t)
+
+	tmp, err := ioutil.TempDir("", "test-get-container")
+	assert.NoError(err)
+	defer os.RemoveAll(tmp)
+	os.Setenv("HOST_PROC", tmp)
+	defer os.Setenv("HOST_PROC", "/proc")
+	assert.NoError(ioutil.WriteFile(filepath.Join(tmp, "mounts"), []byte("cgroup "+tmp+"/memory cgroup rw,memory 0 0\n"), 0644))
+
+	cid := "a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419"
+	assert.NoError(os.MkdirAll(filepath.Join(tmp, "10"), 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(tmp, "10", "cgroup"), []byte("6:memory:/docker/"+cid+"\n"), 0644))
+	assert.NoError(os.MkdirAll(filepath.Join(tmp, "memory", "docker", cid), 0755))
+	for name, contents := range map[string]string{"memory.stat": "rss 1024\n", "memory.usage_in_bytes": "2048\n"} {
+		assert.NoError(ioutil.WriteFile(filepath.Join(tmp, "memory", "docker", cid, name), []byte(contents), 0644))
+	}
+
+	inspect := func(id, name, status string, pid int) types.ContainerJSON {
+		return types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:      id,
+				Name:    name,
+				Image:   "redis:latest",
+				Created: "2018-01-02T15:04:05.999999999Z",
+				State:   &types.ContainerState{Status: status, Pid: pid},
+				Path:    "redis-server",
+				Args:    []string{"--appendonly", "yes"},
+			},
+			Config: &container.Config{Labels: map[string]string{"app": "redis"}},
+		}
+	}
+	cli := &fakeDockerClient{
+		inspects: map[string]types.ContainerJSON{
+			cid:    inspect(cid, "/redis", "running", 10),
+			"gone": inspect("gone", "/migrate", "exited", 0),
+		},
+	}
+	d, err := newDockerUtil(&Config{}, cli)
+	assert.NoError(err)
+
+	c, err := d.container(cid)
+	assert.NoError(err)
+	assert.Equal("redis", c.Name)
+	assert.Equal("running", c.State)
+	assert.Equal(int64(1514905445), c.Created)
+	assert.Equal(map[string]string{"app": "redis"}, c.Labels)
+	assert.Equal([]int32{10}, c.Pids)
+	// Without the pids controller the processes of the cgroup are counted.
+	assert.Equal(uint64(1), c.PidsCurrent)
+	assert.Equal("redis-server --appendonly yes", c.Command)
+	assert.Equal(filepath.Join(tmp, "memory", "docker", cid), c.CgroupPath)
+	assert.Equal(uint64(1024), c.Memory.RSS)
+
+	// Stopped containers are reported without stats.
+	c, err = d.container("gone")
+	assert.NoError(err)
+	assert.Equal("exited", c.State)
+	assert.Equal(NullContainer.Memory, c.Memory)
+
+	_, err = d.container("missing")
+	assert.Equal(ErrContainerNotFound, err)
+
+	// Short IDs are resolved against the known containers.
+	d.lastContainers = []*Container{{ID: cid}, {ID: "gone"}}
+	c, err = d.container(cid[:12])
+	assert.NoError(err)
+	assert.Equal(cid, c.ID)
+	d.lastContainers = append(d.lastContainers, &Container{ID: cid[:12] + "ffff"})
+	_, err = d.container(cid[:12])
+	assert.Equal(ErrAmbiguousContainerID, err)
+}
+
+func TestGetCollector(t *testing.T) {
+	assert := assert.New(t)
+	defer func(d *dockerUtil, c Collector) { globalDockerUtil, globalCollector = d, c }(globalDockerUtil, globalCollector)
+
+	globalCollector = nil
+	containers, err := CollectContainers(GetCollector())
+	assert.NoError(err)
+	assert.Nil(containers)
+
+	assert.NoError(InitDockerUtilWithClient(&Config{}, &fakeDockerClient{}))
+	assert.Equal(globalDockerUtil, GetCollector())
+}
+
+func TestCloseDockerUtil(t *testing.T) {
+	assert := assert.New(t)
+	defer func(d *dockerUtil, c Collector) { globalDockerUtil, globalCollector = d, c }(globalDockerUtil, globalCollector)
+	globalDockerUtil, globalCollector = nil, nil
+
+	assert.NoError(CloseDockerUtil())
+
+	first := &fakeDockerClient{}
+	assert.NoError(InitDockerUtilWithClient(&Config{WatchEvents: true}, first))
+	assert.NoError(CloseDockerUtil())
+	assert.True(first.closed)
+	assert.Nil(globalDockerUtil)
+	assert.Nil(GetCollector())
+
+	second := &fakeDockerClient{info: types.Info{Name: "docker-host"}}
+	assert.NoError(InitDockerUtilWithClient(&Config{WatchEvents: true}, second))
+	hostname, err := GetHostname()
+	assert.NoError(err)
+	assert.Equal("docker-host", hostname)
+
+	// Initializing again releases the previous client.
+	assert.NoError(InitDockerUtilWithClient(&Config{}, &fakeDockerClient{}))
+	assert.True(second.closed)
+	assert.NoError(CloseDockerUtil())
+}
+
+func TestResolveContainerID(t *testing.T) {
+	ids := map[string]struct{}{"abcdef012345": {}, "abc999": {}, "abcdef": {}}
+	for _, tc := range []struct {
+		id, expected string
+		err          error
+	}{
+		{"abcdef012345", "abcdef012345", nil},
+		{"abcdef0", "abcdef012345", nil},
+		// An exact match wins over the longer IDs with this prefix.
+		{"abcdef", "abcdef", nil},
+		{"abc", "", ErrAmbiguousContainerID},
+		// Unknown IDs are left for the daemon to resolve.
+		{"123", "123", nil},
+		{"", "", nil},
+	} {
+		id, err := ResolveContainerID(tc.id, ids)
+		assert.Equal(t, tc.expected, id, tc.id)
+		assert.Equal(t, tc.err, err, tc.id)
+	}
+}
+
+func TestDockerSockets(t *testing.T) {
+	assert := assert.New(t)
+
+	os.Unsetenv("DOCKER_SOCKET_PATH")
+	os.Unsetenv("XDG_RUNTIME_DIR")
+	defer os.Unsetenv("XDG_RUNTIME_DIR")
+	assert.Equal([]dockerSocket{
+		{"/var/run/docker.sock", "Docker"},
+		{"/run/podman/podman.sock", "podman"},
+	}, dockerSockets())
+
+	// The configured socket takes priority and rootless Podman is probed last.
+	os.Setenv("DOCKER_SOCKET_PATH", "/tmp/podman.sock")
+	defer os.Unsetenv("DOCKER_SOCKET_PATH")
+	os.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	assert.Equal([]dockerSocket{
+		{"/tmp/podman.sock", "podman"},
+		{"/var/run/docker.sock", "Docker"},
+		{"/run/podman/podman.sock", "podman"},
+		{"/run/user/1000/podman/podman.sock", "podman"},
+	}, dockerSockets())
+}
+
+// restoreEnv returns a function restoring the environment variables to their
+// current values, unsetting the ones which aren't set.
+func restoreEnv(keys ...string) func() {
+	values := make(map[string]*string, len(keys))
+	for _, k := range keys {
+		if v, ok := os.LookupEnv(k); ok {
+			values[k] = &v
+		} else {
+			values[k] = nil
+		}
+	}
+	return func() {
+		for k, v := range values {
+			if v != nil {
+				os.Setenv(k, *v)
+			} else {
+				os.Unsetenv(k)
+			}
+		}
+	}
+}
+
+func TestDockerHTTPClientNoProxy(t *testing.T) {
+	assert := assert.New(t)
+	defer restoreEnv("DOCKER_CERT_PATH", "HTTP_PROXY")()
+	os.Unsetenv("DOCKER_CERT_PATH")
+	os.Setenv("HTTP_PROXY", "http://proxy:3128")
+
+	// The default client, honoring the proxy, is used unless asked otherwise.
+	c, err := dockerHTTPClient("tcp://10.0.0.2:2375", false)
+	assert.NoError(err)
+	assert.Nil(c)
+
+	// Local sockets are never proxied.
+	c, err = dockerHTTPClient("unix:///var/run/docker.sock", true)
+	assert.NoError(err)
+	assert.Nil(c)
+
+	c, err = dockerHTTPClient("tcp://10.0.0.2:2375", true)
+	assert.NoError(err)
+	if assert.NotNil(c) {
+		tr, ok := c.Transport.(*http.Transport)
+		assert.True(ok)
+		assert.Nil(tr.Proxy)
+	}
+}
+
+func TestConnectToPodman(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "test-connect-to-podman")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	sockPath := filepath.Join(dir, "podman", "podman.sock")
+	assert.NoError(os.MkdirAll(filepath.Dir(sockPath), 0755))
+	l, err := net.Listen("unix", sockPath)
+	assert.NoError(err)
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(types.Version{APIVersion: "1.25"})
+	}))
+	ts.Listener = l
+	ts.Start()
+	defer ts.Close()
+
+	os.Unsetenv("DOCKER_HOST")
+	os.Setenv("DOCKER_SOCKET_PATH", sockPath)
+	defer os.Unsetenv("DOCKER_SOCKET_PATH")
+
+	cli, containerType, err := connectToDocker(false)
+	assert.NoError(err)
+	assert.Equal("podman", containerType)
+	v, err := cli.ServerVersion(context.Background())
+	assert.NoError(err)
+	assert.Equal("1.25", v.APIVersion)
+}
+
+func TestContainerUptime(t *testing.T) {
+	now := int64(10000)
+	for i, tc := range []struct {
+		inspectStartedAt, cgroupStartedAt, created int64
+		expected                                   int64
+	}{
+		// The inspect start time is preferred.
+		{inspectStartedAt: 9000, cgroupStartedAt: 8990, created: 5000, expected: 1000},
+		// The container restarted after it was inspected.
+		{inspectStartedAt: 9000, cgroupStartedAt: 9500, created: 5000, expected: 500},
+		// Not inspected.
+		{cgroupStartedAt: 9500, created: 5000, expected: 500},
+		// No start time at all.
+		{created: 5000, expected: 5000},
+		{expected: 0},
+		// Clock skew.
+		{cgroupStartedAt: 10005, expected: 0},
+	} {
+		assert.Equal(t, tc.expected, containerUptime(now, tc.inspectStartedAt, tc.cgroupStartedAt, tc.created), "test %d", i)
+	}
+}
+
+func TestParallelize(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, workers := range []int{0, 1, 4, 100} {
+		var running, maxRunning int32
+		var mu sync.Mutex
+		calls := make([]int, 50)
+		parallelize(len(calls), workers, func(i int) {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			calls[i]++
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+		})
+		for i, n := range calls {
+			assert.Equal(1, n, "index %d with %d workers", i, workers)
+		}
+		assert.True(maxRunning <= int32(workers) || maxRunning == 1, "%d running with %d workers", maxRunning, workers)
+	}
+}
+
+// BenchmarkContainerStats measures the cgroup stats collection of 1000
+// synthetic containers with a single and with several workers.
+func BenchmarkContainerStats(b *testing.B) {
+	tmp, err := ioutil.TempDir("", "bench-container-stats")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	os.Setenv("HOST_PROC", tmp)
+	defer os.Setenv("HOST_PROC", "/proc")
+
+	write := func(contents string, elem ...string) {
+		p := filepath.Join(append([]string{tmp}, elem...)...)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			b.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	var mounts []string
+	for _, ctrl := range []string{"memory", "cpu,cpuacct", "blkio"} {
+		mounts = append(mounts, fmt.Sprintf("cgroup %s/cgroup/%s cgroup rw,%s 0 0", tmp, ctrl, ctrl))
+	}
+	write(strings.Join(mounts, "\n")+"\n", "mounts")
+
+	cli := &fakeDockerClient{}
+	for i := 1; i <= 1000; i++ {
+		id := fmt.Sprintf("%064x", i)
+		cli.containers = append(cli.containers, types.Container{ID: id, Names: []string{"/c" + strconv.Itoa(i)}, State: "running"})
+		write(fmt.Sprintf("6:memory:/docker/%s\n5:cpu,cpuacct:/docker/%s\n4:blkio:/docker/%s\n", id, id, id), strconv.Itoa(i), "cgroup")
+		write("rss 1024\ncache 2048\ntotal_inactive_file 512\n", "cgroup", "memory", "docker", id, "memory.stat")
+		write("4096\n", "cgroup", "memory", "docker", id, "memory.usage_in_bytes")
+		write("user 100\nsystem 50\n", "cgroup", "cpu,cpuacct", "docker", id, "cpuacct.stat")
+		write("8:0 Read 1000\n8:0 Write 2000\n", "cgroup", "blkio", "docker", id, "blkio.throttle.io_service_bytes")
+	}
+
+	for _, workers := range []int{1, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			d, err := newDockerUtil(&Config{CacheDuration: time.Hour, StatWorkers: workers}, cli)
+			if err != nil {
+				b.Fatal(err)
+			}
+			// Drop the listing of the previous run.
+			cache.Delete(containersCacheKey)
+			containers, err := d.containers()
+			if err != nil || len(containers) != 1000 {
+				b.Fatalf("got %d containers: %v", len(containers), err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				d.containers()
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	log "github.com/cihub/seelog"
+	"github.com/docker/docker/client"
+)
+
+// errNoEndpoints is returned when none of the endpoints could be connected to.
+var errNoEndpoints = errors.New("unable to connect to any of the docker endpoints")
+
+// endpointsCollector collects the containers of several Docker daemons, each
+// with its own dockerUtil.
+type endpointsCollector struct {
+	utils []*dockerUtil
+}
+
+// NewEndpointsCollector returns a Collector of the containers of the Docker
+// daemons at the given hosts, e.g. tcp://10.0.0.2:2376 or
+// unix:///var/run/docker.sock, whose containers have their Endpoint set to the
+// host. The stats of the containers of remote daemons are read from the stats
+// API since their cgroups and network namespaces aren't on this host. The
+// endpoints which can't be connected to are skipped, it fails if all are.
+// Unlike InitDockerUtil, it doesn't replace the global dockerUtil.
+func NewEndpointsCollector(cfg *Config, endpoints []string) (Collector, error) {
+	c := &endpointsCollector{}
+	for _, endpoint := range endpoints {
+		d, err := newEndpointUtil(cfg, endpoint)
+		if err != nil {
+			log.Errorf("unable to connect to docker endpoint %s: %s", endpoint, err)
+			continue
+		}
+		c.utils = append(c.utils, d)
+	}
+	if len(c.utils) == 0 {
+		return nil, errNoEndpoints
+	}
+	return c, nil
+}
+
+// InitEndpointsCollector makes the collector of the given Docker endpoints
+// the one returned by GetCollector, see NewEndpointsCollector.
+func InitEndpointsCollector(cfg *Config, endpoints []string) error {
+	c, err := NewEndpointsCollector(cfg, endpoints)
+	if err != nil {
+		return err
+	}
+	if globalCollector != nil && globalCollector != Collector(globalDockerUtil) {
+		globalCollector.Close()
+	}
+	globalCollector = c
+	return nil
+}
+
+// newEndpointUtil returns a dockerUtil connected to the daemon at endpoint.
+func newEndpointUtil(cfg *Config, endpoint string) (*dockerUtil, error) {
+	cli, err := connectToEndpoint(endpoint, cfg.NoProxy)
+	if err != nil {
+		return nil, err
+	}
+	d, err := newDockerUtil(cfg, cli)
+	if err != nil {
+		return nil, err
+	}
+	d.endpoint = endpoint
+	d.remote = isRemoteDockerHost(endpoint)
+	d.reconnect = func() (dockerClient, error) {
+		return connectToEndpoint(endpoint, cfg.NoProxy)
+	}
+	if cfg.WatchEvents {
+		d.startEventsWatcher()
+	}
+	if cfg.BackgroundRefresh {
+		d.startRefresher()
+	}
+	return d, nil
+}
+
+// connectToEndpoint connects to the daemon at host with its own API version.
+// Unlike connectToDocker it ignores DOCKER_API_VERSION which pins the
+// version of the local daemon.
+func connectToEndpoint(host string, noProxy bool) (*client.Client, error) {
+	httpClient, err := dockerHTTPClient(host, noProxy)
+	if err != nil {
+		return nil, err
+	}
+	probe, err := client.NewClient(host, "", httpClient, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer probe.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOperationTimeout)
+	defer cancel()
+	v, err := probe.ServerVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the API version of %s: %s", host, err)
+	}
+	return client.NewClient(host, v.APIVersion, httpClient, nil)
+}
+
+// AllContainers returns the containers of all the endpoints, from their
+// snapshots with Config.BackgroundRefresh. The endpoints failing are logged
+// and skipped, it only fails if all of them do.
+func (c *endpointsCollector) AllContainers() ([]*Container, error) {
+	var all []*Container
+	var lastErr error
+	failures := 0
+	for _, d := range c.utils {
+		containers, err := d.AllContainers()
+		if err != nil {
+			log.Debugf("unable to collect the containers of %s: %s", d.endpoint, err)
+			lastErr = err
+			failures++
+			continue
+		}
+		all = append(all, containers...)
+	}
+	if failures == len(c.utils) {
+		return nil, lastErr
+	}
+	return all, nil
+}
+
+// GetHostname isn't supported since the containers run on several hosts.
+func (c *endpointsCollector) GetHostname() (string, error) {
+	return "", errHostnameNotSupported
+}
+
+// Close closes the clients of all the endpoints.
+func (c *endpointsCollector) Close() {
+	for _, d := range c.utils {
+		d.Close()
+	}
+}
@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointsCollector(t *testing.T) {
+	assert := assert.New(t)
+
+	remote := func(endpoint string, cli *fakeDockerClient) *dockerUtil {
+		d, err := newDockerUtil(&Config{CollectNetwork: true}, cli)
+		assert.NoError(err)
+		d.endpoint = endpoint
+		d.remote = isRemoteDockerHost(endpoint)
+		return d
+	}
+	var stats types.StatsJSON
+	stats.CPUStats.CPUUsage.UsageInUsermode = 1590000000
+	stats.MemoryStats = types.MemoryStats{Usage: 2048, Stats: map[string]uint64{"rss": 1024}}
+
+	// The same container ID on both daemons is kept apart.
+	a := remote("tcp://10.0.0.2:2376", &fakeDockerClient{
+		containers: []types.Container{{ID: "1", Names: []string{"/redis"}, State: "running"}},
+		stats:      map[string]types.StatsJSON{"1": stats},
+	})
+	b := remote("tcp://10.0.0.3:2376", &fakeDockerClient{
+		containers: []types.Container{{ID: "1", Names: []string{"/web"}, State: "running"}},
+		stats:      map[string]types.StatsJSON{"1": stats},
+	})
+	down := remote("tcp://10.0.0.4:2376", &fakeDockerClient{listErr: fmt.Errorf("connection refused")})
+
+	c := &endpointsCollector{utils: []*dockerUtil{a, down, b}}
+	containers, err := c.AllContainers()
+	assert.NoError(err)
+	if assert.Len(containers, 2) {
+		assert.Equal("redis", containers[0].Name)
+		assert.Equal("tcp://10.0.0.2:2376", containers[0].Endpoint)
+		assert.Equal("web", containers[1].Name)
+		assert.Equal("tcp://10.0.0.3:2376", containers[1].Endpoint)
+		// The stats of remote containers come from the stats API.
+		assert.Equal(uint64(1024), containers[1].Memory.RSS)
+		assert.Equal(uint64(159), containers[1].CPU.User)
+	}
+	// The remote network namespaces aren't looked up.
+	assert.Empty(a.networkMappings)
+
+	_, err = (&endpointsCollector{utils: []*dockerUtil{down}}).AllContainers()
+	assert.Error(err)
+
+	// With BackgroundRefresh the snapshots of the endpoints are returned.
+	refreshed := &fakeDockerClient{containers: []types.Container{{ID: "1", Names: []string{"/db"}, State: "running"}}}
+	r, err := newDockerUtil(&Config{BackgroundRefresh: true, MetadataOnly: true}, refreshed)
+	assert.NoError(err)
+	r.endpoint = "tcp://10.0.0.5:2376"
+	r.refresh()
+	containers, err = (&endpointsCollector{utils: []*dockerUtil{r}}).AllContainers()
+	assert.NoError(err)
+	if assert.Len(containers, 1) {
+		assert.Equal("db", containers[0].Name)
+	}
+	assert.Equal(1, refreshed.listCalls)
+
+	_, err = c.GetHostname()
+	assert.Equal(errHostnameNotSupported, err)
+}
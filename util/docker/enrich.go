@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/cihub/seelog"
+)
+
+// enrichBudget is the time an enricher should take at most for a container.
+// Enrichers run on every collection of every check so the slower ones are
+// logged.
+const enrichBudget = 5 * time.Millisecond
+
+// enrichers are the functions registered with RegisterEnricher.
+var enrichers struct {
+	sync.RWMutex
+	fns []func(*Container)
+}
+
+// RegisterEnricher registers a function populating the containers with custom
+// metadata, e.g. Labels or Tags from a CMDB. The enrichers are called in
+// registration order on every container returned by AllContainers, after its
+// stats were collected. They must not block: they should take well under
+// enrichBudget per container and fetch their metadata asynchronously. The
+// Labels map is shared with the containers cache, enrichers updating it must
+// be idempotent. A panicking enricher is recovered and logged.
+func RegisterEnricher(fn func(*Container)) {
+	enrichers.Lock()
+	defer enrichers.Unlock()
+	enrichers.fns = append(enrichers.fns, fn)
+}
+
+// enrichContainers runs the registered enrichers on the containers.
+func enrichContainers(containers []*Container) {
+	enrichers.RLock()
+	fns := enrichers.fns
+	enrichers.RUnlock()
+	for i, fn := range fns {
+		for _, c := range containers {
+			runEnricher(i, fn, c)
+		}
+	}
+}
+
+// runEnricher runs the i-th enricher on a container, recovering from its
+// panics.
+func runEnricher(i int, fn func(*Container), c *Container) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("container enricher %d panicked on container %s: %v", i, c.ID, r)
+		}
+	}()
+	start := time.Now()
+	fn(c)
+	if elapsed := time.Since(start); elapsed > enrichBudget {
+		log.Warnf("container enricher %d took %s on container %s, over its %s budget", i, elapsed, c.ID, enrichBudget)
+	}
+}
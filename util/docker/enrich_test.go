@@ -0,0 +1,32 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnrichContainers(t *testing.T) {
+	assert := assert.New(t)
+	defer func(fns []func(*Container)) { enrichers.fns = fns }(enrichers.fns)
+	enrichers.fns = nil
+
+	RegisterEnricher(func(c *Container) {
+		c.Tags = append(c.Tags, "team:"+c.Name)
+	})
+	RegisterEnricher(func(c *Container) {
+		if c.Name == "broken" {
+			panic("cmdb unavailable")
+		}
+		c.Tags = append(c.Tags, "cmdb:ok")
+	})
+	RegisterEnricher(func(c *Container) {
+		c.Tags = append(c.Tags, "last")
+	})
+
+	containers := []*Container{{ID: "1", Name: "web"}, {ID: "2", Name: "broken"}}
+	enrichContainers(containers)
+	// The enrichers run in order and a panic only skips the panicking one.
+	assert.Equal([]string{"team:web", "cmdb:ok", "last"}, containers[0].Tags)
+	assert.Equal([]string{"team:broken", "last"}, containers[1].Tags)
+}
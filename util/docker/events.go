@@ -0,0 +1,96 @@
+package docker
+
+import (
+	"context"
+	"time"
+
+	log "github.com/cihub/seelog"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// eventsRetryInterval is how long we wait before subscribing to the Docker
+// events again after the stream was interrupted, e.g. by a daemon restart.
+var eventsRetryInterval = 5 * time.Second
+
+// StopEventsWatcher stops the events watcher started with Config.WatchEvents
+// and waits for it to exit. It is a no-op if the watcher isn't running.
+func StopEventsWatcher() {
+	if globalDockerUtil != nil {
+		globalDockerUtil.stopEventsWatcher()
+	}
+}
+
+// startEventsWatcher starts a goroutine invalidating the cached data of
+// containers as soon as they die or are destroyed.
+func (d *dockerUtil) startEventsWatcher() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.watchEvents(ctx)
+	}()
+
+	d.Lock()
+	d.stopEvents = func() {
+		cancel()
+		<-done
+	}
+	d.Unlock()
+}
+
+func (d *dockerUtil) stopEventsWatcher() {
+	d.Lock()
+	stop := d.stopEvents
+	d.stopEvents = nil
+	d.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// watchEvents subscribes to the container die and destroy events until ctx
+// is cancelled, subscribing again if the stream is interrupted.
+func (d *dockerUtil) watchEvents(ctx context.Context) {
+	filter := filters.NewArgs()
+	filter.Add("type", events.ContainerEventType)
+	filter.Add("event", "die")
+	filter.Add("event", "destroy")
+
+	for {
+		messages, errs := d.client().Events(ctx, types.EventsOptions{Filters: filter})
+	stream:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case m := <-messages:
+				d.invalidateContainer(m.Actor.ID, m.Actor.Attributes["image"])
+			case err := <-errs:
+				if ctx.Err() != nil {
+					return
+				}
+				log.Debugf("docker events stream interrupted: %s", err)
+				break stream
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventsRetryInterval):
+		}
+	}
+}
+
+// invalidateContainer drops the cached data of a container that died.
+func (d *dockerUtil) invalidateContainer(containerID, image string) {
+	d.Lock()
+	defer d.Unlock()
+	delete(d.networkMappings, containerID)
+	delete(d.initPids, containerID)
+	delete(d.detailsByID, containerID)
+	// The image is resolved again on the next call if it's still in use.
+	delete(d.imageNameBySha, image)
+}
@@ -0,0 +1,47 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{
+		events:    make(chan events.Message),
+		eventErrs: make(chan error, 1),
+	}
+	d, err := newDockerUtil(&Config{}, cli)
+	assert.NoError(err)
+	d.networkMappings["1"] = []dockerNetwork{hostNetwork}
+	d.networkMappings["2"] = []dockerNetwork{hostNetwork}
+	d.detailsByID["1"] = &containerDetails{}
+	d.imageNameBySha["sha256:aaa"] = imageNameEntry{name: "redis"}
+
+	defer func(i time.Duration) { eventsRetryInterval = i }(eventsRetryInterval)
+	eventsRetryInterval = time.Millisecond
+	d.startEventsWatcher()
+
+	// An interrupted stream is subscribed to again.
+	cli.eventErrs <- errors.New("unexpected EOF")
+	cli.events <- events.Message{
+		Type:   events.ContainerEventType,
+		Action: "die",
+		Actor:  events.Actor{ID: "1", Attributes: map[string]string{"image": "sha256:aaa"}},
+	}
+	d.stopEventsWatcher()
+
+	assert.NotContains(d.networkMappings, "1")
+	assert.Contains(d.networkMappings, "2")
+	assert.Empty(d.detailsByID)
+	assert.Empty(d.imageNameBySha)
+	assert.Nil(d.stopEvents)
+
+	// Stopping again is a no-op.
+	d.stopEventsWatcher()
+}
@@ -0,0 +1,212 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/datadog-process-agent/util/cache"
+	"github.com/docker/docker/api/types"
+	dockernetwork "github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+)
+
+// useFixtures points HOST_PROC at the host snapshot in testdata/<name>, which
+// has the proc and sys files of a real host, trimmed to the ones of a single
+// container, and returns the cgroup root of the snapshot. The block devices,
+// cgroups and pid containers cached from the previous proc are dropped. The
+// returned function restores them.
+func useFixtures(t *testing.T, name string) (string, func()) {
+	root, err := filepath.Abs(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(root); err != nil {
+		t.Fatalf("missing fixtures %s: %s", name, err)
+	}
+	hostProc, hasHostProc := os.LookupEnv("HOST_PROC")
+	os.Setenv("HOST_PROC", filepath.Join(root, "proc"))
+	cache.Delete(partitionsCacheKey)
+	cache.Delete(cgroupsCacheKey)
+	cache.Delete(pidContainersCacheKey)
+	return filepath.Join(root, "sys", "fs", "cgroup"), func() {
+		if hasHostProc {
+			os.Setenv("HOST_PROC", hostProc)
+		} else {
+			os.Unsetenv("HOST_PROC")
+		}
+		cache.Delete(partitionsCacheKey)
+		cache.Delete(cgroupsCacheKey)
+		cache.Delete(pidContainersCacheKey)
+	}
+}
+
+func TestFixturesCgroupV1(t *testing.T) {
+	assert := assert.New(t)
+	cgroupRoot, restore := useFixtures(t, "cgroupv1")
+	defer restore()
+
+	// A redis container on the default bridge network.
+	id := "3b5a6c8c7f1e0f4ba1a8a56a27d2e9cc1f1f1ff2dbe8a8f7c1fa4d0c5f24b1d7"
+	pid := 3146
+	cgs, err := CgroupsForPids(cgroupRoot, []int32{int32(pid)})
+	assert.NoError(err)
+	cg, ok := cgs[id]
+	if !assert.True(ok) {
+		return
+	}
+	assert.False(cg.v2)
+	assert.Equal(filepath.Join(cgroupRoot, "memory", "docker", id), cg.Path())
+
+	mem, err := cg.Mem()
+	assert.NoError(err)
+	assert.Equal(uint64(8744960), mem.RSS)
+	assert.Equal(uint64(2252800), mem.Cache)
+	assert.Equal(uint64(1081344), mem.MappedFile)
+	assert.Equal(uint64(5983), mem.TotalPgFault)
+	assert.Equal(uint64(11997184), mem.MemUsageInBytes)
+	assert.Equal(uint64(11997184-1404928), mem.WorkingSet)
+	limit, err := cg.MemLimit()
+	assert.NoError(err)
+	assert.Equal(uint64(268435456), limit)
+	softLimit, err := cg.MemSoftLimit()
+	assert.NoError(err)
+	assert.Equal(uint64(0), softLimit)
+
+	cpu, err := cg.CPU()
+	assert.NoError(err)
+	assert.Equal(&CgroupTimesStat{ContainerID: id, User: 1428, System: 917, NrThrottled: 27, ThrottledTime: 1839472001}, cpu)
+	cpuLimit, err := cg.CPULimit()
+	assert.NoError(err)
+	assert.Equal(50.0, cpuLimit)
+	cpus, err := cg.CpusetCount()
+	assert.NoError(err)
+	assert.Equal(4, cpus)
+	shares, err := cg.CPUShares()
+	assert.NoError(err)
+	assert.Equal(uint64(512), shares)
+
+	io, err := cg.IO()
+	assert.NoError(err)
+	assert.Equal(&CgroupIOStat{
+		ContainerID: id,
+		ReadBytes:   2 * 10563584,
+		WriteBytes:  2 * 36864,
+		Devices: map[string]IODeviceStat{
+			"sda":  {ReadBytes: 10563584, WriteBytes: 36864},
+			"dm-0": {ReadBytes: 10563584, WriteBytes: 36864},
+		},
+	}, io)
+
+	pids, ok, err := cg.PidsCurrent()
+	assert.NoError(err)
+	assert.True(ok)
+	assert.Equal(uint64(4), pids)
+	pidsLimit, err := cg.PidsLimit()
+	assert.NoError(err)
+	assert.Equal(uint64(0), pidsLimit)
+
+	networks := findDockerNetworks(id, pid, &types.SummaryNetworkSettings{
+		Networks: map[string]*dockernetwork.EndpointSettings{
+			"bridge": {Gateway: "172.17.0.1", IPAddress: "172.17.0.2"},
+		},
+	})
+	assert.Equal([]dockerNetwork{{iface: "eth0", dockerName: "bridge"}}, networks)
+	stat, err := collectNetworkStats(id, pid, networks, false)
+	assert.NoError(err)
+	assert.Equal(&NetworkStat{BytesRcvd: 1849320, PacketsRcvd: 14873, BytesSent: 2417762, PacketsSent: 12938}, stat)
+}
+
+func TestFixturesCgroupV2(t *testing.T) {
+	assert := assert.New(t)
+	cgroupRoot, restore := useFixtures(t, "cgroupv2")
+	defer restore()
+
+	// A postgres container on two user-defined networks, in a systemd scope.
+	id := "e1c2c4f0b6d3a8f9d4c7b2a1e0f9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1"
+	pid := 5120
+	cgs, err := CgroupsForPids(cgroupRoot, []int32{int32(pid)})
+	assert.NoError(err)
+	cg, ok := cgs[id]
+	if !assert.True(ok) {
+		return
+	}
+	assert.True(cg.v2)
+	assert.Equal(filepath.Join(cgroupRoot, "system.slice", "docker-"+id+".scope"), cg.Path())
+
+	mem, err := cg.Mem()
+	assert.NoError(err)
+	assert.Equal(uint64(41156608), mem.RSS)
+	assert.Equal(uint64(130940928), mem.Cache)
+	assert.Equal(uint64(36044800), mem.MappedFile)
+	assert.Equal(uint64(48201), mem.Pgfault)
+	assert.Equal(uint64(97), mem.Pgmajfault)
+	assert.Equal(uint64(175939584), mem.MemUsageInBytes)
+	assert.Equal(uint64(175939584-51105792), mem.WorkingSet)
+	assert.Equal(uint64(0), mem.Swap)
+	limit, err := cg.MemLimit()
+	assert.NoError(err)
+	assert.Equal(uint64(1073741824), limit)
+
+	cpu, err := cg.CPU()
+	assert.NoError(err)
+	assert.Equal(&CgroupTimesStat{ContainerID: id, User: 2701, System: 1141, CPUPressure: 1.25}, cpu)
+	cpuLimit, err := cg.CPULimit()
+	assert.NoError(err)
+	assert.Equal(100.0, cpuLimit)
+	cpus, err := cg.CpusetCount()
+	assert.NoError(err)
+	assert.Equal(8, cpus)
+	// The weight of the default 1024 shares.
+	shares, err := cg.CPUShares()
+	assert.NoError(err)
+	assert.Equal(uint64(998), shares)
+
+	// The shares from container.Inspect are kept.
+	ctr := &Container{ID: id, CpuShares: 1024}
+	setCgroupLimits(ctr, cg, nil)
+	assert.Equal(int64(1024), ctr.CpuShares)
+	ctr = &Container{ID: id}
+	setCgroupLimits(ctr, cg, nil)
+	assert.Equal(int64(998), ctr.CpuShares)
+
+	io, err := cg.IO()
+	assert.NoError(err)
+	assert.Equal(&CgroupIOStat{
+		ContainerID: id,
+		ReadBytes:   64536576,
+		WriteBytes:  281903104,
+		Devices: map[string]IODeviceStat{
+			"nvme0n1": {ReadBytes: 64536576, WriteBytes: 281903104},
+		},
+	}, io)
+
+	pids, ok, err := cg.PidsCurrent()
+	assert.NoError(err)
+	assert.True(ok)
+	assert.Equal(uint64(9), pids)
+	pidsLimit, err := cg.PidsLimit()
+	assert.NoError(err)
+	assert.Equal(uint64(4096), pidsLimit)
+
+	networks := findDockerNetworks(id, pid, &types.SummaryNetworkSettings{
+		Networks: map[string]*dockernetwork.EndpointSettings{
+			"backend":  {Gateway: "172.18.0.1", IPAddress: "172.18.0.3"},
+			"frontend": {Gateway: "172.19.0.1", IPAddress: "172.19.0.5"},
+		},
+	})
+	// The default route is on eth0 but frontend is on eth1.
+	assert.Equal([]dockerNetwork{{iface: "eth0", dockerName: "backend"}, {iface: "eth1", dockerName: "frontend"}}, networks)
+	stat, err := collectNetworkStats(id, pid, networks, true)
+	assert.NoError(err)
+	assert.Equal(&NetworkStat{
+		BytesRcvd:   98374628 + 5214302,
+		PacketsRcvd: 184213 + 31877,
+		BytesSent:   73625118 + 8873521,
+		PacketsSent: 162904 + 29310,
+		PerInterface: map[string]*NetworkStat{
+			"backend":  {BytesRcvd: 98374628, PacketsRcvd: 184213, BytesSent: 73625118, PacketsSent: 162904},
+			"frontend": {BytesRcvd: 5214302, PacketsRcvd: 31877, BytesSent: 8873521, PacketsSent: 29310},
+		},
+	}, stat)
+}
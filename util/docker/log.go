@@ -0,0 +1,98 @@
+package docker
+
+import (
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	log "github.com/cihub/seelog"
+)
+
+// debugLogs tracks the per-container debug messages by signature, so the
+// errors shared by all the containers of a host are logged once per
+// invalidation interval rather than once per container.
+var debugLogs = struct {
+	sync.Mutex
+	interval    time.Duration
+	bySignature map[string]*limitedLog
+}{interval: defaultInvalidationInterval, bySignature: make(map[string]*limitedLog)}
+
+// containerIDRe matches the container IDs in the error messages, e.g. in the
+// paths of the cgroup files.
+var containerIDRe = regexp.MustCompile(`[0-9a-f]{64}`)
+
+type limitedLog struct {
+	// last is when a message of the signature was last logged
+	last time.Time
+	// suppressed is the number of messages not logged since
+	suppressed int
+}
+
+// setDebugLogInterval sets how often the messages of a signature are logged
+// at most, defaultInvalidationInterval if interval isn't positive.
+func setDebugLogInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultInvalidationInterval
+	}
+	debugLogs.Lock()
+	debugLogs.interval = interval
+	debugLogs.Unlock()
+}
+
+// debugSignature returns the signature of a debug message: its format and
+// the kind of its errors, without the container IDs, so different errors are
+// limited independently but the same error of all the containers together.
+func debugSignature(format string, params []interface{}) string {
+	signature := format
+	for _, p := range params {
+		err, ok := p.(error)
+		if !ok {
+			continue
+		}
+		switch e := err.(type) {
+		case *os.PathError:
+			// The path is specific to the container, the cause isn't.
+			signature += "|" + e.Op + ": " + e.Err.Error()
+		case *os.SyscallError:
+			signature += "|" + e.Syscall + ": " + e.Err.Error()
+		default:
+			signature += "|" + containerIDRe.ReplaceAllString(err.Error(), "")
+		}
+	}
+	return signature
+}
+
+// allowDebug returns whether a message of the signature can be logged at now
+// and, if so, how many messages of the signature were suppressed before it.
+func allowDebug(signature string, now time.Time) (bool, int) {
+	debugLogs.Lock()
+	defer debugLogs.Unlock()
+	l, ok := debugLogs.bySignature[signature]
+	if !ok {
+		debugLogs.bySignature[signature] = &limitedLog{last: now}
+		return true, 0
+	}
+	if now.Sub(l.last) < debugLogs.interval {
+		l.suppressed++
+		return false, 0
+	}
+	suppressed := l.suppressed
+	l.last, l.suppressed = now, 0
+	return true, suppressed
+}
+
+// limitedDebugf is log.Debugf for the messages logged for every container. A
+// message is logged at most once per invalidation interval for each format
+// and error, with the number of messages suppressed since the last one.
+func limitedDebugf(format string, params ...interface{}) {
+	ok, suppressed := allowDebug(debugSignature(format, params), time.Now())
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		log.Debugf(format+" (%d similar messages suppressed)", append(params, suppressed)...)
+		return
+	}
+	log.Debugf(format, params...)
+}
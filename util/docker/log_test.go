@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowDebug(t *testing.T) {
+	assert := assert.New(t)
+	debugLogs.Lock()
+	bySignature := debugLogs.bySignature
+	debugLogs.bySignature = make(map[string]*limitedLog)
+	debugLogs.Unlock()
+	defer func() {
+		debugLogs.Lock()
+		debugLogs.bySignature = bySignature
+		debugLogs.Unlock()
+	}()
+
+	now := time.Now()
+	allow := func(signature string) (bool, int) { return allowDebug(signature, now) }
+
+	// The first message of a signature is logged, the next ones are
+	// suppressed until the interval elapsed.
+	ok, suppressed := allow("test.debug.1: %s")
+	assert.True(ok)
+	assert.Equal(0, suppressed)
+	for i := 0; i < 3; i++ {
+		ok, _ = allow("test.debug.1: %s")
+		assert.False(ok)
+	}
+	// Signatures are limited independently.
+	ok, _ = allow("test.debug.2: %s")
+	assert.True(ok)
+
+	now = now.Add(debugLogs.interval - time.Second)
+	ok, _ = allow("test.debug.1: %s")
+	assert.False(ok)
+	now = now.Add(time.Second)
+	ok, suppressed = allow("test.debug.1: %s")
+	assert.True(ok)
+	assert.Equal(4, suppressed)
+
+	// The count starts over after each logged message.
+	now = now.Add(debugLogs.interval)
+	ok, suppressed = allow("test.debug.1: %s")
+	assert.True(ok)
+	assert.Equal(0, suppressed)
+}
+
+func TestDebugSignature(t *testing.T) {
+	assert := assert.New(t)
+
+	id1 := "a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419"
+	id2 := "3b5a6c8c7f1e0f4ba1a8a56a27d2e9cc1f1f1ff2dbe8a8f7c1fa4d0c5f24b1d7"
+	pathErr := func(id string, err error) error {
+		return &os.PathError{Op: "open", Path: "/sys/fs/cgroup/memory/docker/" + id + "/memory.stat", Err: err}
+	}
+
+	// The same error of different containers shares a signature...
+	assert.Equal(
+		debugSignature("cgroup memory: %s", []interface{}{pathErr(id1, syscall.EACCES)}),
+		debugSignature("cgroup memory: %s", []interface{}{pathErr(id2, syscall.EACCES)}))
+	assert.Equal(
+		debugSignature("container id %s has an empty cgroup, skipping", []interface{}{id1}),
+		debugSignature("container id %s has an empty cgroup, skipping", []interface{}{id2}))
+	assert.Equal(
+		debugSignature("cgroup cpu: %s", []interface{}{fmt.Errorf("wrong format file: /docker/%s/cpu.stat", id1)}),
+		debugSignature("cgroup cpu: %s", []interface{}{fmt.Errorf("wrong format file: /docker/%s/cpu.stat", id2)}))
+
+	// ...but not different errors.
+	assert.NotEqual(
+		debugSignature("cgroup memory: %s", []interface{}{pathErr(id1, syscall.EACCES)}),
+		debugSignature("cgroup memory: %s", []interface{}{pathErr(id1, syscall.ENOENT)}))
+}
+
+func TestSetDebugLogInterval(t *testing.T) {
+	assert := assert.New(t)
+	defer setDebugLogInterval(0)
+
+	setDebugLogInterval(time.Minute)
+	assert.Equal(time.Minute, debugLogs.interval)
+	setDebugLogInterval(0)
+	assert.Equal(defaultInvalidationInterval, debugLogs.interval)
+}
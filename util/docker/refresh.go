@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"time"
+
+	log "github.com/cihub/seelog"
+)
+
+// defaultRefreshInterval is used when Config.RefreshInterval is unset.
+const defaultRefreshInterval = 10 * time.Second
+
+// containersSnapshot is the result of the last background refresh.
+type containersSnapshot struct {
+	containers []*Container
+	// err is only set if no refresh succeeded yet
+	err error
+	// at is when containers were collected
+	at time.Time
+}
+
+// startRefresher starts a goroutine collecting the containers every
+// RefreshInterval, the first time right away, into the snapshot returned by
+// AllContainers.
+func (d *dockerUtil) startRefresher() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(d.cfg.RefreshInterval)
+		defer ticker.Stop()
+		for {
+			d.refresh()
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	d.Lock()
+	d.stopRefresh = func() {
+		close(stop)
+		<-done
+	}
+	d.Unlock()
+}
+
+func (d *dockerUtil) stopRefresher() {
+	d.Lock()
+	stop := d.stopRefresh
+	d.stopRefresh = nil
+	d.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// refresh collects the containers into the snapshot. Failures keep the last
+// successful snapshot, which gets older.
+func (d *dockerUtil) refresh() {
+	containers, err := d.containers()
+	if err != nil {
+		log.Debugf("unable to refresh the containers: %s", err)
+		if last, ok := d.snapshot.Load().(*containersSnapshot); ok && last.err == nil {
+			return
+		}
+		d.snapshot.Store(&containersSnapshot{err: err})
+		return
+	}
+	d.snapshot.Store(&containersSnapshot{containers: containers, at: time.Now()})
+}
+
+// latestContainers returns the containers of the last background refresh
+// and reports their age, without blocking. It returns no container until the
+// first refresh completes.
+func (d *dockerUtil) latestContainers() ([]*Container, error) {
+	s, ok := d.snapshot.Load().(*containersSnapshot)
+	if !ok {
+		return nil, nil
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	gaugeSince(d.cfg.Statsd, "datadog.process.docker.snapshot_age_ms", s.at)
+	return s.containers, nil
+}
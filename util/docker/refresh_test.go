@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackgroundRefresh(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeDockerClient{containers: []types.Container{
+		{ID: "1", Names: []string{"/redis"}, State: "running"},
+	}}
+	stats := &fakeStatsClient{counts: make(map[string]int64), gauges: make(map[string]int)}
+	// The metadata is enough to check the snapshots without reading cgroups.
+	d, err := newDockerUtil(&Config{BackgroundRefresh: true, MetadataOnly: true, Statsd: stats}, cli)
+	assert.NoError(err)
+	// Don't share the containers cache with the other tests.
+	d.endpoint = "test.refresh.1"
+
+	// There is no container until the first refresh.
+	containers, err := d.AllContainers()
+	assert.NoError(err)
+	assert.Empty(containers)
+
+	d.refresh()
+	containers, err = d.AllContainers()
+	assert.NoError(err)
+	if assert.Len(containers, 1) {
+		assert.Equal("redis", containers[0].Name)
+	}
+	// The snapshot is returned without calling the daemon.
+	assert.Equal(1, cli.listCalls)
+	assert.Equal(1, stats.gauges["datadog.process.docker.snapshot_age_ms"])
+
+	// A failed refresh keeps the last snapshot.
+	cli.listErr = errors.New("daemon is down")
+	d.refresh()
+	containers, err = d.AllContainers()
+	assert.NoError(err)
+	assert.Len(containers, 1)
+
+	// Until a refresh succeeds the error is returned.
+	d, err = newDockerUtil(&Config{BackgroundRefresh: true, MetadataOnly: true}, cli)
+	assert.NoError(err)
+	d.endpoint = "test.refresh.2"
+	d.refresh()
+	_, err = d.AllContainers()
+	assert.True(IsCollectionError(err, ErrListContainers))
+
+	d.startRefresher()
+	d.stopRefresher()
+	assert.Nil(d.stopRefresh)
+	// Stopping again is a no-op.
+	d.stopRefresher()
+}
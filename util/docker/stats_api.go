@@ -0,0 +1,188 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// nsPerTick converts the nanoseconds reported by the stats API into the
+// USER_HZ ticks reported by cgroup v1 cpuacct.stat.
+const nsPerTick = usecPerTick * 1000
+
+// apiStats fills the stats of a container from the Docker stats API. It is
+// used in place of the cgroup files when they aren't readable, at the cost of
+// an API call per container.
+func (d *dockerUtil) apiStats(container *Container) error {
+	ctx, cancel := d.timeoutContext()
+	defer cancel()
+
+	resp, err := d.client().ContainerStats(ctx, container.ID, false)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var s types.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return fmt.Errorf("unable to decode stats for container %s: %s", container.ID, err)
+	}
+
+	if isWindows {
+		container.CPU = statsCPUWindows(container.ID, s.CPUStats)
+		container.Memory = statsMemWindows(container.ID, s.MemoryStats)
+		container.IO = statsIOWindows(container.ID, s.StorageStats)
+		container.PidsCurrent = uint64(s.NumProcs)
+	} else {
+		container.CPU = statsCPU(container.ID, s.CPUStats)
+		container.Memory = statsMem(container.ID, s.MemoryStats)
+		container.IO = statsIO(container.ID, s.BlkioStats)
+	}
+	container.Network = NullContainer.Network
+	if d.cfg.CollectNetwork {
+		d.Lock()
+		networks := d.networkMappings[container.ID]
+		d.Unlock()
+		// An empty mapping is a container in network host mode or sharing
+		// the PID namespace of another container, a nil one couldn't be
+		// mapped, e.g. on Windows without procfs.
+		if networks == nil || len(networks) > 0 {
+			container.Network = statsNetwork(s.Networks, networks, d.cfg.CollectNetworkPerInterface)
+		}
+	}
+	// The stats API reports the host memory as the limit of unlimited
+	// containers.
+	container.MemLimit = s.MemoryStats.Limit
+	container.Uptime = containerUptime(time.Now().Unix(), container.inspectStartedAt, 0, container.Created)
+	return nil
+}
+
+func statsCPU(id string, s types.CPUStats) *CgroupTimesStat {
+	return &CgroupTimesStat{
+		ContainerID:   id,
+		User:          s.CPUUsage.UsageInUsermode / nsPerTick,
+		System:        s.CPUUsage.UsageInKernelmode / nsPerTick,
+		NrThrottled:   s.ThrottlingData.ThrottledPeriods,
+		ThrottledTime: s.ThrottlingData.ThrottledTime,
+	}
+}
+
+// statsCPUWindows converts the CPU times of Windows, in 100ns units, into
+// ticks. Windows doesn't report any throttling.
+func statsCPUWindows(id string, s types.CPUStats) *CgroupTimesStat {
+	return &CgroupTimesStat{
+		ContainerID: id,
+		User:        s.CPUUsage.UsageInUsermode * 100 / nsPerTick,
+		System:      s.CPUUsage.UsageInKernelmode * 100 / nsPerTick,
+	}
+}
+
+// statsMemWindows maps the memory stats of Windows which only report the
+// commit and the private working set.
+func statsMemWindows(id string, s types.MemoryStats) *CgroupMemStat {
+	return &CgroupMemStat{
+		ContainerID:     id,
+		RSS:             s.PrivateWorkingSet,
+		MemUsageInBytes: s.Commit,
+		WorkingSet:      s.PrivateWorkingSet,
+	}
+}
+
+// statsIOWindows maps the storage stats of Windows, which aren't broken down
+// by device.
+func statsIOWindows(id string, s types.StorageStats) *CgroupIOStat {
+	return &CgroupIOStat{
+		ContainerID: id,
+		ReadBytes:   s.ReadSizeBytes,
+		WriteBytes:  s.WriteSizeBytes,
+	}
+}
+
+// statsMem maps the memory stats, whose keys are the ones of the memory.stat
+// file of either cgroup v1 or v2 depending on the host.
+func statsMem(id string, s types.MemoryStats) *CgroupMemStat {
+	mem := &CgroupMemStat{
+		ContainerID:     id,
+		MemUsageInBytes: s.Usage,
+		MemFailCnt:      s.Failcnt,
+	}
+	// anon is the cgroup v2 equivalent of rss.
+	_, v2 := s.Stats["anon"]
+	for k, v := range s.Stats {
+		if v2 {
+			mem.setV2(k, v)
+		} else {
+			mem.setV1(k, v)
+		}
+	}
+	if v2 {
+		mem.WorkingSet = workingSet(mem.MemUsageInBytes, mem.InactiveFile)
+	} else {
+		mem.WorkingSet = workingSet(mem.MemUsageInBytes, mem.TotalInactiveFile)
+	}
+	return mem
+}
+
+func statsIO(id string, s types.BlkioStats) *CgroupIOStat {
+	io := &CgroupIOStat{ContainerID: id}
+	if len(s.IoServiceBytesRecursive) == 0 {
+		return io
+	}
+	devices := blockDevices()
+	io.Devices = make(map[string]IODeviceStat)
+	for _, entry := range s.IoServiceBytesRecursive {
+		name := deviceName(devices, fmt.Sprintf("%d:%d", entry.Major, entry.Minor))
+		dev := io.Devices[name]
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			dev.ReadBytes += entry.Value
+			io.ReadBytes += entry.Value
+		case "write":
+			dev.WriteBytes += entry.Value
+			io.WriteBytes += entry.Value
+		default:
+			continue
+		}
+		io.Devices[name] = dev
+	}
+	return io
+}
+
+// statsNetwork sums the stats of the interfaces. The per interface stats are
+// keyed by Docker network when the interface could be mapped to one, by
+// interface name otherwise.
+func statsNetwork(s map[string]types.NetworkStats, networks []dockerNetwork, perInterface bool) *NetworkStat {
+	stat := &NetworkStat{}
+	if perInterface {
+		stat.PerInterface = make(map[string]*NetworkStat, len(s))
+	}
+	for iface, nw := range s {
+		ifaceStat := &NetworkStat{
+			BytesRcvd:   nw.RxBytes,
+			PacketsRcvd: nw.RxPackets,
+			BytesSent:   nw.TxBytes,
+			PacketsSent: nw.TxPackets,
+		}
+		stat.BytesRcvd += ifaceStat.BytesRcvd
+		stat.PacketsRcvd += ifaceStat.PacketsRcvd
+		stat.BytesSent += ifaceStat.BytesSent
+		stat.PacketsSent += ifaceStat.PacketsSent
+		if !perInterface {
+			continue
+		}
+		mapped := false
+		for _, dn := range networks {
+			if dn.iface == iface {
+				stat.PerInterface[dn.dockerName] = ifaceStat
+				mapped = true
+			}
+		}
+		if !mapped {
+			stat.PerInterface[iface] = ifaceStat
+		}
+	}
+	return stat
+}
@@ -0,0 +1,166 @@
+package docker
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-process-agent/util/cache"
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerContainersStatsAPI(t *testing.T) {
+	assert := assert.New(t)
+
+	// A proc without cgroup mounts.
+	tmp, err := ioutil.TempDir("", "test-docker-containers-stats-api")
+	assert.NoError(err)
+	defer os.RemoveAll(tmp)
+	os.Setenv("HOST_PROC", tmp)
+	defer os.Setenv("HOST_PROC", "/proc")
+	cache.Set(partitionsCacheKey, map[string]string{"8:0": "sda"})
+	defer cache.Delete(partitionsCacheKey)
+
+	var stats types.StatsJSON
+	stats.CPUStats.CPUUsage.UsageInUsermode = 1590000000
+	stats.CPUStats.CPUUsage.UsageInKernelmode = 880000000
+	stats.CPUStats.ThrottlingData.ThrottledPeriods = 12
+	stats.CPUStats.ThrottlingData.ThrottledTime = 345678000
+	stats.MemoryStats = types.MemoryStats{
+		Usage: 2048,
+		Limit: 4096,
+		Stats: map[string]uint64{"rss": 1024, "cache": 512, "total_inactive_file": 256, "unknown": 1},
+	}
+	stats.BlkioStats.IoServiceBytesRecursive = []types.BlkioStatEntry{
+		{Major: 8, Minor: 0, Op: "Read", Value: 1000},
+		{Major: 8, Minor: 0, Op: "Write", Value: 2000},
+		{Major: 8, Minor: 0, Op: "Total", Value: 3000},
+		{Major: 252, Minor: 0, Op: "Read", Value: 10},
+	}
+	stats.Networks = map[string]types.NetworkStats{
+		"eth0": {RxBytes: 100, RxPackets: 1, TxBytes: 200, TxPackets: 2},
+		"eth1": {RxBytes: 10, RxPackets: 1, TxBytes: 20, TxPackets: 2},
+	}
+
+	cli := &fakeDockerClient{
+		containers: []types.Container{
+			{ID: "1", Names: []string{"/redis"}, State: "running"},
+			{ID: "2", Names: []string{"/web"}, State: "running"},
+		},
+		inspects: map[string]types.ContainerJSON{
+			"2": {ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Pid: 2}}},
+		},
+		stats: map[string]types.StatsJSON{"1": stats},
+	}
+	d, err := newDockerUtil(&Config{UseDockerStatsAPI: true, CollectNetwork: true, CollectNetworkPerInterface: true}, cli)
+	assert.NoError(err)
+	d.networkMappings["1"] = []dockerNetwork{{iface: "eth0", dockerName: "bridge"}}
+
+	containers, err := cgroupContainers("test.containers.stats.api", time.Second, "", 0, 0, d.dockerContainers, d.networkStats, d.statsFallback(), nil)
+	assert.NoError(err)
+	if !assert.Len(containers, 2) {
+		return
+	}
+
+	c := containers[0]
+	assert.Equal(&CgroupTimesStat{ContainerID: "1", User: 159, System: 88, NrThrottled: 12, ThrottledTime: 345678000}, c.CPU)
+	assert.Equal(&CgroupMemStat{
+		ContainerID:       "1",
+		RSS:               1024,
+		Cache:             512,
+		TotalInactiveFile: 256,
+		MemUsageInBytes:   2048,
+		WorkingSet:        1792,
+	}, c.Memory)
+	assert.Equal(uint64(4096), c.MemLimit)
+	assert.Equal(&CgroupIOStat{
+		ContainerID: "1",
+		ReadBytes:   1010,
+		WriteBytes:  2000,
+		Devices: map[string]IODeviceStat{
+			"sda":   {ReadBytes: 1000, WriteBytes: 2000},
+			"252:0": {ReadBytes: 10},
+		},
+	}, c.IO)
+	assert.Equal(&NetworkStat{
+		BytesRcvd:   110,
+		PacketsRcvd: 2,
+		BytesSent:   220,
+		PacketsSent: 4,
+		PerInterface: map[string]*NetworkStat{
+			"bridge": {BytesRcvd: 100, PacketsRcvd: 1, BytesSent: 200, PacketsSent: 2},
+			"eth1":   {BytesRcvd: 10, PacketsRcvd: 1, BytesSent: 20, PacketsSent: 2},
+		},
+	}, c.Network)
+
+	// The container whose stats can't be read is reported without stats.
+	c = containers[1]
+	assert.Equal("2", c.ID)
+	assert.Equal(NullContainer.CPU, c.CPU)
+	assert.Equal(NullContainer.Memory, c.Memory)
+
+	// Without the flag the stats API isn't queried.
+	d.cfg.UseDockerStatsAPI = false
+	containers, err = cgroupContainers("test.containers.stats.api.disabled", time.Second, "", 0, 0, d.dockerContainers, d.networkStats, d.statsFallback(), nil)
+	assert.NoError(err)
+	if assert.Len(containers, 2) {
+		assert.Equal(NullContainer.CPU, containers[0].CPU)
+	}
+}
+
+func TestStatsMemV2(t *testing.T) {
+	mem := statsMem("1", types.MemoryStats{
+		Usage: 157286400,
+		Stats: map[string]uint64{"anon": 104857600, "file": 52428800, "inactive_file": 10485760},
+	})
+	assert.Equal(t, &CgroupMemStat{
+		ContainerID:     "1",
+		RSS:             104857600,
+		Cache:           52428800,
+		InactiveFile:    10485760,
+		MemUsageInBytes: 157286400,
+		WorkingSet:      146800640,
+	}, mem)
+}
+
+func TestAPIStatsWindows(t *testing.T) {
+	assert := assert.New(t)
+	defer func(w bool) { isWindows = w }(isWindows)
+	isWindows = true
+
+	var stats types.StatsJSON
+	stats.CPUStats.CPUUsage.UsageInUsermode = 15900000
+	stats.CPUStats.CPUUsage.UsageInKernelmode = 8800000
+	stats.MemoryStats = types.MemoryStats{Commit: 4096, CommitPeak: 8192, PrivateWorkingSet: 2048}
+	stats.StorageStats = types.StorageStats{ReadSizeBytes: 1000, WriteSizeBytes: 2000}
+	stats.NumProcs = 3
+	stats.Networks = map[string]types.NetworkStats{
+		"eth0": {RxBytes: 100, RxPackets: 1, TxBytes: 200, TxPackets: 2},
+	}
+	cli := &fakeDockerClient{
+		containers: []types.Container{{ID: "1", Names: []string{"/iis"}, State: "running"}},
+		stats:      map[string]types.StatsJSON{"1": stats},
+	}
+	d, err := newDockerUtil(&Config{CollectNetwork: true}, cli)
+	assert.NoError(err)
+	// The stats API is used without the flag.
+	fallback := d.statsFallback()
+	if !assert.NotNil(fallback) {
+		return
+	}
+
+	c := &Container{ID: "1", State: "running"}
+	assert.NoError(fallback(c))
+	assert.Equal(&CgroupTimesStat{ContainerID: "1", User: 159, System: 88}, c.CPU)
+	assert.Equal(&CgroupMemStat{ContainerID: "1", RSS: 2048, MemUsageInBytes: 4096, WorkingSet: 2048}, c.Memory)
+	assert.Equal(&CgroupIOStat{ContainerID: "1", ReadBytes: 1000, WriteBytes: 2000}, c.IO)
+	assert.Equal(uint64(3), c.PidsCurrent)
+	// The interfaces can't be mapped to the docker networks without procfs.
+	assert.Equal(&NetworkStat{BytesRcvd: 100, PacketsRcvd: 1, BytesSent: 200, PacketsSent: 2}, c.Network)
+}
+
+func TestNamedPipePath(t *testing.T) {
+	assert.Equal(t, `\\.\pipe\docker_engine`, namedPipePath(windowsDockerHost))
+}
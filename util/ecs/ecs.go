@@ -1,11 +1,24 @@
 package ecs
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	agentpayload "github.com/DataDog/agent-payload/gogen"
 	agentecs "github.com/DataDog/datadog-agent/pkg/metadata/ecs"
 	log "github.com/cihub/seelog"
+
+	"github.com/DataDog/datadog-process-agent/util/docker"
+)
+
+// Labels set by the ECS agent on the containers it runs.
+const (
+	containerNameLabel = "com.amazonaws.ecs.container-name"
+	taskArnLabel       = "com.amazonaws.ecs.task-arn"
 )
 
 var (
@@ -14,8 +27,18 @@ var (
 
 	lastErr       string
 	globalECSUtil *ecsUtil
+
+	// ecsAgentURL is the introspection API of the local ECS agent.
+	ecsAgentURL          = fmt.Sprintf("http://localhost:%d/", agentecs.DefaultAgentPort)
+	introspectionTimeout = 300 * time.Millisecond
 )
 
+// instanceMetadata is the response of the v1/metadata introspection endpoint.
+type instanceMetadata struct {
+	Cluster              string `json:"Cluster"`
+	ContainerInstanceArn string `json:"ContainerInstanceArn"`
+}
+
 // InitECSUtil initializes a global ecsUtil used by later function calls.
 func InitECSUtil() error {
 	_, err := agentecs.GetPayload()
@@ -38,8 +61,76 @@ func GetMetadata() *agentpayload.ECSMetadataPayload {
 	return nil
 }
 
+// GetHostname returns the ID of the ECS container instance this agent runs
+// on, from the introspection API of the local ECS agent.
+func GetHostname() (string, error) {
+	if globalECSUtil == nil {
+		return "", ErrECSNotAvailable
+	}
+	return globalECSUtil.getHostname()
+}
+
+// TaskForContainer returns the ARN and family of the ECS task running the
+// container, matched by Docker ID or else by the ECS container name label.
+// Containers not managed by ECS get empty strings.
+func TaskForContainer(ecsMeta *agentpayload.ECSMetadataPayload, ctr *docker.Container) (arn, family string) {
+	if ecsMeta == nil {
+		return "", ""
+	}
+	for _, t := range ecsMeta.Tasks {
+		for _, c := range t.Containers {
+			if c.DockerId == ctr.ID {
+				return t.Arn, t.Family
+			}
+		}
+	}
+
+	name, ok := ctr.Labels[containerNameLabel]
+	if !ok {
+		return "", ""
+	}
+	taskArn := ctr.Labels[taskArnLabel]
+	for _, t := range ecsMeta.Tasks {
+		// The same container name is used by all the tasks of a family.
+		if taskArn != "" && t.Arn != taskArn {
+			continue
+		}
+		for _, c := range t.Containers {
+			if c.Name == name {
+				return t.Arn, t.Family
+			}
+		}
+	}
+	return "", ""
+}
+
 type ecsUtil struct{}
 
+// getHostname returns the ID of the container instance, the last part of its
+// ARN, e.g. 1f73d099-b914-411c-a9ff-81633b7741dd for
+// arn:aws:ecs:us-west-2:012345678910:container-instance/default/1f73d099-b914-411c-a9ff-81633b7741dd
+func (e *ecsUtil) getHostname() (string, error) {
+	client := http.Client{Timeout: introspectionTimeout}
+	res, err := client.Get(ecsAgentURL + "v1/metadata")
+	if err != nil {
+		return "", fmt.Errorf("unable to query the ECS agent metadata: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected ECS agent metadata status: %s", res.Status)
+	}
+	var meta instanceMetadata
+	if err := json.NewDecoder(res.Body).Decode(&meta); err != nil {
+		return "", fmt.Errorf("unable to decode the ECS agent metadata: %s", err)
+	}
+	if meta.ContainerInstanceArn == "" {
+		return "", fmt.Errorf("no container instance in the ECS agent metadata")
+	}
+	parts := strings.Split(meta.ContainerInstanceArn, "/")
+	return parts[len(parts)-1], nil
+}
+
 func (e *ecsUtil) getMetadata() *agentpayload.ECSMetadataPayload {
 	payload, err := agentecs.GetPayload()
 	if err != nil && err.Error() != lastErr {
@@ -0,0 +1,99 @@
+package ecs
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	agentpayload "github.com/DataDog/agent-payload/gogen"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-process-agent/util/docker"
+)
+
+func TestTaskForContainer(t *testing.T) {
+	ecsMeta := &agentpayload.ECSMetadataPayload{
+		Tasks: []*agentpayload.ECSMetadataPayload_Task{
+			{
+				Arn:    "arn:aws:ecs:us-east-1:123:task/web-1",
+				Family: "web",
+				Containers: []*agentpayload.ECSMetadataPayload_Container{
+					{DockerId: "aaa", Name: "nginx"},
+				},
+			},
+			{
+				Arn:    "arn:aws:ecs:us-east-1:123:task/web-2",
+				Family: "web",
+				Containers: []*agentpayload.ECSMetadataPayload_Container{
+					{DockerId: "bbb", Name: "nginx"},
+				},
+			},
+		},
+	}
+
+	for i, tc := range []struct {
+		ctr    *docker.Container
+		arn    string
+		family string
+	}{
+		{&docker.Container{ID: "bbb"}, "arn:aws:ecs:us-east-1:123:task/web-2", "web"},
+		// Matched by name, within the task from the label if set.
+		{
+			&docker.Container{ID: "ccc", Labels: map[string]string{containerNameLabel: "nginx"}},
+			"arn:aws:ecs:us-east-1:123:task/web-1", "web",
+		},
+		{
+			&docker.Container{ID: "ccc", Labels: map[string]string{
+				containerNameLabel: "nginx",
+				taskArnLabel:       "arn:aws:ecs:us-east-1:123:task/web-2",
+			}},
+			"arn:aws:ecs:us-east-1:123:task/web-2", "web",
+		},
+		{&docker.Container{ID: "ccc", Labels: map[string]string{containerNameLabel: "redis"}}, "", ""},
+		{&docker.Container{ID: "ddd"}, "", ""},
+	} {
+		arn, family := TaskForContainer(ecsMeta, tc.ctr)
+		assert.Equal(t, tc.arn, arn, "case %d", i)
+		assert.Equal(t, tc.family, family, "case %d", i)
+	}
+
+	arn, family := TaskForContainer(nil, &docker.Container{ID: "aaa"})
+	assert.Equal(t, "", arn)
+	assert.Equal(t, "", family)
+}
+
+func TestGetHostname(t *testing.T) {
+	assert := assert.New(t)
+
+	metadata := `{"Cluster":"default","ContainerInstanceArn":"arn:aws:ecs:us-west-2:012345678910:container-instance/default/1f73d099-b914-411c-a9ff-81633b7741dd","Version":"Amazon ECS Agent - v1.14.4"}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/metadata" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, metadata)
+	}))
+	defer ts.Close()
+	defer func(url string) { ecsAgentURL = url }(ecsAgentURL)
+	ecsAgentURL = ts.URL + "/"
+
+	// ECS wasn't detected.
+	globalECSUtil = nil
+	_, err := GetHostname()
+	assert.Equal(ErrECSNotAvailable, err)
+
+	globalECSUtil = &ecsUtil{}
+	defer func() { globalECSUtil = nil }()
+	hostname, err := GetHostname()
+	assert.NoError(err)
+	assert.Equal("1f73d099-b914-411c-a9ff-81633b7741dd", hostname)
+
+	// The agent isn't registered to a cluster yet.
+	metadata = `{"Cluster":"default"}`
+	_, err = GetHostname()
+	assert.Error(err)
+	metadata = "garbage"
+	_, err = GetHostname()
+	assert.Error(err)
+}
@@ -0,0 +1,57 @@
+package hostname
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	log "github.com/cihub/seelog"
+
+	"github.com/DataDog/datadog-process-agent/util/docker"
+	"github.com/DataDog/datadog-process-agent/util/ecs"
+	"github.com/DataDog/datadog-process-agent/util/kubernetes"
+)
+
+// ErrHostnameNotFound is returned when none of the providers could resolve a
+// usable hostname.
+var ErrHostnameNotFound = errors.New("unable to resolve hostname")
+
+// provider is a single source of hostname in the resolution chain.
+type provider struct {
+	name string
+	get  func() (string, error)
+}
+
+// providers are tried in order, the first usable value wins.
+var providers = []provider{
+	{"ecs", ecs.GetHostname},
+	{"kubernetes", kubernetes.GetNodeName},
+	{"os", os.Hostname},
+	{"docker", docker.GetHostname},
+}
+
+// Get resolves the hostname of the host the agent runs on, trying the ECS
+// metadata, the Kubernetes node name, the OS hostname and finally the Docker
+// daemon name. Empty and localhost values are skipped.
+func Get() (string, error) {
+	for _, p := range providers {
+		name, err := p.get()
+		if err != nil {
+			log.Debugf("unable to get hostname from %s: %s", p.name, err)
+			continue
+		}
+		if isValid(name) {
+			return name, nil
+		}
+		log.Debugf("ignoring hostname %q from %s", name, p.name)
+	}
+	return "", ErrHostnameNotFound
+}
+
+func isValid(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "localhost", "localhost.localdomain":
+		return false
+	}
+	return true
+}
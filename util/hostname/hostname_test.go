@@ -0,0 +1,48 @@
+package hostname
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	defer func(p []provider) { providers = p }(providers)
+
+	static := func(name string, err error) func() (string, error) {
+		return func() (string, error) { return name, err }
+	}
+	failing := static("", errors.New("unavailable"))
+
+	for _, tc := range []struct {
+		getters  []func() (string, error)
+		expected string
+		err      error
+	}{
+		{
+			getters:  []func() (string, error){static("ecs-host", nil), static("node", nil)},
+			expected: "ecs-host",
+		},
+		{
+			getters:  []func() (string, error){failing, static("node", nil)},
+			expected: "node",
+		},
+		{
+			getters:  []func() (string, error){static("", nil), static("localhost", nil), static("LOCALHOST.localdomain", nil), static("docker-host", nil)},
+			expected: "docker-host",
+		},
+		{
+			getters: []func() (string, error){failing, static("localhost", nil)},
+			err:     ErrHostnameNotFound,
+		},
+	} {
+		providers = nil
+		for _, g := range tc.getters {
+			providers = append(providers, provider{"test", g})
+		}
+		name, err := Get()
+		assert.Equal(t, tc.expected, name)
+		assert.Equal(t, tc.err, err)
+	}
+}
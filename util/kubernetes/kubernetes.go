@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	agentpayload "github.com/DataDog/agent-payload/gogen"
@@ -65,6 +66,15 @@ func GetMetadata() *agentpayload.KubeMetadataPayload {
 	return nil
 }
 
+// GetNodeName returns the name of the node this agent is running on, as
+// reported by the local kubelet.
+func GetNodeName() (string, error) {
+	if globalKubeUtil == nil {
+		return "", ErrKubernetesNotAvailable
+	}
+	return globalKubeUtil.getNodeName()
+}
+
 // IsKubernetes returns true if we're running inside a Kubernetes container.
 func IsKubernetes() bool {
 	return os.Getenv("KUBERNETES_SERVICE_HOST") != ""
@@ -81,6 +91,11 @@ const (
 	kindReplicationController = "ReplicationController"
 	kindDeployment            = "Deployment"
 	kindJob                   = "Job"
+
+	// Pod labels set by the kubelet on the containers.
+	podNameLabel      = "io.kubernetes.pod.name"
+	podNamespaceLabel = "io.kubernetes.pod.namespace"
+	podUIDLabel       = "io.kubernetes.pod.uid"
 )
 
 // Pod contains fields for unmarshalling a Pod
@@ -99,6 +114,7 @@ type PodList struct {
 type PodSpec struct {
 	HostNetwork bool   `json:"hostNetwork,omitempty"`
 	Hostname    string `json:"hostname,omitempty"`
+	NodeName    string `json:"nodeName,omitempty"`
 }
 
 // PodStatus contains fields for unmarshalling a PodStatus
@@ -139,6 +155,33 @@ type OwnerReference struct {
 type kubeUtil struct {
 	cfg         *Config
 	lastKubeErr string
+	// nodeName is shared by the checks so it's guarded by the mutex
+	nodeName string
+	sync.Mutex
+}
+
+// getNodeName returns the node name from the pods scheduled by the local
+// kubelet. A node never gets renamed so the first name found is kept.
+func (ku *kubeUtil) getNodeName() (string, error) {
+	ku.Lock()
+	nodeName := ku.nodeName
+	ku.Unlock()
+	if nodeName != "" {
+		return nodeName, nil
+	}
+	pods, err := ku.getLocalPodList()
+	if err != nil {
+		return "", err
+	}
+	for _, p := range pods {
+		if p.Spec.NodeName != "" {
+			ku.Lock()
+			ku.nodeName = p.Spec.NodeName
+			ku.Unlock()
+			return p.Spec.NodeName, nil
+		}
+	}
+	return "", errors.New("no node name found in the kubelet pod list")
 }
 
 // GetKubernetesMeta returns a Kubernetes metadata payload using a mix of state from the
@@ -270,6 +313,43 @@ func setPodCreator(pod *agentpayload.KubeMetadataPayload_Pod, ownerRefs []*Owner
 	}
 }
 
+// PodForContainer returns the name, namespace and UID of the pod running the
+// container. The pod is identified from the labels set by the kubelet on the
+// containers it runs and completed with the pods from the metadata, e.g. for
+// the UID which isn't set by older kubelets. Containers that don't belong to
+// a pod get empty strings.
+func PodForContainer(kubeMeta *agentpayload.KubeMetadataPayload, ctr *docker.Container) (name, namespace, uid string) {
+	name = ctr.Labels[podNameLabel]
+	namespace = ctr.Labels[podNamespaceLabel]
+	uid = ctr.Labels[podUIDLabel]
+	if kubeMeta == nil || (name != "" && namespace != "" && uid != "") {
+		return name, namespace, uid
+	}
+
+	for _, p := range kubeMeta.Pods {
+		if name != "" {
+			if p.Name != name || p.Namespace != namespace {
+				continue
+			}
+		} else if !hasContainerID(p.ContainerIds, ctr.ID) {
+			continue
+		}
+		return p.Name, p.Namespace, p.Uid
+	}
+	return name, namespace, uid
+}
+
+// hasContainerID returns true if id is in the container ids reported by the
+// kubelet which are prefixed by the runtime, e.g. "docker://<id>".
+func hasContainerID(containerIDs []string, id string) bool {
+	for _, cid := range containerIDs {
+		if strings.HasSuffix(cid, "://"+id) {
+			return true
+		}
+	}
+	return false
+}
+
 // Try and find the hostname to query the kubelet
 func locateKubelet(cfg *Config) (string, error) {
 	var err error
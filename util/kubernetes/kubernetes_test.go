@@ -0,0 +1,117 @@
+package kubernetes
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	agentpayload "github.com/DataDog/agent-payload/gogen"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-process-agent/util/docker"
+)
+
+func TestPodForContainer(t *testing.T) {
+	kubeMeta := &agentpayload.KubeMetadataPayload{
+		Pods: []*agentpayload.KubeMetadataPayload_Pod{
+			{Name: "redis-0", Namespace: "default", Uid: "uid-1", ContainerIds: []string{"docker://abc"}},
+			{Name: "web-1", Namespace: "prod", Uid: "uid-2", ContainerIds: []string{"docker://def"}},
+		},
+	}
+
+	for i, tc := range []struct {
+		meta                *agentpayload.KubeMetadataPayload
+		ctr                 *docker.Container
+		name, namespace, id string
+	}{
+		// All labels are set by recent kubelets.
+		{
+			meta: kubeMeta,
+			ctr: &docker.Container{ID: "xyz", Labels: map[string]string{
+				podNameLabel:      "other",
+				podNamespaceLabel: "kube-system",
+				podUIDLabel:       "uid-3",
+			}},
+			name: "other", namespace: "kube-system", id: "uid-3",
+		},
+		// The UID is resolved with the metadata.
+		{
+			meta: kubeMeta,
+			ctr: &docker.Container{ID: "xyz", Labels: map[string]string{
+				podNameLabel:      "web-1",
+				podNamespaceLabel: "prod",
+			}},
+			name: "web-1", namespace: "prod", id: "uid-2",
+		},
+		// Without labels we fall back to the container ids of the pods.
+		{
+			meta: kubeMeta,
+			ctr:  &docker.Container{ID: "abc"},
+			name: "redis-0", namespace: "default", id: "uid-1",
+		},
+		// Containers outside of pods.
+		{
+			meta: kubeMeta,
+			ctr:  &docker.Container{ID: "123"},
+		},
+		{
+			ctr: &docker.Container{ID: "abc"},
+		},
+	} {
+		name, namespace, uid := PodForContainer(tc.meta, tc.ctr)
+		assert.Equal(t, tc.name, name, "name test %d", i)
+		assert.Equal(t, tc.namespace, namespace, "namespace test %d", i)
+		assert.Equal(t, tc.id, uid, "uid test %d", i)
+	}
+}
+
+func TestGetNodeName(t *testing.T) {
+	assert := assert.New(t)
+
+	queries := 0
+	var mu sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries++
+		mu.Unlock()
+		if r.URL.Path != "/pods" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"items":[{"metadata":{"name":"static"},"spec":{}},{"metadata":{"name":"web-1"},"spec":{"nodeName":"node-7"}}]}`)
+	}))
+	defer ts.Close()
+
+	ku := &kubeUtil{cfg: &Config{kubeletAPIURL: ts.URL}}
+	// The checks look the node name up concurrently.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name, err := ku.getNodeName()
+			assert.NoError(err)
+			assert.Equal("node-7", name)
+		}()
+	}
+	wg.Wait()
+
+	// The name is kept once found.
+	mu.Lock()
+	before := queries
+	mu.Unlock()
+	name, err := ku.getNodeName()
+	assert.NoError(err)
+	assert.Equal("node-7", name)
+	assert.Equal(before, queries)
+
+	// Without a scheduled pod there is no name.
+	empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items":[]}`)
+	}))
+	defer empty.Close()
+	_, err = (&kubeUtil{cfg: &Config{kubeletAPIURL: empty.URL}}).getNodeName()
+	assert.Error(err)
+}